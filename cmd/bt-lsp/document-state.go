@@ -1,7 +1,12 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"net/url"
+	"os"
+	"path/filepath"
 
 	"github.com/brahms116/between/internal/parser"
 	"github.com/brahms116/between/internal/st"
@@ -10,18 +15,37 @@ import (
 
 type documentState struct {
 	logger      *log.Logger
+	path        string
 	text        string
 	syntaxTree  []st.Definition
 	diagnostics []Diagnostic
 }
 
-func newDocumentState(text string, logger *log.Logger) documentState {
+func newDocumentState(uri string, text string, logger *log.Logger) documentState {
 	ds := documentState{}
 	ds.logger = logger
+	ds.path = uriToPath(uri)
 	ds.updateText(text)
 	return ds
 }
 
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.Clean(u.Path)
+}
+
+// readFile reads the files imported by the document from disk, and the document
+// itself from its unsaved text.
+func (ds *documentState) readFile(path string) ([]byte, error) {
+	if path == ds.path {
+		return []byte(ds.text), nil
+	}
+	return os.ReadFile(path)
+}
+
 func (ds *documentState) updateText(text string) {
 	ds.text = text
 	tree, errs := parser.LexAndParse(text)
@@ -30,6 +54,84 @@ func (ds *documentState) updateText(text string) {
 		ds.diagnostics = errorsToDiagnostics(errs)
 		return
 	}
-	_, _, errs = translate.Translate(tree)
-	ds.diagnostics = errorsToDiagnostics(errs)
+	files, parseErrs := parser.LexAndParseFiles(ds.path, ds.readFile)
+	// The errors are in imported files, they are reported on the imports which
+	// lead to them and in full when those files are opened. The definitions
+	// which could be parsed are still translated.
+	importDiagnostics := ds.importDiagnostics(files, parseErrs)
+	_, _, errs = translate.TranslateFiles(ds.path, files, translate.Options{})
+	ds.diagnostics = append(errorsToDiagnostics(errorsInFile(errs, ds.path)), importDiagnostics...)
+}
+
+// importDiagnostics reports the imports of the document which lead, directly or
+// through other imports, to files which cannot be parsed.
+func (ds *documentState) importDiagnostics(files map[string][]st.Definition, parseErrs []error) []Diagnostic {
+	failed := make(map[string]error)
+	var failedPaths []string
+	for _, err := range parseErrs {
+		var fileErr parser.FileError
+		if !errors.As(err, &fileErr) {
+			continue
+		}
+		if _, ok := failed[fileErr.Path]; !ok {
+			failed[fileErr.Path] = fileErr.Err
+			failedPaths = append(failedPaths, fileErr.Path)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	ds.logger.Printf("Failed to parse imports of %s: %v", ds.path, parseErrs)
+
+	var diagnostics []Diagnostic
+	for _, d := range ds.syntaxTree {
+		if d.Import == nil || d.Import.Path.IsErr {
+			continue
+		}
+		reachable := importedFiles(files, d.Import.ResolvePath(ds.path))
+		for _, path := range failedPaths {
+			if _, ok := reachable[path]; !ok {
+				continue
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Range:    lexLocationToLspRange(d.Import.Path.Loc),
+				Severity: &DiagnosticSeverityError,
+				Message:  fmt.Sprintf("Imported file %s cannot be parsed: %s", path, failed[path].Error()),
+			})
+		}
+	}
+	return diagnostics
+}
+
+// importedFiles returns the file at path and the files it imports, directly or
+// through other imports.
+func importedFiles(files map[string][]st.Definition, path string) map[string]struct{} {
+	seen := make(map[string]struct{})
+	queue := []string{path}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		if _, ok := seen[path]; ok {
+			continue
+		}
+		seen[path] = struct{}{}
+		for _, d := range files[path] {
+			if d.Import != nil && !d.Import.Path.IsErr {
+				queue = append(queue, d.Import.ResolvePath(path))
+			}
+		}
+	}
+	return seen
+}
+
+// errorsInFile filters translation errors down to the ones in the file at path.
+func errorsInFile(errs []error, path string) []error {
+	var res []error
+	for _, err := range errs {
+		if e, ok := err.(translate.TypeError); ok && e.Path != path {
+			continue
+		}
+		res = append(res, err)
+	}
+	return res
 }
@@ -19,9 +19,13 @@ func errorsToDiagnostics(errs []error) []Diagnostic {
 func errorToDiagnostic(err error) *Diagnostic {
 	switch e := err.(type) {
 	case translate.TypeError:
+		severity := &DiagnosticSeverityError
+		if e.Severity == translate.SeverityWarning {
+			severity = &DiagnosticSeverityWarning
+		}
 		return &Diagnostic{
 			Range:    lexLocationToLspRange(e.Location),
-			Severity: &DiagnosticSeverityError,
+			Severity: severity,
 			Message:  e.LspMessage(),
 		}
 	case parser.UnexpectedTokenError:
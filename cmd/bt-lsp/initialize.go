@@ -13,6 +13,10 @@ func newInitializeResponse() InitializeResult {
             SEMTOK_PROPERTY,
             SEMTOK_STRING,
             SEMTOK_ENUM_MEMBER,
+            SEMTOK_COMMENT,
+            SEMTOK_TYPE_PARAM,
+            SEMTOK_NUMBER,
+            SEMTOK_DECORATOR,
 					},
 				},
 				Full: &semanticTokensSyncFull,
@@ -59,26 +59,76 @@ func (t *treeToSemanticTokens) convertDefinitions(ds []st.Definition) {
 }
 
 func (t *treeToSemanticTokens) convertDefinition(d st.Definition) {
-	if d.Product != nil {
+	if d.Import != nil {
+		t.convertImport(*d.Import)
+	} else if d.Product != nil {
 		t.convertProduct(*d.Product)
 	} else if d.Sum != nil {
 		t.convertSum(*d.Sum)
 	} else if d.SumStr != nil {
 		t.convertSumStr(*d.SumStr)
+	} else if d.SumInt != nil {
+		t.convertSumInt(*d.SumInt)
+	} else if d.Alias != nil {
+		t.convertDoc(d.Alias.Doc)
+		t.addSemanticToken(SEMTOK_KEYWORD_INDEX, d.Alias.Keyword)
+		t.addSemanticToken(SEMTOK_CLASS_INDEX, d.Alias.Id)
+		t.convertType(d.Alias.Type)
+		t.convertAttributes(d.Alias.Attributes)
+	} else if d.NewType != nil {
+		t.convertDoc(d.NewType.Doc)
+		t.addSemanticToken(SEMTOK_KEYWORD_INDEX, d.NewType.Keyword)
+		t.addSemanticToken(SEMTOK_CLASS_INDEX, d.NewType.Id)
+		t.convertType(d.NewType.Type)
+		t.convertAttributes(d.NewType.Attributes)
+	} else if d.Extern != nil {
+		t.convertExtern(*d.Extern)
+	}
+}
+
+func (t *treeToSemanticTokens) convertExtern(e st.Extern) {
+	t.convertDoc(e.Doc)
+	t.addSemanticToken(SEMTOK_KEYWORD_INDEX, e.Keyword)
+	t.addSemanticToken(SEMTOK_CLASS_INDEX, e.Id)
+	t.convertAttributes(e.Attributes)
+	for _, m := range e.Mappings {
+		t.addSemanticToken(SEMTOK_PROPERTY_INDEX, m.Target)
+		t.addSemanticToken(SEMTOK_STRING_INDEX, m.Type)
+	}
+}
+
+func (t *treeToSemanticTokens) convertImport(i st.Import) {
+	t.addSemanticToken(SEMTOK_KEYWORD_INDEX, i.Keyword)
+	t.addSemanticToken(SEMTOK_STRING_INDEX, i.Path)
+}
+
+func (t *treeToSemanticTokens) convertDoc(doc []lex.Token) {
+	for _, d := range doc {
+		t.addSemanticToken(SEMTOK_COMMENT_INDEX, d)
 	}
 }
 
 func (t *treeToSemanticTokens) convertSum(s st.Sum) {
+	t.convertDoc(s.Doc)
 	t.addSemanticToken(SEMTOK_KEYWORD_INDEX, s.Keyword)
 	t.addSemanticToken(SEMTOK_CLASS_INDEX, s.Id)
+	t.convertTypeParams(s.TypeParams)
+	t.convertAttributes(s.Attributes)
 	for _, v := range s.Variants {
 		t.convertField(v)
 	}
 }
 
 func (t *treeToSemanticTokens) convertProduct(p st.Product) {
+	t.convertDoc(p.Doc)
 	t.addSemanticToken(SEMTOK_KEYWORD_INDEX, p.Keyword)
 	t.addSemanticToken(SEMTOK_CLASS_INDEX, p.Id)
+	t.convertTypeParams(p.TypeParams)
+	if p.Extends != nil {
+		t.addSemanticToken(SEMTOK_KEYWORD_INDEX, p.Extends.Keyword)
+		t.convertType(st.Type{TypeIdent: &p.Extends.Type})
+	}
+	t.convertAttributes(p.Attributes)
 	for _, f := range p.Fields {
 		t.convertField(f)
 	}
@@ -86,35 +136,123 @@ func (t *treeToSemanticTokens) convertProduct(p st.Product) {
 
 func (t *treeToSemanticTokens) convertField(f st.Field) {
 	if f.FieldFull != nil {
+		t.convertDoc(f.FieldFull.Doc)
 		t.addSemanticToken(SEMTOK_PROPERTY_INDEX, f.FieldFull.Id)
 		if f.FieldFull.JsonName != nil {
 			t.addSemanticToken(SEMTOK_STRING_INDEX, *f.FieldFull.JsonName)
 		}
 		t.convertType(f.FieldFull.Type)
+		t.convertNull(f.FieldFull.Null)
+		t.convertDefault(f.FieldFull.Default)
+		t.convertAttributes(f.FieldFull.Attributes)
 	} else if f.FieldShort != nil {
+		t.convertDoc(f.FieldShort.Doc)
 		t.addSemanticToken(SEMTOK_CLASS_INDEX, f.FieldShort.Id)
+		t.convertNull(f.FieldShort.Null)
+		t.convertDefault(f.FieldShort.Default)
+		t.convertAttributes(f.FieldShort.Attributes)
+	}
+}
+
+func (t *treeToSemanticTokens) convertNull(n *st.Null) {
+	if n == nil {
+		return
+	}
+	t.addSemanticToken(SEMTOK_KEYWORD_INDEX, n.Null)
+}
+
+func (t *treeToSemanticTokens) convertDefault(d *st.Default) {
+	if d == nil || d.Value.IsErr {
+		return
+	}
+	switch d.Value.Type {
+	case lex.TOKEN_NUMBER:
+		t.addSemanticToken(SEMTOK_NUMBER_INDEX, d.Value)
+	case lex.TOKEN_LITERAL:
+		t.addSemanticToken(SEMTOK_STRING_INDEX, d.Value)
+	case lex.TOKEN_BOOL:
+		t.addSemanticToken(SEMTOK_KEYWORD_INDEX, d.Value)
+	case lex.TOKEN_ID:
+		t.addSemanticToken(SEMTOK_ENUM_MEMBER_INDEX, d.Value)
+	}
+}
+
+func (t *treeToSemanticTokens) convertAttributes(attributes []st.Attribute) {
+	for _, a := range attributes {
+		t.addSemanticToken(SEMTOK_DECORATOR_INDEX, a.At)
+		t.addSemanticToken(SEMTOK_DECORATOR_INDEX, a.Name)
+		for _, arg := range a.Args {
+			if arg.Name != nil {
+				t.addSemanticToken(SEMTOK_PROPERTY_INDEX, *arg.Name)
+			}
+			if arg.Value.Type == lex.TOKEN_NUMBER {
+				t.addSemanticToken(SEMTOK_NUMBER_INDEX, arg.Value)
+			} else {
+				t.addSemanticToken(SEMTOK_STRING_INDEX, arg.Value)
+			}
+		}
 	}
 }
 
 func (t *treeToSemanticTokens) convertType(ty st.Type) {
 	if ty.List != nil {
 		t.convertType(ty.List.Type)
+	} else if ty.Map != nil {
+		t.convertType(ty.Map.Key)
+		t.convertType(ty.Map.Value)
 	} else if ty.TypeIdent != nil {
 		t.addSemanticToken(SEMTOK_CLASS_INDEX, ty.TypeIdent.Id)
+		if ty.TypeIdent.TypeArgs != nil {
+			for _, arg := range ty.TypeIdent.TypeArgs.Args {
+				t.convertType(arg)
+			}
+		}
+	}
+}
+
+func (t *treeToSemanticTokens) convertTypeParams(tp *st.TypeParams) {
+	if tp == nil {
+		return
+	}
+	for _, param := range tp.Params {
+		t.addSemanticToken(SEMTOK_TYPE_PARAM_INDEX, param)
 	}
 }
 
 func (t *treeToSemanticTokens) convertSumStr(ss st.SumStr) {
+	t.convertDoc(ss.Doc)
 	t.addSemanticToken(SEMTOK_KEYWORD_INDEX, ss.Keyword)
 	t.addSemanticToken(SEMTOK_CLASS_INDEX, ss.Id)
+	t.convertAttributes(ss.Attributes)
 	for _, v := range ss.Variants {
 		t.convertSumStrVariant(v)
 	}
 }
 
 func (t *treeToSemanticTokens) convertSumStrVariant(sv st.SumStrVariant) {
+	t.convertDoc(sv.Doc)
 	t.addSemanticToken(SEMTOK_ENUM_MEMBER_INDEX, sv.Id)
 	if sv.JsonName != nil {
 		t.addSemanticToken(SEMTOK_STRING_INDEX, *sv.JsonName)
 	}
+	t.convertAttributes(sv.Attributes)
+}
+
+func (t *treeToSemanticTokens) convertSumInt(si st.SumInt) {
+	t.convertDoc(si.Doc)
+	t.addSemanticToken(SEMTOK_KEYWORD_INDEX, si.Keyword)
+	t.addSemanticToken(SEMTOK_CLASS_INDEX, si.Id)
+	t.convertAttributes(si.Attributes)
+	for _, v := range si.Variants {
+		t.convertSumIntVariant(v)
+	}
+}
+
+func (t *treeToSemanticTokens) convertSumIntVariant(sv st.SumIntVariant) {
+	t.convertDoc(sv.Doc)
+	t.addSemanticToken(SEMTOK_ENUM_MEMBER_INDEX, sv.Id)
+	if sv.Value != nil {
+		t.addSemanticToken(SEMTOK_NUMBER_INDEX, *sv.Value)
+	}
+	t.convertAttributes(sv.Attributes)
 }
@@ -6,6 +6,10 @@ const (
 	SEMTOK_PROPERTY    = "property"
 	SEMTOK_STRING      = "string"
 	SEMTOK_ENUM_MEMBER = "enumMember"
+	SEMTOK_COMMENT     = "comment"
+	SEMTOK_TYPE_PARAM  = "typeParameter"
+	SEMTOK_NUMBER      = "number"
+	SEMTOK_DECORATOR   = "decorator"
 )
 
 const (
@@ -14,4 +18,8 @@ const (
 	SEMTOK_PROPERTY_INDEX
 	SEMTOK_STRING_INDEX
 	SEMTOK_ENUM_MEMBER_INDEX
+	SEMTOK_COMMENT_INDEX
+	SEMTOK_TYPE_PARAM_INDEX
+	SEMTOK_NUMBER_INDEX
+	SEMTOK_DECORATOR_INDEX
 )
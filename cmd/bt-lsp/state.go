@@ -29,7 +29,7 @@ func newLspState(logger *log.Logger) *lspState {
 }
 
 func (s *lspState) addDocument(uri string, text string) {
-	ds := newDocumentState(text, s.logger)
+	ds := newDocumentState(uri, text, s.logger)
 	s.documents[uri] = ds
 }
 
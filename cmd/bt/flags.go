@@ -8,19 +8,35 @@ import (
 type flags struct {
 	inputFileLocation  string
 	outputFileLocation string
+	outputDirLocation  string
+	outputExtension    string
 	goPackageName      string
+	jvmPackageName     string
+	csNamespace        string
+	warningsAsErrors   bool
 }
 
 func newFlags() (flags, error) {
 	f := flags{}
 
 	flag.StringVar(&f.inputFileLocation, "input", "", "path to the input file: e.g. ./input.bt")
-	flag.StringVar(&f.outputFileLocation, "output", "", "path to the output file: e.g. ./output.go")
-	flag.StringVar(&f.goPackageName, "go-package-name", "", "used when output is a golang file, specifies the package name for the generated go file, defaults to the name of the output file, e.g. mypackage.go will be mypackage")
+	flag.StringVar(&f.outputFileLocation, "output", "", "path to the output file: e.g. ./output.go, the input file and every file it imports are generated into it")
+	flag.StringVar(&f.outputDirLocation, "output-dir", "", "path to a directory to generate one output file per source file into, instead of a single --output file: e.g. ./generated")
+	flag.StringVar(&f.outputExtension, "output-ext", "", "used with --output-dir, the extension of the generated files which selects the output language: e.g. ts")
+	flag.StringVar(&f.goPackageName, "go-package-name", "", "used when output is a golang file, specifies the package name for the generated go file, defaults to the name of the output file, e.g. mypackage.go will be mypackage, or the name of the output directory")
+	flag.StringVar(&f.jvmPackageName, "jvm-package-name", "", "used when output is a kotlin or java file, specifies the package of the generated code, defaults to no package for an --output file, or the name of the output directory followed by the subdirectories of each file, e.g. com.example.api")
+	flag.StringVar(&f.csNamespace, "cs-namespace", "", "used when output is a c# file, specifies the namespace of the generated code, defaults to the global namespace for an --output file, or the name of the output directory, e.g. Example.Api")
+	flag.BoolVar(&f.warningsAsErrors, "warnings-as-errors", false, "fail on warnings, such as names which are not camelCase or PascalCase, instead of only printing them")
 	flag.Parse()
 
-	if f.outputFileLocation == "" {
-		return f, fmt.Errorf("--output is required")
+	if f.outputFileLocation == "" && f.outputDirLocation == "" {
+		return f, fmt.Errorf("--output or --output-dir is required")
+	}
+	if f.outputFileLocation != "" && f.outputDirLocation != "" {
+		return f, fmt.Errorf("only one of --output or --output-dir can be used")
+	}
+	if f.outputDirLocation != "" && f.outputExtension == "" {
+		return f, fmt.Errorf("--output-ext is required when using --output-dir")
 	}
 	if f.inputFileLocation == "" {
 		return f, fmt.Errorf("--input is required")
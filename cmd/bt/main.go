@@ -3,8 +3,10 @@ package main
 import (
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/brahms116/between/internal/ast"
 	"github.com/brahms116/between/internal/generator"
 	"github.com/brahms116/between/internal/parser"
 	"github.com/brahms116/between/internal/translate"
@@ -15,11 +17,35 @@ type OutputFormat string
 const (
 	TypescriptOut OutputFormat = "Typescript"
 	GolangOut     OutputFormat = "Golang"
+	RustOut       OutputFormat = "Rust"
+	PythonOut     OutputFormat = "Python"
+	KotlinOut     OutputFormat = "Kotlin"
+	JavaOut       OutputFormat = "Java"
+	SwiftOut      OutputFormat = "Swift"
+	CSharpOut     OutputFormat = "CSharp"
 )
 
 var extentionOutputMap map[string]OutputFormat = map[string]OutputFormat{
-	"ts": TypescriptOut,
-	"go": GolangOut,
+	"ts":    TypescriptOut,
+	"go":    GolangOut,
+	"rs":    RustOut,
+	"py":    PythonOut,
+	"kt":    KotlinOut,
+	"java":  JavaOut,
+	"swift": SwiftOut,
+	"cs":    CSharpOut,
+}
+
+// outputTargets are the names of the output formats in externs
+var outputTargets map[OutputFormat]string = map[OutputFormat]string{
+	TypescriptOut: "ts",
+	GolangOut:     "go",
+	RustOut:       "rs",
+	PythonOut:     "py",
+	KotlinOut:     "kt",
+	JavaOut:       "java",
+	SwiftOut:      "swift",
+	CSharpOut:     "cs",
 }
 
 func parseOutputFileDetails(outputFileLocation string) (filename string, format OutputFormat) {
@@ -43,29 +69,70 @@ func main() {
 		log.Panic(err)
 	}
 
-	fileName, outputFormat := parseOutputFileDetails(args.outputFileLocation)
-
-	input, err := os.ReadFile(args.inputFileLocation)
-	if err != nil {
-		log.Panic(err)
+	var fileName string
+	var outputFormat OutputFormat
+	if args.outputDirLocation != "" {
+		var ok bool
+		outputFormat, ok = extentionOutputMap[args.outputExtension]
+		if !ok {
+			log.Panic("Unsupported output format")
+		}
+	} else {
+		fileName, outputFormat = parseOutputFileDetails(args.outputFileLocation)
 	}
 
-	st, errs := parser.LexAndParse(string(input))
-	definitions, primitives, errs := translate.Translate(st)
+	entry := filepath.Clean(args.inputFileLocation)
+	sources, errs := parser.LexAndParseFiles(entry, os.ReadFile)
 	if len(errs) > 0 {
 		log.Panic(errs[0])
 	}
+	files, primitives, errs := translate.TranslateFiles(entry, sources, translate.Options{
+		Targets: []string{outputTargets[outputFormat]},
+	})
+	reportTypeErrors(errs, args.warningsAsErrors)
+
+	if args.outputDirLocation != "" {
+		writeOutputPerSource(args, entry, files, outputFormat)
+		return
+	}
 
 	var output string
 	switch outputFormat {
 	case TypescriptOut:
-		output = generator.PrintTsDefinitions(definitions)
+		output = generator.PrintTsDefinitions(ast.Definitions(files))
 	case GolangOut:
 		goPackageName := args.goPackageName
 		if goPackageName == "" {
 			goPackageName = fileName
 		}
-		output = generator.PrintGoDefinitions(definitions, primitives, generator.GoGeneratorOptions{PackageName: goPackageName})
+		output = generator.PrintGoDefinitions(ast.Definitions(files), primitives, generator.GoGeneratorOptions{PackageName: goPackageName})
+	case RustOut:
+		output = generator.PrintRustDefinitions(ast.Definitions(files), generator.RustGeneratorOptions{Definitions: ast.Definitions(files)})
+	case PythonOut:
+		output = generator.PrintPyDefinitions(ast.Definitions(files))
+	case KotlinOut:
+		output = generator.PrintKotlinDefinitions(ast.Definitions(files), generator.KotlinGeneratorOptions{
+			PackageName: args.jvmPackageName,
+			Definitions: ast.Definitions(files),
+		})
+	case JavaOut:
+		// A Java file holds a class of the same name
+		className := javaClassName(args.outputFileLocation)
+		if err := generator.CheckJavaClassName(className, ast.Definitions(files)); err != nil {
+			log.Fatal(err)
+		}
+		output = generator.PrintJavaDefinitions(ast.Definitions(files), generator.JavaGeneratorOptions{
+			PackageName: args.jvmPackageName,
+			ClassName:   className,
+			Definitions: ast.Definitions(files),
+		})
+	case SwiftOut:
+		output = generator.PrintSwiftDefinitions(ast.Definitions(files), generator.SwiftGeneratorOptions{Definitions: ast.Definitions(files)})
+	case CSharpOut:
+		output = generator.PrintCSharpDefinitions(ast.Definitions(files), generator.CSharpGeneratorOptions{
+			Namespace:   args.csNamespace,
+			Definitions: ast.Definitions(files),
+		})
 	}
 
 	err = os.WriteFile(args.outputFileLocation, []byte(output), 0644)
@@ -73,3 +140,183 @@ func main() {
 		log.Panic(err)
 	}
 }
+
+// reportTypeErrors prints the errors and warnings of a translation, exiting
+// when there are errors, or warnings and they are treated as errors.
+func reportTypeErrors(errs []error, warningsAsErrors bool) {
+	failed := false
+	for _, err := range errs {
+		log.Print(err)
+		if !translate.IsWarning(err) || warningsAsErrors {
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// writeOutputPerSource generates one output file per source file into the
+// output directory, mirroring the layout of the sources relative to the input
+// file. The output paths are checked before anything is written, so an error
+// leaves no partial output behind.
+func writeOutputPerSource(args flags, entry string, files []ast.File, outputFormat OutputFormat) {
+	goPackageName := args.goPackageName
+	if goPackageName == "" {
+		outputDir, err := filepath.Abs(args.outputDirLocation)
+		if err != nil {
+			log.Panic(err)
+		}
+		goPackageName = filepath.Base(outputDir)
+	}
+	jvmPackageName := args.jvmPackageName
+	if jvmPackageName == "" {
+		outputDir, err := filepath.Abs(args.outputDirLocation)
+		if err != nil {
+			log.Panic(err)
+		}
+		jvmPackageName = filepath.Base(outputDir)
+	}
+	csNamespace := args.csNamespace
+	if csNamespace == "" {
+		outputDir, err := filepath.Abs(args.outputDirLocation)
+		if err != nil {
+			log.Panic(err)
+		}
+		csNamespace = filepath.Base(outputDir)
+	}
+
+	outputPaths := make(map[string]string)
+	for _, f := range files {
+		rel, err := filepath.Rel(filepath.Dir(entry), f.Path)
+		if err != nil {
+			log.Panic(err)
+		}
+		if strings.HasPrefix(rel, "..") {
+			log.Fatalf("%s is outside the directory of the input file", f.Path)
+		}
+		// Go files share a package, so they have to share a directory
+		if outputFormat == GolangOut && filepath.Dir(rel) != "." {
+			log.Fatalf("%s must be in the directory of the input file to be generated into the same go package", f.Path)
+		}
+		outputPaths[f.Path] = strings.TrimSuffix(rel, filepath.Ext(rel)) + "." + args.outputExtension
+		if outputFormat == JavaOut {
+			if err := generator.CheckJavaClassName(javaClassName(outputPaths[f.Path]), f.Definitions); err != nil {
+				log.Fatalf("%s: %s", f.Path, err)
+			}
+		}
+	}
+	// Kotlin and Java files are in the package of the output directory, followed
+	// by the subdirectories they are in
+	jvmPackages := make(map[string]string)
+	jvmClasses := make(map[string]string)
+	for path, rel := range outputPaths {
+		pkg := jvmPackageName
+		if dir := filepath.Dir(rel); dir != "." {
+			pkg += "." + strings.ReplaceAll(filepath.ToSlash(dir), "/", ".")
+		}
+		jvmPackages[path] = pkg
+		jvmClasses[path] = pkg + "." + javaClassName(rel)
+	}
+
+	declaredHelpers := make(map[string]struct{})
+	declaredKotlinHelpers := make(map[string]map[string]struct{})
+	outputs := make(map[string]string)
+	for _, f := range files {
+		rel := outputPaths[f.Path]
+
+		var output string
+		switch outputFormat {
+		case TypescriptOut:
+			output = generator.PrintTsFile(f)
+		case GolangOut:
+			output = generator.PrintGoDefinitions(f.Definitions, f.UsedPrimitiveTypes, generator.GoGeneratorOptions{
+				PackageName:     goPackageName,
+				DeclaredHelpers: declaredHelpers,
+			})
+			// The files share a package, so only the first which needs a
+			// helper type declares it
+			for _, helper := range generator.GoHelpers(f.Definitions, f.UsedPrimitiveTypes) {
+				declaredHelpers[helper] = struct{}{}
+			}
+		case RustOut:
+			output = generator.PrintRustFile(f, generator.RustGeneratorOptions{Definitions: ast.Definitions(files)})
+		case PythonOut:
+			output = generator.PrintPyFile(f)
+		case KotlinOut:
+			pkg := jvmPackages[f.Path]
+			if declaredKotlinHelpers[pkg] == nil {
+				declaredKotlinHelpers[pkg] = make(map[string]struct{})
+			}
+			options := generator.KotlinGeneratorOptions{
+				PackageName:     pkg,
+				Packages:        jvmPackages,
+				Definitions:     ast.Definitions(files),
+				DeclaredHelpers: declaredKotlinHelpers[pkg],
+			}
+			output = generator.PrintKotlinFile(f, options)
+			// Only the first file of a package which needs a helper type
+			// declares it
+			for _, helper := range generator.KotlinHelpers(f.Definitions, options) {
+				declaredKotlinHelpers[pkg][helper] = struct{}{}
+			}
+		case JavaOut:
+			// Every other file is imported, as Java has no aliases and the
+			// types of aliases can come from anywhere
+			var importedClasses []string
+			for path, class := range jvmClasses {
+				if path != f.Path {
+					importedClasses = append(importedClasses, class)
+				}
+			}
+			output = generator.PrintJavaDefinitions(f.Definitions, generator.JavaGeneratorOptions{
+				PackageName:     jvmPackages[f.Path],
+				ClassName:       javaClassName(rel),
+				ImportedClasses: importedClasses,
+				Definitions:     ast.Definitions(files),
+			})
+		case SwiftOut:
+			// The files are in one module, so only the first which needs a
+			// helper type declares it
+			options := generator.SwiftGeneratorOptions{
+				Definitions:     ast.Definitions(files),
+				DeclaredHelpers: declaredHelpers,
+			}
+			output = generator.PrintSwiftDefinitions(f.Definitions, options)
+			for _, helper := range generator.SwiftHelpers(f.Definitions, options) {
+				declaredHelpers[helper] = struct{}{}
+			}
+		case CSharpOut:
+			// The files share a namespace, as Go files share a package, so only
+			// the first which needs a helper type declares it
+			options := generator.CSharpGeneratorOptions{
+				Namespace:       csNamespace,
+				Definitions:     ast.Definitions(files),
+				DeclaredHelpers: declaredHelpers,
+			}
+			output = generator.PrintCSharpDefinitions(f.Definitions, options)
+			for _, helper := range generator.CSharpHelpers(f.Definitions, options) {
+				declaredHelpers[helper] = struct{}{}
+			}
+		}
+
+		outputs[filepath.Join(args.outputDirLocation, rel)] = output
+	}
+
+	for outputLocation, output := range outputs {
+		err := os.MkdirAll(filepath.Dir(outputLocation), 0755)
+		if err != nil {
+			log.Panic(err)
+		}
+		err = os.WriteFile(outputLocation, []byte(output), 0644)
+		if err != nil {
+			log.Panic(err)
+		}
+	}
+}
+
+// javaClassName is the name of the class of a generated Java file, which has
+// to be the name of the file.
+func javaClassName(outputPath string) string {
+	return strings.TrimSuffix(filepath.Base(outputPath), filepath.Ext(outputPath))
+}
@@ -1,15 +1,16 @@
 package demo
 
 import (
-	"time"
+	"encoding/json"
+	"fmt"
 )
 
 type User struct {
 	Age         int       `json:"age"`
 	Name        string    `json:"$name"`
-	Email       *string   `json:"email,omitEmpty"`
-	Hobbies     *[]string `json:"hobbies,omitEmpty"`
-	DateOfBirth time.Time `json:"dateOfBirth"`
+	Email       *string   `json:"email,omitempty"`
+	Hobbies     *[]string `json:"hobbies,omitempty"`
+	DateOfBirth string    `json:"dateOfBirth"`
 	Status      Status    `json:"status"`
 	UserData    UserData  `json:"userData"`
 }
@@ -20,9 +21,85 @@ const Status_Disabled Status = "Disabled"
 const Status_Pending Status = "pending activation"
 
 type UserData struct {
-	AdminData    *AdminData    `json:"adminData,omitEmpty"`
-	CustomerData *CustomerData `json:"customerData,omitEmpty"`
+	AdminData    *AdminData    `json:"adminData,omitempty"`
+	CustomerData *CustomerData `json:"customerData,omitempty"`
 }
+
+// UserDataVariant names a variant of UserData.
+type UserDataVariant string
+
+const UserDataVariant_AdminData UserDataVariant = "adminData"
+const UserDataVariant_CustomerData UserDataVariant = "customerData"
+
+// Which returns the variant which is set, or an empty string when none is.
+func (x UserData) Which() UserDataVariant {
+	switch {
+	case x.AdminData != nil:
+		return UserDataVariant_AdminData
+	case x.CustomerData != nil:
+		return UserDataVariant_CustomerData
+	}
+	return ""
+}
+
+// NewUserDataAdminData wraps v as the AdminData variant of UserData.
+func NewUserDataAdminData(v AdminData) UserData { return UserData{AdminData: &v} }
+
+// NewUserDataCustomerData wraps v as the CustomerData variant of UserData.
+func NewUserDataCustomerData(v CustomerData) UserData { return UserData{CustomerData: &v} }
+
+// MarshalJSON encodes the variant which is set, failing unless exactly one is.
+func (x UserData) MarshalJSON() ([]byte, error) {
+	set := 0
+	if x.AdminData != nil {
+		set++
+	}
+	if x.CustomerData != nil {
+		set++
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("UserData must have exactly one variant set, got %d", set)
+	}
+	var tag string
+	var value any
+	switch {
+	case x.AdminData != nil:
+		tag, value = "adminData", x.AdminData
+	case x.CustomerData != nil:
+		tag, value = "customerData", x.CustomerData
+	}
+	return json.Marshal(map[string]any{tag: value})
+}
+
+// UnmarshalJSON decodes the variant named by the tag.
+func (x *UserData) UnmarshalJSON(b []byte) error {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(b, &object); err != nil {
+		return err
+	}
+	if len(object) != 1 {
+		return fmt.Errorf("UserData must have exactly one variant set, got %d", len(object))
+	}
+	var tagged struct {
+		Tag     string
+		Content json.RawMessage
+	}
+	for tag, content := range object {
+		tagged.Tag, tagged.Content = tag, content
+	}
+	*x = UserData{}
+	switch tagged.Tag {
+	case "adminData":
+		x.AdminData = new(AdminData)
+		return json.Unmarshal(tagged.Content, x.AdminData)
+	case "customerData":
+		x.CustomerData = new(CustomerData)
+		return json.Unmarshal(tagged.Content, x.CustomerData)
+	default:
+		return fmt.Errorf("unknown UserData variant %q", tagged.Tag)
+	}
+}
+
 type AdminData struct {
 	AccessLevel int `json:"accessLevel"`
 }
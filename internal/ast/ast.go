@@ -1,50 +1,221 @@
 package ast
 
+import "github.com/brahms116/between/internal/lex"
+
 // TODO add span location
 
 type Type struct {
 	List      *List
+	Map       *Map
 	TypeIdent *TypeIdent
 }
 
+// Optional types may be absent, `?`, and nullable types may be null, `| null`.
+// Only the types of fields can be nullable.
+
 type TypeIdent struct {
 	Id       string
+	TypeArgs []Type
+	Optional bool
 	Nullable bool
 }
 
 type List struct {
+	Optional bool
 	Nullable bool
 	Type     Type
 }
 
+type Map struct {
+	Optional bool
+	Nullable bool
+	Key      Type
+	Value    Type
+}
+
 type Field struct {
-	Id       string
-	JsonName *string
-	Type     Type
+	Doc         []string
+	Id          string
+	JsonName    *string
+	Type        Type
+	Constraints []Constraint
+	Default     *Default
+	Attributes  Attributes
+}
+
+// Default is the value a field takes when it is not given.
+type Default struct {
+	Kind DefaultKind
+	// the string, number or bool, or the JSON value of a sumstr or sumint
+	// variant
+	Value string
+	// the sumstr or sumint and the name of its variant, for variant defaults
+	Enum    string
+	Variant string
+}
+
+type DefaultKind string
+
+const (
+	DefaultString    DefaultKind = "string"
+	DefaultNumber    DefaultKind = "number"
+	DefaultBool      DefaultKind = "bool"
+	DefaultSumStr    DefaultKind = "sumStr"
+	DefaultSumInt    DefaultKind = "sumInt"
+	DefaultEmptyList DefaultKind = "emptyList"
+	DefaultEmptyMap  DefaultKind = "emptyMap"
+)
+
+// Attribute is an attribute such as @deprecated("Use id") which has been checked
+// against the known attributes.
+type Attribute struct {
+	Name     string
+	Args     []AttributeArg
+	Location lex.Location
+}
+
+type AttributeArg struct {
+	// empty for positional arguments
+	Name     string
+	Value    string
+	Location lex.Location
 }
 
+type Attributes []Attribute
+
+// Constraint restricts the values of a field, such as @min(0) or @pattern("^a").
+type Constraint struct {
+	Name ConstraintName
+	// a number, or a regular expression for patterns
+	Value string
+}
+
+type ConstraintName string
+
+const (
+	ConstraintMin       ConstraintName = "min"
+	ConstraintMax       ConstraintName = "max"
+	ConstraintMinLength ConstraintName = "minLength"
+	ConstraintMaxLength ConstraintName = "maxLength"
+	ConstraintPattern   ConstraintName = "pattern"
+	ConstraintMinItems  ConstraintName = "minItems"
+	ConstraintMaxItems  ConstraintName = "maxItems"
+)
+
 type Product struct {
-	Id     string
-	Fields []Field
+	Doc        []string
+	Id         string
+	TypeParams []string
+	// The product this product extends, if any
+	Extends *TypeIdent
+	// Fields of the products this product extends, furthest ancestor first,
+	// with their type parameters substituted
+	InheritedFields []Field
+	Fields          []Field
+	Attributes      Attributes
 }
 
 type Sum struct {
-	Id       string
-	Variants []Field
+	Doc        []string
+	Id         string
+	TypeParams []string
+	Encoding   SumEncoding
+	// Key of the variant name for internally and adjacently tagged sums
+	Tag string
+	// Key of the variant value for adjacently tagged sums
+	Content    string
+	Variants   []Field
+	Attributes Attributes
 }
 
+// SumEncoding is how the variant of a sum is represented in JSON
+type SumEncoding string
+
+const (
+	// {"adminData": {...}}
+	SumEncodingExternal SumEncoding = "external"
+	// {"type": "adminData", ...}
+	SumEncodingInternal SumEncoding = "internal"
+	// {"kind": "adminData", "data": {...}}
+	SumEncodingAdjacent SumEncoding = "adjacent"
+	// {...}, the first variant which fits
+	SumEncodingUntagged SumEncoding = "untagged"
+)
+
 type SumStr struct {
-	Id       string
-	Variants []SumStrVariant
+	Doc        []string
+	Id         string
+	Variants   []SumStrVariant
+	Attributes Attributes
 }
 
 type SumStrVariant struct {
-	Id       string
-	JsonName *string
+	Doc        []string
+	Id         string
+	JsonName   *string
+	Attributes Attributes
+}
+
+type SumInt struct {
+	Doc        []string
+	Id         string
+	Variants   []SumIntVariant
+	Attributes Attributes
+}
+
+type SumIntVariant struct {
+	Doc        []string
+	Id         string
+	Value      int64
+	Attributes Attributes
+}
+
+// Alias is another name for a type, interchangeable with it.
+type Alias struct {
+	Doc        []string
+	Id         string
+	Type       Type
+	Attributes Attributes
+}
+
+// NewType is a distinct type with the same representation as its type.
+type NewType struct {
+	Doc        []string
+	Id         string
+	Type       Type
+	Attributes Attributes
+}
+
+// Extern is a type defined outside of bt, which is the given type in each
+// target.
+type Extern struct {
+	Doc []string
+	Id  string
+	// the type in each target, such as "github.com/shopspring/decimal.Decimal"
+	// for "go"
+	Targets    map[string]string
+	Attributes Attributes
 }
 
 type Definition struct {
 	Product *Product
 	Sum     *Sum
 	SumStr  *SumStr
+	SumInt  *SumInt
+	Alias   *Alias
+	NewType *NewType
+	Extern  *Extern
+}
+
+// Import lists the identifiers a file uses from one of the files it imports.
+type Import struct {
+	Path string
+	Ids  []string
+}
+
+type File struct {
+	Path               string
+	Imports            []Import
+	Definitions        []Definition
+	UsedPrimitiveTypes map[string]struct{}
 }
@@ -1,9 +1,62 @@
 package ast
 
+func (t Type) IsOptional() bool {
+	if t.List != nil {
+		return t.List.Optional
+	}
+	if t.Map != nil {
+		return t.Map.Optional
+	}
+	return t.TypeIdent.Optional
+}
+
 func (t Type) IsNullable() bool {
 	if t.List != nil {
 		return t.List.Nullable
 	}
+	if t.Map != nil {
+		return t.Map.Nullable
+	}
 	return t.TypeIdent.Nullable
 }
 
+// Get returns the attribute with the name, if there is one.
+func (as Attributes) Get(name string) (Attribute, bool) {
+	for _, a := range as {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Attribute{}, false
+}
+
+// Arg returns the value of the argument with the name, or of the positional
+// argument at the index when name is empty.
+func (a Attribute) Arg(name string, index int) (string, bool) {
+	position := 0
+	for _, arg := range a.Args {
+		if arg.Name != name {
+			continue
+		}
+		if name != "" || position == index {
+			return arg.Value, true
+		}
+		position++
+	}
+	return "", false
+}
+
+// AllFields returns the fields the product inherits followed by its own fields.
+func (p Product) AllFields() []Field {
+	return append(append([]Field{}, p.InheritedFields...), p.Fields...)
+}
+
+// Definitions flattens the definitions of files into a single list, keeping the
+// order of the files.
+func Definitions(files []File) []Definition {
+	var definitions []Definition
+	for _, f := range files {
+		definitions = append(definitions, f.Definitions...)
+	}
+	return definitions
+}
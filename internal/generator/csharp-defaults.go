@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printDefault prints the default of a field, wrapped in the newtypes the type
+// of the field is made of.
+func (p *csharpPrinter) printDefault(f ast.Field) string {
+	newTypes, base := resolveNewTypes(p.definitions, f.Type)
+	d := *f.Default
+	var value string
+	switch d.Kind {
+	case ast.DefaultString:
+		value = printCSharpString(d.Value)
+	case ast.DefaultNumber:
+		value = d.Value
+		if base.TypeIdent != nil && base.TypeIdent.Id == "Float" {
+			value += "f"
+		}
+	case ast.DefaultBool:
+		value = d.Value
+	case ast.DefaultSumStr, ast.DefaultSumInt:
+		value = fmt.Sprintf("%s.%s", d.Enum, capitalizeHead(d.Variant))
+	case ast.DefaultEmptyList, ast.DefaultEmptyMap:
+		value = "new()"
+	default:
+		panic("Invalid default")
+	}
+	for i := len(newTypes) - 1; i >= 0; i-- {
+		value = fmt.Sprintf("new %s(%s)", newTypes[i], value)
+	}
+	return value
+}
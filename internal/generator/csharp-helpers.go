@@ -0,0 +1,88 @@
+package generator
+
+// csharpHelpers is the code of the helpers generated alongside the definitions
+// which use them.
+var csharpHelpers = map[string]string{
+	"Optional": `/// <summary>
+/// A field which can be absent, null or have a value. Absent fields are the
+/// default, which is not written.
+/// </summary>
+[JsonConverter(typeof(OptionalConverterFactory))]
+public readonly record struct Optional<T>(T Value)
+{
+    /// <summary>
+    /// Whether the field is present, with a value or null.
+    /// </summary>
+    public bool IsSet { get; } = true;
+}
+
+public sealed class OptionalConverterFactory : JsonConverterFactory
+{
+    public override bool CanConvert(Type typeToConvert) =>
+        typeToConvert.IsGenericType && typeToConvert.GetGenericTypeDefinition() == typeof(Optional<>);
+
+    public override JsonConverter CreateConverter(Type typeToConvert, JsonSerializerOptions options) =>
+        (JsonConverter)Activator.CreateInstance(typeof(OptionalConverter<>).MakeGenericType(typeToConvert.GetGenericArguments()))!;
+}
+
+public class OptionalConverter<T> : JsonConverter<Optional<T>>
+{
+    // null is read as a value rather than left to the serializer
+    public override bool HandleNull => true;
+
+    public override Optional<T> Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options) =>
+        new(JsonSerializer.Deserialize<T>(ref reader, ValueOptions(options))!);
+
+    public override void Write(Utf8JsonWriter writer, Optional<T> value, JsonSerializerOptions options) =>
+        JsonSerializer.Serialize(writer, value.Value, ValueOptions(options));
+
+    protected virtual JsonSerializerOptions ValueOptions(JsonSerializerOptions options) => options;
+}
+
+/// <summary>
+/// Reads and writes the Int64s of an Optional as strings, which
+/// [JsonNumberHandling] does not do through a converter.
+/// </summary>
+public sealed class OptionalInt64Converter<T> : OptionalConverter<T>
+{
+    private Tuple<JsonSerializerOptions, JsonSerializerOptions>? valueOptions;
+
+    protected override JsonSerializerOptions ValueOptions(JsonSerializerOptions options)
+    {
+        var cached = valueOptions;
+        if (cached == null || cached.Item1 != options)
+        {
+            cached = Tuple.Create(options, new JsonSerializerOptions(options)
+            {
+                NumberHandling = JsonNumberHandling.WriteAsString | JsonNumberHandling.AllowReadingFromString,
+            });
+            valueOptions = cached;
+        }
+        return cached.Item2;
+    }
+}
+`,
+}
+
+func (p *csharpPrinter) helpers() []string {
+	var helpers []string
+	if p.usesOptional {
+		helpers = append(helpers, "Optional")
+	}
+	return helpers
+}
+
+// printHelpers prints the helpers the definitions use, which are not declared
+// by another file of the assembly already.
+func (p *csharpPrinter) printHelpers(declaredHelpers map[string]struct{}) []string {
+	var helpersStrings []string
+	for _, helper := range p.helpers() {
+		if _, ok := declaredHelpers[helper]; !ok {
+			p.useNamespace("System")
+			p.useNamespace("System.Text.Json")
+			p.useNamespace("System.Text.Json.Serialization")
+			helpersStrings = append(helpersStrings, csharpHelpers[helper])
+		}
+	}
+	return helpersStrings
+}
@@ -0,0 +1,166 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printSum prints a sum as an abstract record with a record per variant
+// holding its value, and a converter for the encoding of the sum. Generic sums
+// get a converter factory, which creates the converter for their type
+// arguments.
+func (p *csharpPrinter) printSum(s ast.Sum) string {
+	p.useNamespace("System")
+	p.useNamespace("System.Text.Json")
+	p.useNamespace("System.Text.Json.Serialization")
+	typeParams := printCSharpTypeParams(s.TypeParams)
+	sumType := s.Id + typeParams
+
+	var variantsString string
+	var readCasesString string
+	var writeCasesString string
+	var untaggedAttemptsString string
+	for _, variant := range s.Variants {
+		className := s.Id + capitalizeHead(variant.Id)
+		variantType := className + typeParams
+		valueType := p.printType(variant.Type)
+		variantsString += "\n" + p.printDoc(variant.Doc, variant.Attributes, "") + fmt.Sprintf("public sealed record %s(%s Value) : %s;\n", variantType, valueType, sumType)
+
+		isInt64 := p.isInt64(variant.Type) && variant.Type.List == nil && variant.Type.Map == nil
+		read := fmt.Sprintf("%%s.Deserialize<%s>(options)!", valueType)
+		writeValue := "JsonSerializer.Serialize(writer, variant.Value, options);\n"
+		if isInt64 {
+			p.useNamespace("System.Globalization")
+			read = "long.Parse(%s.GetString()!, CultureInfo.InvariantCulture)"
+			writeValue = "writer.WriteStringValue(variant.Value.ToString(CultureInfo.InvariantCulture));\n"
+		}
+		key := printCSharpString(fieldJsonName(variant))
+
+		var writeString string
+		var readFrom string
+		switch s.Encoding {
+		case ast.SumEncodingExternal:
+			readFrom = "property.Value"
+			writeString = "writer.WriteStartObject();\n" +
+				fmt.Sprintf("writer.WritePropertyName(%s);\n", key) +
+				writeValue +
+				"writer.WriteEndObject();\n"
+		case ast.SumEncodingInternal:
+			readFrom = "element"
+			// The properties of the variant are written next to the tag
+			writeString = "writer.WriteStartObject();\n" +
+				fmt.Sprintf("writer.WriteString(%s, %s);\n", printCSharpString(s.Tag), key) +
+				"foreach (var property in JsonSerializer.SerializeToElement(variant.Value, options).EnumerateObject())\n" +
+				"{\n" +
+				"    property.WriteTo(writer);\n" +
+				"}\n" +
+				"writer.WriteEndObject();\n"
+		case ast.SumEncodingAdjacent:
+			readFrom = "content"
+			writeString = "writer.WriteStartObject();\n" +
+				fmt.Sprintf("writer.WriteString(%s, %s);\n", printCSharpString(s.Tag), key) +
+				fmt.Sprintf("writer.WritePropertyName(%s);\n", printCSharpString(s.Content)) +
+				writeValue +
+				"writer.WriteEndObject();\n"
+		case ast.SumEncodingUntagged:
+			writeString = writeValue
+			catchString := "catch (JsonException)"
+			if isInt64 {
+				catchString = "catch (Exception e) when (e is JsonException or InvalidOperationException or FormatException)"
+			}
+			untaggedAttemptsString += fmt.Sprintf("        try\n        {\n            return new %s(%s);\n        }\n        %s\n        {\n            // The next variant is tried\n        }\n\n", variantType, fmt.Sprintf(read, "element"), catchString)
+		}
+		if readFrom != "" {
+			readCasesString += fmt.Sprintf("            %s => new %s(%s),\n", key, variantType, fmt.Sprintf(read, readFrom))
+		}
+		writeCasesString += fmt.Sprintf("            case %s variant:\n%s                break;\n", variantType, indentCSharp(writeString, "                "))
+	}
+
+	unknownString := fmt.Sprintf("            var key => throw new JsonException($\"Unknown %s variant {key}\"),\n", s.Id)
+	var readString string
+	switch s.Encoding {
+	case ast.SumEncodingExternal:
+		p.useNamespace("System.Linq")
+		readString = fmt.Sprintf(`        if (element.ValueKind != JsonValueKind.Object || element.EnumerateObject().Count() != 1)
+        {
+            throw new JsonException(%s);
+        }
+        var property = element.EnumerateObject().First();
+        return property.Name switch
+        {
+%s%s        };
+`, printCSharpString(s.Id+" must be an object with a single key"), readCasesString, unknownString)
+	case ast.SumEncodingInternal:
+		readString = fmt.Sprintf(`        if (element.ValueKind != JsonValueKind.Object || !element.TryGetProperty(%s, out var tag) || tag.ValueKind != JsonValueKind.String)
+        {
+            throw new JsonException(%s);
+        }
+        return tag.GetString() switch
+        {
+%s%s        };
+`, printCSharpString(s.Tag), printCSharpString(s.Id+" must be an object with "+s.Tag), readCasesString, unknownString)
+	case ast.SumEncodingAdjacent:
+		readString = fmt.Sprintf(`        if (element.ValueKind != JsonValueKind.Object || !element.TryGetProperty(%s, out var tag) || tag.ValueKind != JsonValueKind.String || !element.TryGetProperty(%s, out var content))
+        {
+            throw new JsonException(%s);
+        }
+        return tag.GetString() switch
+        {
+%s%s        };
+`, printCSharpString(s.Tag), printCSharpString(s.Content), printCSharpString(s.Id+" must be an object with "+s.Tag+" and "+s.Content), readCasesString, unknownString)
+	case ast.SumEncodingUntagged:
+		// The first variant which can be read is taken
+		readString = untaggedAttemptsString + fmt.Sprintf("        throw new JsonException(%s);\n", printCSharpString(s.Id+" does not match any of its variants"))
+	}
+
+	converterType := s.Id + "Converter" + typeParams
+	converterString := fmt.Sprintf(`
+public sealed class %s : JsonConverter<%s>
+{
+    public override %s Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)
+    {
+        using var document = JsonDocument.ParseValue(ref reader);
+        var element = document.RootElement;
+%s    }
+
+    public override void Write(Utf8JsonWriter writer, %s value, JsonSerializerOptions options)
+    {
+        switch (value)
+        {
+%s            default:
+                throw new JsonException($"Unknown %s variant {value.GetType()}");
+        }
+    }
+}
+`, converterType, sumType, sumType, readString, sumType, writeCasesString, s.Id)
+
+	attributeConverter := s.Id + "Converter"
+	if len(s.TypeParams) > 0 {
+		attributeConverter = s.Id + "ConverterFactory"
+		openType := fmt.Sprintf("<%s>", strings.Repeat(",", len(s.TypeParams)-1))
+		converterString += fmt.Sprintf(`
+public sealed class %sConverterFactory : JsonConverterFactory
+{
+    public override bool CanConvert(Type typeToConvert) =>
+        typeToConvert.IsGenericType && typeToConvert.GetGenericTypeDefinition() == typeof(%s%s);
+
+    public override JsonConverter CreateConverter(Type typeToConvert, JsonSerializerOptions options) =>
+        (JsonConverter)Activator.CreateInstance(typeof(%sConverter%s).MakeGenericType(typeToConvert.GetGenericArguments()))!;
+}
+`, s.Id, s.Id, openType, s.Id, openType)
+	}
+
+	sumString := p.printDoc(s.Doc, s.Attributes, "") + fmt.Sprintf("[JsonConverter(typeof(%s))]\npublic abstract record %s;\n", attributeConverter, sumType)
+	return sumString + variantsString + converterString
+}
+
+// indentCSharp indents each line of code.
+func indentCSharp(code string, indent string) string {
+	lines := strings.Split(strings.TrimSuffix(code, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = indent + line
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,387 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// CSHARP_PRIMITIVES maps primitives to C# types. Int64s are longs written as
+// strings, and the other types which are strings in JSON are strings.
+var CSHARP_PRIMITIVES map[string]string = map[string]string{
+	"Float":    "float",
+	"Float64":  "double",
+	"Str":      "string",
+	"Bool":     "bool",
+	"Int":      "long",
+	"Int32":    "int",
+	"Int64":    "long",
+	"Any":      "JsonElement",
+	"Object":   "Dictionary<string, JsonElement>",
+	"Decimal":  "string",
+	"UUID":     "string",
+	"Bytes":    "string",
+	"Date":     "string",
+	"DateTime": "string",
+	"Duration": "string",
+}
+
+// csharpMemberNames are the members records already have, which properties
+// named like them get a trailing underscore.
+var csharpMemberNames = map[string]struct{}{
+	"EqualityContract": {}, "Equals": {}, "GetHashCode": {}, "GetType": {},
+	"ToString": {}, "PrintMembers": {}, "Deconstruct": {}, "MemberwiseClone": {},
+	"Finalize": {},
+}
+
+type CSharpGeneratorOptions struct {
+	// Namespace of the generated code, the global namespace if empty
+	Namespace string
+	// Definitions of every file being generated, to resolve the aliases and
+	// newtypes of types from other files
+	Definitions []ast.Definition
+	// Helper types that are already declared in the namespace, by another file
+	DeclaredHelpers map[string]struct{}
+}
+
+// csharpPrinter prints definitions, keeping track of what the printed code
+// uses to add the using directives and helpers it needs.
+type csharpPrinter struct {
+	definitions  map[string]ast.Definition
+	usings       map[string]struct{}
+	usesOptional bool
+}
+
+func newCSharpPrinter(options CSharpGeneratorOptions) *csharpPrinter {
+	return &csharpPrinter{
+		definitions: definitionsById(options.Definitions),
+		usings:      make(map[string]struct{}),
+	}
+}
+
+// PrintCSharpDefinitions prints the definitions as records and enums, with
+// converters for the types System.Text.Json cannot encode by itself. The files
+// of an assembly see each other's types, so they do not import anything.
+func PrintCSharpDefinitions(ds []ast.Definition, options CSharpGeneratorOptions) string {
+	p := newCSharpPrinter(options)
+	var definitionStrings []string
+	for _, d := range ds {
+		if definitionString := p.printDefinition(d); definitionString != "" {
+			definitionStrings = append(definitionStrings, definitionString)
+		}
+	}
+	definitionStrings = append(definitionStrings, p.printHelpers(options.DeclaredHelpers)...)
+
+	var usingLines []string
+	for using := range p.usings {
+		usingLines = append(usingLines, fmt.Sprintf("using %s;", using))
+	}
+	sort.Strings(usingLines)
+
+	headerString := "#nullable enable\n\n"
+	if len(usingLines) > 0 {
+		headerString += strings.Join(usingLines, "\n") + "\n\n"
+	}
+	if options.Namespace != "" {
+		headerString += fmt.Sprintf("namespace %s;\n\n", options.Namespace)
+	}
+	return headerString + strings.Join(definitionStrings, "\n")
+}
+
+// CSharpHelpers returns the helper types the definitions need.
+func CSharpHelpers(ds []ast.Definition, options CSharpGeneratorOptions) []string {
+	p := newCSharpPrinter(options)
+	for _, d := range ds {
+		p.printDefinition(d)
+	}
+	return p.helpers()
+}
+
+func (p *csharpPrinter) useNamespace(namespace string) {
+	p.usings[namespace] = struct{}{}
+}
+
+// printDefinition prints a definition. Aliases and externs are replaced by
+// their types where they are used, as C# aliases are local to a file.
+func (p *csharpPrinter) printDefinition(d ast.Definition) string {
+	if d.SumStr != nil {
+		return p.printSumStr(*d.SumStr)
+	}
+	if d.SumInt != nil {
+		return p.printSumInt(*d.SumInt)
+	}
+	if d.Alias != nil || d.Extern != nil {
+		return ""
+	}
+	if d.NewType != nil {
+		return p.printNewType(*d.NewType)
+	}
+	if d.Sum != nil {
+		return p.printSum(*d.Sum)
+	}
+	if d.Product != nil {
+		return p.printProduct(*d.Product)
+	}
+	panic("Invalid definition")
+}
+
+// printProduct prints a record with a property per field. Fields which are
+// not optional and have no default are required, unless they are deprecated, as
+// C# does not let a required property be obsolete. Fields which are optional and
+// nullable are an Optional, which tells an absent field from a null one.
+func (p *csharpPrinter) printProduct(prod ast.Product) string {
+	p.useNamespace("System.Text.Json.Serialization")
+	var propertyStrings []string
+	for _, f := range prod.AllFields() {
+		attributesString := fmt.Sprintf("    [JsonPropertyName(%s)]\n", printCSharpString(fieldJsonName(f)))
+		typeString := p.printType(f.Type)
+		switch {
+		case f.Type.IsOptional() && f.Type.IsNullable():
+			p.usesOptional = true
+			typeString = fmt.Sprintf("Optional<%s>", typeString)
+			attributesString += "    [JsonIgnore(Condition = JsonIgnoreCondition.WhenWritingDefault)]\n"
+		case f.Type.IsOptional():
+			attributesString += "    [JsonIgnore(Condition = JsonIgnoreCondition.WhenWritingNull)]\n"
+		}
+		if p.isInt64(f.Type) {
+			if f.Type.IsOptional() && f.Type.IsNullable() {
+				attributesString += fmt.Sprintf("    [JsonConverter(typeof(OptionalInt64Converter<%s>))]\n", p.printType(f.Type))
+			} else {
+				attributesString += "    [JsonNumberHandling(JsonNumberHandling.WriteAsString | JsonNumberHandling.AllowReadingFromString)]\n"
+			}
+		}
+
+		_, deprecated := f.Attributes.Get("deprecated")
+		var requiredString string
+		if !f.Type.IsOptional() && f.Default == nil && !deprecated {
+			requiredString = "required "
+		}
+		propertyString := fmt.Sprintf("    public %s%s %s { get; init; }", requiredString, typeString, csharpPropertyName(f.Id, prod.Id))
+		if f.Default != nil {
+			propertyString += fmt.Sprintf(" = %s;", p.printDefault(f))
+		} else if !f.Type.IsOptional() && deprecated {
+			propertyString += " = default!;"
+		}
+		propertyStrings = append(propertyStrings, p.printDoc(f.Doc, f.Attributes, "    ")+attributesString+propertyString+"\n")
+	}
+	return p.printDoc(prod.Doc, prod.Attributes, "") + fmt.Sprintf("public sealed record %s%s\n{\n%s}\n", prod.Id, printCSharpTypeParams(prod.TypeParams), strings.Join(propertyStrings, "\n"))
+}
+
+// isInt64 reports whether a type holds Int64s, directly or in a list or map.
+func (p *csharpPrinter) isInt64(t ast.Type) bool {
+	t = p.resolveAliases(t)
+	if t.List != nil {
+		return p.isInt64(t.List.Type)
+	}
+	if t.Map != nil {
+		return p.isInt64(t.Map.Value)
+	}
+	return t.TypeIdent.Id == "Int64"
+}
+
+// printNewType prints a record struct holding the value, with a converter
+// encoding it as the value. Newtypes of strings can be the keys of
+// dictionaries.
+func (p *csharpPrinter) printNewType(n ast.NewType) string {
+	p.useNamespace("System")
+	p.useNamespace("System.Text.Json")
+	p.useNamespace("System.Text.Json.Serialization")
+	valueType := p.printType(n.Type)
+	read := fmt.Sprintf("new(JsonSerializer.Deserialize<%s>(ref reader, options)!)", valueType)
+	write := "JsonSerializer.Serialize(writer, value.Value, options)"
+	base := p.resolveAliases(n.Type)
+	if base.TypeIdent != nil && base.TypeIdent.Id == "Int64" {
+		p.useNamespace("System.Globalization")
+		read = "new(reader.TokenType == JsonTokenType.String ? long.Parse(reader.GetString()!, CultureInfo.InvariantCulture) : reader.GetInt64())"
+		write = "writer.WriteStringValue(value.Value.ToString(CultureInfo.InvariantCulture))"
+	}
+	var propertyNameString string
+	if base.TypeIdent != nil && base.TypeIdent.Id == "Str" {
+		propertyNameString = fmt.Sprintf(`
+        public override %s ReadAsPropertyName(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options) =>
+            new(reader.GetString()!);
+
+        public override void WriteAsPropertyName(Utf8JsonWriter writer, %s value, JsonSerializerOptions options) =>
+            writer.WritePropertyName(value.Value);
+`, n.Id, n.Id)
+	}
+	return p.printDoc(n.Doc, n.Attributes, "") + fmt.Sprintf(`[JsonConverter(typeof(%s.Converter))]
+public readonly record struct %s(%s Value)
+{
+    public sealed class Converter : JsonConverter<%s>
+    {
+        public override %s Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options) =>
+            %s;
+
+        public override void Write(Utf8JsonWriter writer, %s value, JsonSerializerOptions options) =>
+            %s;
+%s    }
+}
+`, n.Id, n.Id, valueType, n.Id, n.Id, read, n.Id, write, propertyNameString)
+}
+
+func (p *csharpPrinter) printSumStr(s ast.SumStr) string {
+	p.useNamespace("System.Text.Json.Serialization")
+	var memberStrings []string
+	for _, variant := range s.Variants {
+		name := capitalizeHead(variant.Id)
+		value := variant.Id
+		if variant.JsonName != nil {
+			value = *variant.JsonName
+		}
+		var attributeString string
+		if value != name {
+			attributeString = fmt.Sprintf("    [JsonStringEnumMemberName(%s)]\n", printCSharpString(value))
+		}
+		memberStrings = append(memberStrings, p.printDoc(variant.Doc, variant.Attributes, "    ")+attributeString+fmt.Sprintf("    %s,\n", name))
+	}
+	return p.printDoc(s.Doc, s.Attributes, "") + fmt.Sprintf("[JsonConverter(typeof(JsonStringEnumConverter<%s>))]\npublic enum %s\n{\n%s}\n", s.Id, s.Id, strings.Join(memberStrings, ""))
+}
+
+// printSumInt prints an enum of longs, which System.Text.Json encodes as
+// their values.
+func (p *csharpPrinter) printSumInt(s ast.SumInt) string {
+	var memberStrings []string
+	for _, variant := range s.Variants {
+		memberStrings = append(memberStrings, p.printDoc(variant.Doc, variant.Attributes, "    ")+fmt.Sprintf("    %s = %d,\n", capitalizeHead(variant.Id), variant.Value))
+	}
+	return p.printDoc(s.Doc, s.Attributes, "") + fmt.Sprintf("public enum %s : long\n{\n%s}\n", s.Id, strings.Join(memberStrings, ""))
+}
+
+// printDoc prints a doc comment, using the namespace of [Obsolete] when it is
+// added.
+func (p *csharpPrinter) printDoc(doc []string, attributes ast.Attributes, indent string) string {
+	if _, ok := attributes.Get("deprecated"); ok {
+		p.useNamespace("System")
+	}
+	return printCSharpDoc(doc, attributes, indent)
+}
+
+func printCSharpTypeParams(params []string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("<%s>", strings.Join(params, ", "))
+}
+
+func (p *csharpPrinter) printType(t ast.Type) string {
+	typeString := p.printValueType(t)
+	if t.IsOptional() || t.IsNullable() {
+		return typeString + "?"
+	}
+	return typeString
+}
+
+func (p *csharpPrinter) printValueType(t ast.Type) string {
+	if t.List != nil {
+		p.useNamespace("System.Collections.Generic")
+		return fmt.Sprintf("List<%s>", p.printType(t.List.Type))
+	}
+	if t.Map != nil {
+		p.useNamespace("System.Collections.Generic")
+		return fmt.Sprintf("Dictionary<%s, %s>", p.printType(t.Map.Key), p.printType(t.Map.Value))
+	}
+	id := t.TypeIdent.Id
+	switch id {
+	case "Any":
+		p.useNamespace("System.Text.Json")
+	case "Object":
+		p.useNamespace("System.Collections.Generic")
+		p.useNamespace("System.Text.Json")
+	}
+	if d, ok := p.definitions[id]; ok && d.Alias != nil {
+		return p.printValueType(d.Alias.Type)
+	}
+	if d, ok := p.definitions[id]; ok && d.Extern != nil {
+		return d.Extern.Targets["cs"]
+	}
+	typeString, ok := CSHARP_PRIMITIVES[id]
+	if !ok {
+		typeString = id
+	}
+	if len(t.TypeIdent.TypeArgs) > 0 {
+		var args []string
+		for _, arg := range t.TypeIdent.TypeArgs {
+			args = append(args, p.printType(arg))
+		}
+		typeString += fmt.Sprintf("<%s>", strings.Join(args, ", "))
+	}
+	return typeString
+}
+
+// resolveAliases follows the aliases a type is made of.
+func (p *csharpPrinter) resolveAliases(t ast.Type) ast.Type {
+	for i := 0; t.TypeIdent != nil && i < len(p.definitions); i++ {
+		d, ok := p.definitions[t.TypeIdent.Id]
+		if !ok || d.Alias == nil {
+			break
+		}
+		t = d.Alias.Type
+	}
+	return t
+}
+
+// printCSharpDoc prints doc lines as an XML doc comment, with an [Obsolete]
+// attribute for @deprecated.
+func printCSharpDoc(doc []string, attributes ast.Attributes, indent string) string {
+	var docString string
+	if len(doc) > 0 {
+		escaper := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+		docString = indent + "/// <summary>\n"
+		for _, line := range doc {
+			docString += strings.TrimRight(indent+"/// "+escaper.Replace(line), " ") + "\n"
+		}
+		docString += indent + "/// </summary>\n"
+	}
+	if deprecated, ok := attributes.Get("deprecated"); ok {
+		if reason, ok := deprecated.Arg("", 0); ok {
+			docString += fmt.Sprintf("%s[Obsolete(%s)]\n", indent, printCSharpString(reason))
+		} else {
+			docString += indent + "[Obsolete]\n"
+		}
+	}
+	return docString
+}
+
+// printCSharpString prints s as a C# string literal.
+func printCSharpString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case 0:
+			b.WriteString(`\0`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// csharpPropertyName is the PascalCase name of a field, with a trailing
+// underscore when it is a member of records or the name of the record, which
+// properties cannot have.
+func csharpPropertyName(id string, recordId string) string {
+	name := capitalizeHead(id)
+	if _, ok := csharpMemberNames[name]; ok || name == recordId {
+		return name + "_"
+	}
+	return name
+}
@@ -0,0 +1,25 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+func TestCSharpGolden(t *testing.T) {
+	for _, entry := range []string{"testdata/golden/api.bt", "testdata/golden/patch.bt"} {
+		files, _ := translateFile(t, entry, "cs")
+		declaredHelpers := make(map[string]struct{})
+		for _, f := range files {
+			options := CSharpGeneratorOptions{
+				Namespace:       "Example.Api",
+				Definitions:     ast.Definitions(files),
+				DeclaredHelpers: declaredHelpers,
+			}
+			assertGolden(t, f.Path, "cs", PrintCSharpDefinitions(f.Definitions, options))
+			for _, helper := range CSharpHelpers(f.Definitions, options) {
+				declaredHelpers[helper] = struct{}{}
+			}
+		}
+	}
+}
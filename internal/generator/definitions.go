@@ -0,0 +1,116 @@
+package generator
+
+import "github.com/brahms116/between/internal/ast"
+
+func definitionId(d ast.Definition) string {
+	switch {
+	case d.Product != nil:
+		return d.Product.Id
+	case d.Sum != nil:
+		return d.Sum.Id
+	case d.SumStr != nil:
+		return d.SumStr.Id
+	case d.SumInt != nil:
+		return d.SumInt.Id
+	case d.Alias != nil:
+		return d.Alias.Id
+	case d.NewType != nil:
+		return d.NewType.Id
+	case d.Extern != nil:
+		return d.Extern.Id
+	}
+	panic("Invalid definition")
+}
+
+func definitionsById(ds []ast.Definition) map[string]ast.Definition {
+	definitions := make(map[string]ast.Definition)
+	for _, d := range ds {
+		definitions[definitionId(d)] = d
+	}
+	return definitions
+}
+
+// resolveNewTypes follows the aliases and newtypes a type is made of, returning
+// the newtypes, outermost first, and the type they are made of.
+func resolveNewTypes(definitions map[string]ast.Definition, t ast.Type) ([]string, ast.Type) {
+	var newTypes []string
+	seen := make(map[string]struct{})
+	for t.TypeIdent != nil {
+		id := t.TypeIdent.Id
+		if _, ok := seen[id]; ok {
+			break
+		}
+		seen[id] = struct{}{}
+		d, ok := definitions[id]
+		if !ok {
+			break
+		}
+		if d.Alias != nil {
+			t = d.Alias.Type
+		} else if d.NewType != nil {
+			newTypes = append(newTypes, id)
+			t = d.NewType.Type
+		} else {
+			break
+		}
+	}
+	return newTypes, t
+}
+
+// reaches reports whether the definition from contains the definition to, or
+// is it, outside of a list or map.
+func reaches(references map[string][]string, from string, to string) bool {
+	seen := make(map[string]struct{})
+	var visit func(id string) bool
+	visit = func(id string) bool {
+		if id == to {
+			return true
+		}
+		if _, ok := seen[id]; ok {
+			return false
+		}
+		seen[id] = struct{}{}
+		for _, next := range references[id] {
+			if visit(next) {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(from)
+}
+
+// definitionReferences are the types a definition refers to outside of a list
+// or map.
+func definitionReferences(d ast.Definition) []string {
+	var types []ast.Type
+	switch {
+	case d.Product != nil:
+		for _, f := range d.Product.AllFields() {
+			types = append(types, f.Type)
+		}
+	case d.Sum != nil:
+		for _, v := range d.Sum.Variants {
+			types = append(types, v.Type)
+		}
+	case d.NewType != nil:
+		types = append(types, d.NewType.Type)
+	case d.Alias != nil:
+		types = append(types, d.Alias.Type)
+	}
+	var references []string
+	var walk func(t ast.Type)
+	walk = func(t ast.Type) {
+		if t.TypeIdent == nil {
+			return
+		}
+		references = append(references, t.TypeIdent.Id)
+		for _, arg := range t.TypeIdent.TypeArgs {
+			walk(arg)
+		}
+	}
+	for _, t := range types {
+		walk(t)
+	}
+	return references
+}
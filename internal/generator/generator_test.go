@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brahms116/between/internal/ast"
+	"github.com/brahms116/between/internal/parser"
+	"github.com/brahms116/between/internal/translate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+// translateFile parses and translates a file and the files it imports for a
+// target.
+func translateFile(t *testing.T, entry string, target string) ([]ast.File, map[string]struct{}) {
+	t.Helper()
+	parsed, errs := parser.LexAndParseFiles(entry, os.ReadFile)
+	require.Empty(t, errs)
+	files, primitives, errs := translate.TranslateFiles(entry, parsed, translate.Options{Targets: []string{target}})
+	require.Empty(t, errs)
+	return files, primitives
+}
+
+// runGo runs a Go program made of the files, returning what it prints. The
+// test is skipped when there is no Go toolchain.
+func runGo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed")
+	}
+	dir := t.TempDir()
+	files["go.mod"] = "module roundtrip\n\ngo 1.21\n"
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	return string(out)
+}
+
+// assertGolden compares the output generated for a source file of
+// testdata/golden with the file of the same name in the directory of the
+// target, which is rewritten instead with -update.
+func assertGolden(t *testing.T, sourcePath string, target string, got string) {
+	t.Helper()
+	name := strings.TrimSuffix(filepath.Base(sourcePath), filepath.Ext(sourcePath)) + "." + target
+	path := filepath.Join("testdata", "golden", target, name)
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0644))
+		return
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "run go test with -update to create the golden files")
+	assert.Equal(t, string(want), got)
+}
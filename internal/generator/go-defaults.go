@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printGoNew prints a NewX function returning the product with the defaults of
+// its fields set, or nothing when none of its fields have defaults.
+func printGoNew(p ast.Product) string {
+	var assignmentsString string
+	for _, f := range p.AllFields() {
+		if f.Default == nil {
+			continue
+		}
+		// Assigned rather than set in a composite literal, which cannot set
+		// the fields promoted from an extended product
+		assignmentsString += fmt.Sprintf(`x.%s = %s;`, capitalizeHead(f.Id), printGoDefault(f))
+	}
+	if assignmentsString == "" {
+		return ""
+	}
+
+	returnType := printGoReceiverType(p.Id, p.TypeParams)
+	return fmt.Sprintf(`
+// New%s returns a %s with the defaults of its fields set.
+func New%s%s() %s { var x %s; %s return x; };`, p.Id, p.Id, p.Id, printGoTypeParams(p.TypeParams), returnType, returnType, assignmentsString)
+}
+
+func printGoDefault(f ast.Field) string {
+	d := *f.Default
+	switch d.Kind {
+	case ast.DefaultString:
+		return strconv.Quote(d.Value)
+	case ast.DefaultNumber, ast.DefaultBool:
+		return d.Value
+	case ast.DefaultSumStr, ast.DefaultSumInt:
+		value := d.Enum + "_" + d.Variant
+		// The constant is typed, so has to be converted for aliases and
+		// newtypes of the enum
+		if f.Type.TypeIdent.Id != d.Enum {
+			value = fmt.Sprintf(`%s(%s)`, printGoType(f.Type, false), value)
+		}
+		return value
+	case ast.DefaultEmptyList, ast.DefaultEmptyMap:
+		return printGoType(f.Type, false) + "{}"
+	}
+	panic("Invalid default")
+}
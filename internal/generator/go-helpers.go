@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"sort"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// goHelper is a type generated alongside the definitions which use it.
+type goHelper struct {
+	imports []string
+	code    string
+}
+
+var goHelpers = map[string]goHelper{
+	// A map so that omitempty leaves it out when absent, as encoding/json never
+	// omits structs
+	"Nullable": {
+		imports: []string{"bytes", "encoding/json"},
+		code: `
+// Nullable is a field which can be absent, null or have a value.
+type Nullable[T any] map[bool]T;
+
+// NewNullable returns a Nullable with the value.
+func NewNullable[T any](v T) Nullable[T] { return Nullable[T]{true: v}; };
+
+// NewNull returns a Nullable which is null.
+func NewNull[T any]() Nullable[T] { var zero T; return Nullable[T]{false: zero}; };
+
+// Get returns the value, if there is one.
+func (n Nullable[T]) Get() (T, bool) { v, ok := n[true]; return v, ok; };
+
+// IsNull reports whether the field is null.
+func (n Nullable[T]) IsNull() bool { _, ok := n[false]; return ok; };
+
+// IsSet reports whether the field is present, either null or with a value.
+func (n Nullable[T]) IsSet() bool { return len(n) > 0; };
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) { v, ok := n[true]; if !ok { return []byte("null"), nil; }; return json.Marshal(v); };
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error { if bytes.Equal(data, []byte("null")) { *n = NewNull[T](); return nil; }; var v T; if err := json.Unmarshal(data, &v); err != nil { return err; }; *n = NewNullable(v); return nil; };`,
+	},
+	// For the products and sums with JSON names encoding/json cannot take from
+	// struct tags
+	"jsonField": {
+		imports: []string{"encoding/json"},
+		code: `
+// jsonField is a field of an object encoded by hand.
+type jsonField struct { name string; value any; };
+
+// marshalJSONObject encodes the fields as an object, in their order.
+func marshalJSONObject(fields []jsonField) ([]byte, error) { b := []byte{'{'}; for i, f := range fields { if i > 0 { b = append(b, ','); }; name, err := json.Marshal(f.name); if err != nil { return nil, err; }; value, err := json.Marshal(f.value); if err != nil { return nil, err; }; b = append(append(append(b, name...), ':'), value...); }; return append(b, '}'), nil; };
+
+// unmarshalJSONObject decodes the keys of an object into the fields they name,
+// ignoring unknown keys.
+func unmarshalJSONObject(data []byte, fields map[string]any) error { var object map[string]json.RawMessage; if err := json.Unmarshal(data, &object); err != nil { return err; }; for name, value := range object { if field, ok := fields[name]; ok { if err := json.Unmarshal(value, field); err != nil { return err; }; }; }; return nil; };`,
+	},
+	"Int64": {
+		imports: []string{"encoding/json", "strconv"},
+		code: `
+// Int64 is an int64 which is a string in JSON, as JavaScript numbers cannot
+// hold every int64.
+type Int64 int64;
+
+func (i Int64) MarshalJSON() ([]byte, error) { return json.Marshal(strconv.FormatInt(int64(i), 10)); };
+
+func (i *Int64) UnmarshalJSON(data []byte) error { var s string; if err := json.Unmarshal(data, &s); err != nil { return err; }; v, err := strconv.ParseInt(s, 10, 64); if err != nil { return err; }; *i = Int64(v); return nil; };`,
+	},
+	"Duration": {
+		imports: []string{"encoding/json", "time"},
+		code: `
+// Duration is a time.Duration which is a string such as "1h30m0s" in JSON.
+type Duration struct { time.Duration };
+
+func (d Duration) MarshalJSON() ([]byte, error) { return json.Marshal(d.String()); };
+
+func (d *Duration) UnmarshalJSON(data []byte) error { var s string; if err := json.Unmarshal(data, &s); err != nil { return err; }; v, err := time.ParseDuration(s); if err != nil { return err; }; d.Duration = v; return nil; };`,
+	},
+}
+
+// GoHelpers lists the helper types the definitions need, which are declared
+// in the same file unless another file of the package already declares them.
+func GoHelpers(ds []ast.Definition, usedPrimitives map[string]struct{}) []string {
+	var helpers []string
+	if goUsesNullable(ds) {
+		helpers = append(helpers, "Nullable")
+	}
+	if goUsesJsonObject(ds) {
+		helpers = append(helpers, "jsonField")
+	}
+	for _, primitive := range []string{"Int64", "Duration"} {
+		if _, ok := usedPrimitives[primitive]; ok {
+			helpers = append(helpers, primitive)
+		}
+	}
+	sort.Strings(helpers)
+	return helpers
+}
+
+// goUsesNullable reports whether any field of the definitions is both optional
+// and nullable, which needs the Nullable type.
+func goUsesNullable(ds []ast.Definition) bool {
+	for _, d := range ds {
+		if d.Product == nil {
+			continue
+		}
+		for _, f := range d.Product.Fields {
+			if f.Type.IsOptional() && f.Type.IsNullable() {
+				return true
+			}
+		}
+	}
+	return false
+}
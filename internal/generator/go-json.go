@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printGoProductJsonMethods prints JSON methods encoding the fields of a
+// product by hand, or nothing when encoding/json can take the JSON names of all
+// its fields, including the ones it inherits, from their struct tags.
+func printGoProductJsonMethods(p ast.Product) string {
+	if !goProductNeedsJsonMethods(p) {
+		return ""
+	}
+	var appendsString string
+	var fieldsString string
+	for _, f := range p.AllFields() {
+		fieldName := capitalizeHead(f.Id)
+		name := strconv.Quote(fieldJsonName(f))
+		appendString := fmt.Sprintf(`fields = append(fields, jsonField{%s, x.%s});`, name, fieldName)
+		// Optional fields are left out when absent, as omitempty would
+		if f.Type.IsOptional() && f.Type.IsNullable() {
+			appendString = fmt.Sprintf(`if x.%s.IsSet() { %s };`, fieldName, appendString)
+		} else if f.Type.IsOptional() {
+			appendString = fmt.Sprintf(`if x.%s != nil { %s };`, fieldName, appendString)
+		}
+		appendsString += appendString
+		fieldsString += fmt.Sprintf(`%s: &x.%s,`, name, fieldName)
+	}
+	receiverType := printGoReceiverType(p.Id, p.TypeParams)
+	return fmt.Sprintf(`
+// MarshalJSON encodes the fields of %s by hand, as encoding/json cannot take
+// all of their JSON names from struct tags.
+func (x %s) MarshalJSON() ([]byte, error) { var fields []jsonField; %s return marshalJSONObject(fields); };
+
+// UnmarshalJSON decodes the fields of %s by hand, as encoding/json cannot take
+// all of their JSON names from struct tags.
+func (x *%s) UnmarshalJSON(b []byte) error { return unmarshalJSONObject(b, map[string]any{ %s }); };`, p.Id, receiverType, appendsString, p.Id, receiverType, fieldsString)
+}
+
+// goProductNeedsJsonMethods reports whether a product has a field, of its own
+// or inherited, whose JSON name cannot be a struct tag. Products inheriting
+// such a field need methods of their own, as the ones of the product they embed
+// would be promoted and encode only its fields.
+func goProductNeedsJsonMethods(p ast.Product) bool {
+	for _, f := range p.AllFields() {
+		if !isGoJsonTagName(fieldJsonName(f)) {
+			return true
+		}
+	}
+	return false
+}
+
+// goSumNeedsJsonObject reports whether the tag or content key of a sum cannot
+// be a struct tag, so its JSON methods read and write them by hand.
+func goSumNeedsJsonObject(s ast.Sum) bool {
+	switch s.Encoding {
+	case ast.SumEncodingInternal:
+		return !isGoJsonTagName(s.Tag)
+	case ast.SumEncodingAdjacent:
+		return !isGoJsonTagName(s.Tag) || !isGoJsonTagName(s.Content)
+	}
+	return false
+}
+
+// goUsesJsonObject reports whether any of the definitions encodes an object by
+// hand, which needs the jsonField helpers.
+func goUsesJsonObject(ds []ast.Definition) bool {
+	for _, d := range ds {
+		if d.Product != nil && goProductNeedsJsonMethods(*d.Product) {
+			return true
+		}
+		if d.Sum != nil && goSumNeedsJsonObject(*d.Sum) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package generator
+
+import (
+	"go/format"
+	"os"
+	"testing"
+
+	"github.com/brahms116/between/internal/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoJsonNames(t *testing.T) {
+	files, primitives := translateFile(t, "testdata/go-json-names/api.bt", "go")
+	code, err := format.Source([]byte(PrintGoDefinitions(ast.Definitions(files), primitives, GoGeneratorOptions{PackageName: "main"})))
+	require.NoError(t, err)
+	program, err := os.ReadFile("testdata/go-json-names/main.go")
+	require.NoError(t, err)
+
+	out := runGo(t, map[string]string{"api.go": string(code), "main.go": string(program)})
+	assert.Equal(t, `{"say \"hi\"":"a"}
+{"say \"hi\"":"a","C:\\":"b","":1,"a,b":null,"plain":"c"}
+{"say \"hi\"":"a","C:\\":"b","a,b":"d","plain":"c"}
+{"say \"hi\"":"a","C:\\":"b","plain":"c"}
+{"k,v":"user","say \"hi\"":"a","C:\\":"b","plain":"c"}
+{"t\"":"count","c\\":2}
+{"t\"":"user","c\\":{"say \"hi\"":"","C:\\":"","plain":"c"}}
+`, out)
+}
@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printGoSumMethods prints the variant names, constructors, Which accessor and
+// JSON methods of a sum, which make sure exactly one variant is set.
+func printGoSumMethods(s ast.Sum) string {
+	return printGoSumVariants(s) + printGoSumConstructors(s) + printGoSumMarshal(s) + printGoSumUnmarshal(s)
+}
+
+func goSumVariantType(s ast.Sum) string {
+	return s.Id + "Variant"
+}
+
+func printGoSumVariants(s ast.Sum) string {
+	variantType := goSumVariantType(s)
+	variantsString := printGoDoc([]string{fmt.Sprintf(`%s names a variant of %s.`, variantType, s.Id)}, nil) + fmt.Sprintf(`type %s string;`, variantType)
+	var casesString string
+	for _, variant := range s.Variants {
+		variantName := variantType + "_" + capitalizeHead(variant.Id)
+		variantsString += fmt.Sprintf(`const %s %s = %s;`, variantName, variantType, strconv.Quote(fieldJsonName(variant)))
+		casesString += fmt.Sprintf(`case x.%s != nil: return %s;`, capitalizeHead(variant.Id), variantName)
+	}
+	return variantsString + fmt.Sprintf(`
+// Which returns the variant which is set, or an empty string when none is.
+func (x %s) Which() %s { switch { %s }; return ""; };`, printGoReceiverType(s.Id, s.TypeParams), variantType, casesString)
+}
+
+func printGoSumConstructors(s ast.Sum) string {
+	receiverType := printGoReceiverType(s.Id, s.TypeParams)
+	var constructorsString string
+	for _, variant := range s.Variants {
+		fieldName := capitalizeHead(variant.Id)
+		constructorsString += fmt.Sprintf(`
+// New%s%s wraps v as the %s variant of %s.
+func New%s%s%s(v %s) %s { return %s{%s: &v}; };`, s.Id, fieldName, fieldName, s.Id, s.Id, fieldName, printGoTypeParams(s.TypeParams), printGoType(variant.Type, false), receiverType, receiverType, fieldName)
+	}
+	return constructorsString
+}
+
+func printGoSumMarshal(s ast.Sum) string {
+	var countString string
+	var casesString string
+	for _, variant := range s.Variants {
+		fieldName := capitalizeHead(variant.Id)
+		countString += fmt.Sprintf(`if x.%s != nil { set++ };`, fieldName)
+		if s.Encoding == ast.SumEncodingUntagged {
+			casesString += fmt.Sprintf(`case x.%s != nil: value = x.%s;`, fieldName, fieldName)
+			continue
+		}
+		casesString += fmt.Sprintf(`case x.%s != nil: tag, value = %s, x.%s;`, fieldName, strconv.Quote(fieldJsonName(variant)), fieldName)
+	}
+	countString += fmt.Sprintf(`if set != 1 { return nil, fmt.Errorf(%s, set) };`, strconv.Quote(s.Id+" must have exactly one variant set, got %d"))
+
+	declarationsString := `var tag string; var value any;`
+	var encodeString string
+	switch s.Encoding {
+	case ast.SumEncodingExternal:
+		encodeString = `return json.Marshal(map[string]any{tag: value});`
+	case ast.SumEncodingInternal:
+		// The tag is spliced in as the first key of the object of the variant
+		encodeString = fmt.Sprintf(`b, err := json.Marshal(value); if err != nil { return nil, err };
+tagged, err := json.Marshal(map[string]string{%s: tag}); if err != nil { return nil, err };
+if string(b) == "{}" { return tagged, nil };
+return append(append(tagged[:len(tagged)-1], ','), b[1:]...), nil;`, strconv.Quote(s.Tag))
+	case ast.SumEncodingAdjacent:
+		encodeString = fmt.Sprintf(`return json.Marshal(struct { Tag string %s; Content any %s; }{tag, value});`, printGoJsonTag(s.Tag), printGoJsonTag(s.Content))
+		if goSumNeedsJsonObject(s) {
+			encodeString = fmt.Sprintf(`return marshalJSONObject([]jsonField{{%s, tag}, {%s, value}});`, strconv.Quote(s.Tag), strconv.Quote(s.Content))
+		}
+	case ast.SumEncodingUntagged:
+		declarationsString = `var value any;`
+		encodeString = `return json.Marshal(value);`
+	}
+
+	return fmt.Sprintf(`
+// MarshalJSON encodes the variant which is set, failing unless exactly one is.
+func (x %s) MarshalJSON() ([]byte, error) { set := 0; %s %s switch { %s }; %s };`, printGoReceiverType(s.Id, s.TypeParams), countString, declarationsString, casesString, encodeString)
+}
+
+func printGoSumUnmarshal(s ast.Sum) string {
+	receiverType := printGoReceiverType(s.Id, s.TypeParams)
+	if s.Encoding == ast.SumEncodingUntagged {
+		var attemptsString string
+		for _, variant := range s.Variants {
+			// Unknown fields are rejected so objects do not match the first
+			// product variant regardless of their fields
+			attemptsString += fmt.Sprintf(`{ d := json.NewDecoder(bytes.NewReader(b)); d.DisallowUnknownFields(); var value %s; if d.Decode(&value) == nil { x.%s = &value; return nil; }; };`, printGoType(variant.Type, false), capitalizeHead(variant.Id))
+		}
+		return fmt.Sprintf(`
+// UnmarshalJSON decodes the first variant which matches the JSON.
+func (x *%s) UnmarshalJSON(b []byte) error { *x = %s{}; %s return errors.New(%s); };`, receiverType, receiverType, attemptsString, strconv.Quote(s.Id+" does not match any of its variants"))
+	}
+
+	var taggedString string
+	var contentString string
+	switch s.Encoding {
+	case ast.SumEncodingExternal:
+		taggedString = fmt.Sprintf(`var object map[string]json.RawMessage; if err := json.Unmarshal(b, &object); err != nil { return err };
+if len(object) != 1 { return fmt.Errorf(%s, len(object)) };
+var tagged struct { Tag string; Content json.RawMessage; };
+for tag, content := range object { tagged.Tag, tagged.Content = tag, content };`, strconv.Quote(s.Id+" must have exactly one variant set, got %d"))
+		contentString = "tagged.Content"
+	case ast.SumEncodingAdjacent:
+		taggedString = fmt.Sprintf(`var tagged struct { Tag string %s; Content json.RawMessage %s; }; if err := json.Unmarshal(b, &tagged); err != nil { return err };`, printGoJsonTag(s.Tag), printGoJsonTag(s.Content))
+		if goSumNeedsJsonObject(s) {
+			taggedString = fmt.Sprintf(`var tagged struct { Tag string; Content json.RawMessage; }; if err := unmarshalJSONObject(b, map[string]any{%s: &tagged.Tag, %s: &tagged.Content}); err != nil { return err };`, strconv.Quote(s.Tag), strconv.Quote(s.Content))
+		}
+		contentString = "tagged.Content"
+	case ast.SumEncodingInternal:
+		taggedString = fmt.Sprintf(`var tagged struct { Tag string %s; }; if err := json.Unmarshal(b, &tagged); err != nil { return err };`, printGoJsonTag(s.Tag))
+		if goSumNeedsJsonObject(s) {
+			taggedString = fmt.Sprintf(`var tagged struct { Tag string; }; if err := unmarshalJSONObject(b, map[string]any{%s: &tagged.Tag}); err != nil { return err };`, strconv.Quote(s.Tag))
+		}
+		contentString = "b"
+	}
+
+	var casesString string
+	for _, variant := range s.Variants {
+		fieldName := capitalizeHead(variant.Id)
+		casesString += fmt.Sprintf(`case %s: x.%s = new(%s); return json.Unmarshal(%s, x.%s);`, strconv.Quote(fieldJsonName(variant)), fieldName, printGoType(variant.Type, false), contentString, fieldName)
+	}
+	casesString += fmt.Sprintf(`default: return fmt.Errorf(%s, tagged.Tag);`, strconv.Quote("unknown "+s.Id+" variant %q"))
+
+	return fmt.Sprintf(`
+// UnmarshalJSON decodes the variant named by the tag.
+func (x *%s) UnmarshalJSON(b []byte) error { %s *x = %s{}; switch tagged.Tag { %s }; };`, receiverType, taggedString, receiverType, casesString)
+}
+
+// addGoSumImports adds the packages used by the methods of the sum to imports.
+func addGoSumImports(s ast.Sum, imports map[string]struct{}) {
+	imports["encoding/json"] = struct{}{}
+	imports["fmt"] = struct{}{}
+	if s.Encoding == ast.SumEncodingUntagged {
+		imports["bytes"] = struct{}{}
+		imports["errors"] = struct{}{}
+	}
+}
@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"go/format"
+	"os"
+	"testing"
+
+	"github.com/brahms116/between/internal/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoSumJSON(t *testing.T) {
+	files, primitives := translateFile(t, "testdata/go-sum/api.bt", "go")
+	code, err := format.Source([]byte(PrintGoDefinitions(ast.Definitions(files), primitives, GoGeneratorOptions{PackageName: "main"})))
+	require.NoError(t, err)
+
+	program, err := os.ReadFile("testdata/go-sum/main.go")
+	require.NoError(t, err)
+
+	out := runGo(t, map[string]string{"api.go": string(code), "main.go": string(program)})
+	assert.Equal(t, `{"customer":{"name":"a"}}
+error: External must have exactly one variant set, got 2
+error: External must have exactly one variant set, got 0
+error: unknown External variant "other"
+{"kind":"customer","name":"a"}
+error: unknown Internal variant "other"
+{"kind":"count","data":2}
+error: unknown Adjacent variant "other"
+{"level":3}
+"a"
+error: Untagged does not match any of its variants
+{"ok":4}
+error: External must have exactly one variant set, got 0
+error: External must have exactly one variant set, got 2
+{"kind":"admin","level":2}
+{"kind":"admin","data":{"level":2}}
+"b"
+true true
+`, out)
+}
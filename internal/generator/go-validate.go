@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printGoValidate prints a Validate method which checks the constraints on the
+// fields of a product, including the ones it inherits, or nothing when none of
+// its fields have constraints.
+func printGoValidate(p ast.Product) string {
+	var patternsString string
+	var checksString string
+	for _, f := range p.AllFields() {
+		for _, c := range f.Constraints {
+			if c.Name == ast.ConstraintPattern {
+				patternsString += fmt.Sprintf(`var %s = regexp.MustCompile(%s);`, goPatternVarName(p, f), strconv.Quote(c.Value))
+			}
+			checksString += printGoConstraintCheck(p, f, c)
+		}
+	}
+	if checksString == "" {
+		return ""
+	}
+
+	validateString := fmt.Sprintf(`
+// Validate checks the constraints on the fields of %s, returning every violation.
+func (x %s) Validate() error { var errs []error; %s return errors.Join(errs...); };`, p.Id, printGoReceiverType(p.Id, p.TypeParams), checksString)
+	return patternsString + validateString
+}
+
+func printGoConstraintCheck(p ast.Product, f ast.Field, c ast.Constraint) string {
+	value := "x." + capitalizeHead(f.Id)
+	var guard string
+	if f.Type.IsOptional() && f.Type.IsNullable() {
+		guard = fmt.Sprintf(`v, ok := %s.Get(); ok && `, value)
+		value = "v"
+	} else if f.Type.IsOptional() || f.Type.IsNullable() {
+		guard = value + " != nil && "
+		value = "*" + value
+	}
+
+	var condition string
+	var message string
+	switch c.Name {
+	case ast.ConstraintMin:
+		condition = fmt.Sprintf(`%s < %s`, value, c.Value)
+		message = fmt.Sprintf(`%s must be at least %s`, fieldJsonName(f), c.Value)
+	case ast.ConstraintMax:
+		condition = fmt.Sprintf(`%s > %s`, value, c.Value)
+		message = fmt.Sprintf(`%s must be at most %s`, fieldJsonName(f), c.Value)
+	case ast.ConstraintMinLength:
+		condition = fmt.Sprintf(`utf8.RuneCountInString(string(%s)) < %s`, value, c.Value)
+		message = fmt.Sprintf(`%s must be at least %s characters long`, fieldJsonName(f), c.Value)
+	case ast.ConstraintMaxLength:
+		condition = fmt.Sprintf(`utf8.RuneCountInString(string(%s)) > %s`, value, c.Value)
+		message = fmt.Sprintf(`%s must be at most %s characters long`, fieldJsonName(f), c.Value)
+	case ast.ConstraintPattern:
+		condition = fmt.Sprintf(`!%s.MatchString(string(%s))`, goPatternVarName(p, f), value)
+		message = fmt.Sprintf(`%s must match the pattern %s`, fieldJsonName(f), c.Value)
+	case ast.ConstraintMinItems:
+		condition = fmt.Sprintf(`len(%s) < %s`, value, c.Value)
+		message = fmt.Sprintf(`%s must have at least %s items`, fieldJsonName(f), c.Value)
+	case ast.ConstraintMaxItems:
+		condition = fmt.Sprintf(`len(%s) > %s`, value, c.Value)
+		message = fmt.Sprintf(`%s must have at most %s items`, fieldJsonName(f), c.Value)
+	default:
+		panic("Invalid constraint")
+	}
+	return fmt.Sprintf(`if %s%s { errs = append(errs, errors.New(%s)); };`, guard, condition, strconv.Quote(message))
+}
+
+func goPatternVarName(p ast.Product, f ast.Field) string {
+	return lowerCaseHead(p.Id) + capitalizeHead(f.Id) + "Pattern"
+}
+
+// addGoValidateImports adds the packages used by the Validate method of the
+// product to imports.
+func addGoValidateImports(p ast.Product, imports map[string]struct{}) {
+	for _, f := range p.AllFields() {
+		for _, c := range f.Constraints {
+			imports["errors"] = struct{}{}
+			switch c.Name {
+			case ast.ConstraintPattern:
+				imports["regexp"] = struct{}{}
+			case ast.ConstraintMinLength, ast.ConstraintMaxLength:
+				imports["unicode/utf8"] = struct{}{}
+			}
+		}
+	}
+}
@@ -2,45 +2,197 @@ package generator
 
 import (
 	"fmt"
+	"go/token"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/brahms116/between/internal/ast"
 )
 
+// GO_PRIMITIVES maps primitives to Go types. Int64 and Duration are helper
+// types, as they are strings in JSON.
 var GO_PRIMITIVES map[string]string = map[string]string{
-	"Float":  "float32",
-	"Str":    "string",
-	"Bool":   "bool",
-	"Int":    "int",
-	"Any":    "any",
-	"Object": "map[string]any",
-	"Date":   "time.Time",
+	"Float":    "float32",
+	"Float64":  "float64",
+	"Str":      "string",
+	"Bool":     "bool",
+	"Int":      "int",
+	"Int32":    "int32",
+	"Int64":    "Int64",
+	"Any":      "any",
+	"Object":   "map[string]any",
+	"Decimal":  "string",
+	"UUID":     "string",
+	"Bytes":    "[]byte",
+	"Date":     "string",
+	"DateTime": "time.Time",
+	"Duration": "Duration",
 }
 
 type GoGeneratorOptions struct {
 	PackageName string
+	// Helper types declared by other files of the package, which are left out
+	DeclaredHelpers map[string]struct{}
 }
 
 func PrintGoDefinitions(ds []ast.Definition, usedPrimitives map[string]struct{}, options GoGeneratorOptions) string {
+	var helpers []string
+	for _, helper := range GoHelpers(ds, usedPrimitives) {
+		if _, ok := options.DeclaredHelpers[helper]; !ok {
+			helpers = append(helpers, helper)
+		}
+	}
+	imports := goImports(ds, usedPrimitives, helpers)
+	externImports := goExternImports(ds, imports)
+
 	var definitionString string
 	for _, d := range ds {
-		definitionString += printGoDefinition(d)
+		definitionString += printGoDefinition(d, externImports)
+	}
+	for _, helper := range helpers {
+		definitionString += goHelpers[helper].code
 	}
 	packageString := fmt.Sprintf("package %s;", options.PackageName)
 
 	var importsClause string
-	if _, ok := usedPrimitives["Date"]; ok {
-		importsClause += `"time";`
+	for _, imp := range imports {
+		importsClause += strconv.Quote(imp) + ";"
+	}
+	var externPaths []string
+	for importPath := range externImports {
+		externPaths = append(externPaths, importPath)
+	}
+	sort.Strings(externPaths)
+	for _, importPath := range externPaths {
+		if !slices.Contains(imports, importPath) {
+			importsClause += externImports[importPath] + " " + strconv.Quote(importPath) + ";"
+		}
 	}
 
 	importStatement := fmt.Sprintf("import (%s);", importsClause)
 	return packageString + importStatement + definitionString
 }
 
-func printGoDefinition(d ast.Definition) string {
+// goImports lists the packages used by the generated code for the definitions
+// and the helper types declared with them, other than the packages of externs.
+func goImports(ds []ast.Definition, usedPrimitives map[string]struct{}, helpers []string) []string {
+	imports := make(map[string]struct{})
+	if _, ok := usedPrimitives["DateTime"]; ok {
+		imports["time"] = struct{}{}
+	}
+	for _, helper := range helpers {
+		for _, imp := range goHelpers[helper].imports {
+			imports[imp] = struct{}{}
+		}
+	}
+	for _, d := range ds {
+		if d.Product != nil {
+			addGoValidateImports(*d.Product, imports)
+		}
+		if d.Sum != nil {
+			addGoSumImports(*d.Sum, imports)
+		}
+	}
+
+	var sortedImports []string
+	for imp := range imports {
+		sortedImports = append(sortedImports, imp)
+	}
+	sort.Strings(sortedImports)
+	return sortedImports
+}
+
+// goExternImports names the packages of the types of externs, by import path.
+// The packages are imported with these names, as a package is not always named
+// after the last element of its path, such as gopkg.in/yaml.v3 or
+// github.com/google/uuid/v5, and packages with the same name have to be told
+// apart. Packages which are imported already keep their name.
+func goExternImports(ds []ast.Definition, imports []string) map[string]string {
+	names := make(map[string]string)
+	used := make(map[string]struct{})
+	for _, imp := range imports {
+		used[path.Base(imp)] = struct{}{}
+	}
+	var externPaths []string
+	for _, d := range ds {
+		if d.Extern == nil {
+			continue
+		}
+		importPath, _, _ := splitGoExternType(d.Extern.Targets["go"])
+		if importPath == "" {
+			continue
+		}
+		if slices.Contains(imports, importPath) {
+			names[importPath] = path.Base(importPath)
+		} else if !slices.Contains(externPaths, importPath) {
+			externPaths = append(externPaths, importPath)
+		}
+	}
+	sort.Strings(externPaths)
+	for _, importPath := range externPaths {
+		name := goPackageName(importPath)
+		for i := 2; ; i++ {
+			if _, ok := used[name]; !ok && !token.IsKeyword(name) {
+				break
+			}
+			name = goPackageName(importPath) + strconv.Itoa(i)
+		}
+		used[name] = struct{}{}
+		names[importPath] = name
+	}
+	return names
+}
+
+var goMajorVersion = regexp.MustCompile(`^v[0-9]+$`)
+var goPathMajorVersion = regexp.MustCompile(`\.v[0-9]+$`)
+
+// goPackageName guesses the name of a package from its import path, leaving
+// out major versions, as in github.com/google/uuid/v5 and gopkg.in/yaml.v3,
+// and replacing the characters names cannot have.
+func goPackageName(importPath string) string {
+	elements := strings.Split(importPath, "/")
+	name := elements[len(elements)-1]
+	if goMajorVersion.MatchString(name) && len(elements) > 1 {
+		name = elements[len(elements)-2]
+	}
+	name = goPathMajorVersion.ReplaceAllString(name, "")
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	return name
+}
+
+func printGoDefinition(d ast.Definition, externImports map[string]string) string {
 	if d.SumStr != nil {
 		return printGoSumStr(*d.SumStr)
 	}
+	if d.SumInt != nil {
+		return printGoSumInt(*d.SumInt)
+	}
+	if d.Alias != nil {
+		return printGoDoc(d.Alias.Doc, d.Alias.Attributes) + fmt.Sprintf(`type %s = %s;`, d.Alias.Id, printGoType(d.Alias.Type, false))
+	}
+	if d.NewType != nil {
+		return printGoDoc(d.NewType.Doc, d.NewType.Attributes) + fmt.Sprintf(`type %s %s;`, d.NewType.Id, printGoType(d.NewType.Type, false))
+	}
+	if d.Extern != nil {
+		importPath, modifiers, typeString := splitGoExternType(d.Extern.Targets["go"])
+		if importPath != "" {
+			typeString = modifiers + externImports[importPath] + "." + typeString
+		}
+		return printGoDoc(d.Extern.Doc, d.Extern.Attributes) + fmt.Sprintf(`type %s = %s;`, d.Extern.Id, typeString)
+	}
 	if d.Sum != nil {
 		return printGoSum(*d.Sum)
 	}
@@ -51,7 +203,7 @@ func printGoDefinition(d ast.Definition) string {
 }
 
 func printGoSumStr(s ast.SumStr) string {
-	typeDec := fmt.Sprintf(`type %s string;`, s.Id)
+	typeDec := printGoDoc(s.Doc, s.Attributes) + fmt.Sprintf(`type %s string;`, s.Id)
 	var variantsString string
 	for _, variant := range s.Variants {
 		// Variants can't be optional, yet?
@@ -62,11 +214,36 @@ func printGoSumStr(s ast.SumStr) string {
 			variantValue = *variant.JsonName
 		}
 
-		variantsString += fmt.Sprintf(`const %s %s = "%s";`, variantName, s.Id, variantValue)
+		variantsString += printGoDoc(variant.Doc, variant.Attributes) + fmt.Sprintf(`const %s %s = %s;`, variantName, s.Id, strconv.Quote(variantValue))
 	}
 	return typeDec + variantsString
 }
 
+func printGoSumInt(s ast.SumInt) string {
+	typeDec := printGoDoc(s.Doc, s.Attributes) + fmt.Sprintf(`type %s int;`, s.Id)
+
+	// Values counting up from zero are left to iota
+	isIota := true
+	for i, variant := range s.Variants {
+		if variant.Value != int64(i) {
+			isIota = false
+		}
+	}
+
+	var variantsString string
+	for i, variant := range s.Variants {
+		variantName := s.Id + "_" + variant.Id
+		variantValue := fmt.Sprintf(` %s = %d`, s.Id, variant.Value)
+		if isIota && i == 0 {
+			variantValue = fmt.Sprintf(` %s = iota`, s.Id)
+		} else if isIota {
+			variantValue = ""
+		}
+		variantsString += printGoDoc(variant.Doc, variant.Attributes) + fmt.Sprintf(`%s%s;`, variantName, variantValue)
+	}
+	return typeDec + fmt.Sprintf(`const ( %s );`, variantsString)
+}
+
 func printGoSum(s ast.Sum) string {
 	var variantsString string
 
@@ -74,47 +251,170 @@ func printGoSum(s ast.Sum) string {
 		variantsString += fmt.Sprintf(`%s`, printGoField(variant, true))
 	}
 
-	return fmt.Sprintf("type %s struct { %s};", s.Id, variantsString)
+	return printGoDoc(s.Doc, s.Attributes) + fmt.Sprintf("type %s%s struct { %s};", s.Id, printGoTypeParams(s.TypeParams), variantsString) + printGoSumMethods(s)
 }
 
 func printGoProduct(p ast.Product) string {
 	var fieldsString string
+	if p.Extends != nil {
+		// Embedded, so its fields are promoted and flattened into the JSON
+		fieldsString += printGoType(ast.Type{TypeIdent: p.Extends}, false) + "; "
+	}
 	for _, field := range p.Fields {
 		fieldsString += printGoField(field, false) + " "
 	}
-	return fmt.Sprintf(`type %s struct { %s};`, p.Id, fieldsString)
+	return printGoDoc(p.Doc, p.Attributes) + fmt.Sprintf(`type %s%s struct { %s};`, p.Id, printGoTypeParams(p.TypeParams), fieldsString) + printGoNew(p) + printGoValidate(p) + printGoProductJsonMethods(p)
+}
+
+// splitGoExternType splits the Go type of an extern, such as
+// "*github.com/shopspring/decimal.Decimal", into the package to import, the
+// pointer and slice modifiers before it, and the name of the type. Types
+// without a package, such as "[]byte", need no import and are returned whole as
+// the name.
+func splitGoExternType(s string) (importPath string, modifiers string, typeName string) {
+	typeString := strings.TrimLeft(s, "*[]")
+	dot := strings.LastIndex(typeString, ".")
+	if dot == -1 {
+		return "", "", s
+	}
+	return typeString[:dot], s[:len(s)-len(typeString)], typeString[dot+1:]
+}
+
+// printGoReceiverType prints a type with its type parameters as arguments, as
+// used by the receivers of methods on generic types.
+func printGoReceiverType(id string, params []string) string {
+	if len(params) == 0 {
+		return id
+	}
+	return fmt.Sprintf(`%s[%s]`, id, strings.Join(params, ", "))
+}
+
+func printGoTypeParams(params []string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`[%s any]`, strings.Join(params, ", "))
 }
 
 func printGoField(f ast.Field, forcePointer bool) string {
 	fieldName := capitalizeHead(f.Id)
 	var omitEmptyTag string
-	if f.Type.IsNullable() || forcePointer {
-		omitEmptyTag = ",omitEmpty"
+	if f.Type.IsOptional() || forcePointer {
+		omitEmptyTag = ",omitempty"
 	}
 
-	jsonName := f.Id
-	if f.JsonName != nil {
-		jsonName = *f.JsonName
+	jsonTag := printGoJsonTag(fieldJsonName(f) + omitEmptyTag)
+	if !isGoJsonTagName(fieldJsonName(f)) {
+		// Encoded by the JSON methods of the product or sum instead
+		jsonTag = printGoJsonTag("-")
 	}
 
-	jsonTag := fmt.Sprintf("`json:\"%s%s\"`", jsonName, omitEmptyTag)
+	return printGoDoc(f.Doc, f.Attributes) + fmt.Sprintf(`%s %s %s;`, fieldName, printGoType(f.Type, forcePointer), jsonTag)
+}
+
+// goJsonTagPunctuation is the punctuation encoding/json allows in the names of
+// struct tags, besides letters, digits and spaces.
+const goJsonTagPunctuation = "!#$%&()*+-./:;<=>?@[]^_{|}~"
 
-	return fmt.Sprintf(`%s %s %s;`, fieldName, printGoType(f.Type, forcePointer), jsonTag)
+// isGoJsonTagName reports whether encoding/json can take a JSON name from a
+// struct tag. It ignores other names, using the name of the Go field instead.
+func isGoJsonTagName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && !strings.ContainsRune(goJsonTagPunctuation, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// printGoJsonTag prints the struct tag giving the JSON name and options of a
+// field. Tags are raw strings unless they contain a backtick.
+func printGoJsonTag(value string) string {
+	tag := "json:" + strconv.Quote(value)
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
 }
 
 func printGoType(t ast.Type, forcePointer bool) string {
-	var optionalString string
-	if t.IsNullable() || forcePointer {
-		optionalString = "*"
+	if t.IsOptional() && t.IsNullable() {
+		// A pointer cannot tell an absent value from a null one
+		return fmt.Sprintf(`Nullable[%s]`, printGoValueType(t))
+	}
+	if t.IsOptional() || t.IsNullable() || forcePointer {
+		return "*" + printGoValueType(t)
 	}
+	return printGoValueType(t)
+}
+
+// printGoValueType prints a type without the pointer of optional and nullable
+// types.
+func printGoValueType(t ast.Type) string {
 	if t.List != nil {
-		return fmt.Sprintf(`%s[]%s`, optionalString, printGoType(t.List.Type, false))
+		return fmt.Sprintf(`[]%s`, printGoType(t.List.Type, false))
+	}
+	if t.Map != nil {
+		return fmt.Sprintf(`map[%s]%s`, printGoType(t.Map.Key, false), printGoType(t.Map.Value, false))
 	}
 	typeString, ok := GO_PRIMITIVES[t.TypeIdent.Id]
 	if !ok {
 		typeString = t.TypeIdent.Id
 	}
-	return optionalString + typeString
+	if len(t.TypeIdent.TypeArgs) > 0 {
+		var args []string
+		for _, arg := range t.TypeIdent.TypeArgs {
+			args = append(args, printGoType(arg, false))
+		}
+		typeString += fmt.Sprintf(`[%s]`, strings.Join(args, ", "))
+	}
+	return typeString
+}
+
+// printGoDoc prints doc lines as line comments on their own lines, so they are
+// not mistaken for a trailing comment of the previous semicolon separated
+// statement. @deprecated adds a "Deprecated: " paragraph, which tools like
+// gopls and staticcheck recognise.
+func printGoDoc(doc []string, attributes ast.Attributes) string {
+	if deprecated, ok := attributes.Get("deprecated"); ok {
+		reason, ok := deprecated.Arg("", 0)
+		if !ok {
+			reason = "do not use."
+		}
+		if len(doc) > 0 {
+			doc = append(doc[:len(doc):len(doc)], "")
+		}
+		doc = append(doc, "Deprecated: "+reason)
+	}
+	if len(doc) == 0 {
+		return ""
+	}
+	docString := "\n"
+	for _, line := range doc {
+		if line == "" {
+			docString += "//\n"
+			continue
+		}
+		docString += fmt.Sprintf("// %s\n", line)
+	}
+	return docString
+}
+
+func fieldJsonName(f ast.Field) string {
+	if f.JsonName != nil {
+		return *f.JsonName
+	}
+	return f.Id
+}
+
+func lowerCaseHead(s string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return strings.ToLower(string(s[0])) + s[1:]
 }
 
 func capitalizeHead(s string) string {
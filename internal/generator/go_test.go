@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"go/format"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brahms116/between/internal/ast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoGolden(t *testing.T) {
+	for _, entry := range []string{"testdata/golden/api.bt", "testdata/golden/patch.bt"} {
+		files, _ := translateFile(t, entry, "go")
+		// The files are generated into a program, which has to build
+		program := map[string]string{"main.go": "package main\n\nfunc main() {}\n"}
+		declaredHelpers := make(map[string]struct{})
+		for _, f := range files {
+			code, err := format.Source([]byte(PrintGoDefinitions(f.Definitions, f.UsedPrimitiveTypes, GoGeneratorOptions{
+				PackageName:     "main",
+				DeclaredHelpers: declaredHelpers,
+			})))
+			require.NoError(t, err)
+			assertGolden(t, f.Path, "go", string(code))
+			for _, helper := range GoHelpers(f.Definitions, f.UsedPrimitiveTypes) {
+				declaredHelpers[helper] = struct{}{}
+			}
+			program[strings.TrimSuffix(filepath.Base(f.Path), filepath.Ext(f.Path))+".go"] = string(code)
+		}
+		runGo(t, program)
+	}
+}
+
+func TestGoExternImports(t *testing.T) {
+	files, primitives := translateFile(t, "testdata/go-externs/api.bt", "go")
+	code, err := format.Source([]byte(PrintGoDefinitions(ast.Definitions(files), primitives, GoGeneratorOptions{PackageName: "api"})))
+	require.NoError(t, err)
+	assert.Equal(t, `package api
+
+import (
+	uuid "github.com/google/uuid"
+	uuid2 "github.com/google/uuid/v5"
+	yaml "gopkg.in/yaml.v3"
+	"time"
+)
+
+type Node = yaml.Node
+type Id = uuid2.UUID
+type LegacyIds = []*uuid.UUID
+type Timeout = time.Duration
+type Raw = []byte
+type Doc struct {
+	Node      Node      `+"`json:\"node\"`"+`
+	Id        Id        `+"`json:\"id\"`"+`
+	LegacyIds LegacyIds `+"`json:\"legacyIds\"`"+`
+	Timeout   Timeout   `+"`json:\"timeout\"`"+`
+	At        time.Time `+"`json:\"at\"`"+`
+	Raw       Raw       `+"`json:\"raw\"`"+`
+}
+`, string(code))
+}
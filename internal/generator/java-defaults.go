@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printDefault prints the default of a field, wrapped in the newtypes the type
+// of the field is made of.
+func (p *javaPrinter) printDefault(f ast.Field) string {
+	newTypes, base := resolveNewTypes(p.definitions, f.Type)
+	d := *f.Default
+	var value string
+	switch d.Kind {
+	case ast.DefaultString:
+		value = printJavaString(d.Value)
+	case ast.DefaultNumber:
+		value = d.Value
+		if base.TypeIdent != nil {
+			switch base.TypeIdent.Id {
+			case "Int", "Int64":
+				value += "L"
+			case "Float":
+				value += "f"
+			case "Float64":
+				value += "d"
+			}
+		}
+	case ast.DefaultBool:
+		value = d.Value
+	case ast.DefaultSumStr, ast.DefaultSumInt:
+		value = fmt.Sprintf("%s.%s", d.Enum, javaIdent(d.Variant))
+	case ast.DefaultEmptyList:
+		p.useImport("java.util.List")
+		value = "List.of()"
+	case ast.DefaultEmptyMap:
+		p.useImport("java.util.Map")
+		value = "Map.of()"
+	default:
+		panic("Invalid default")
+	}
+	for i := len(newTypes) - 1; i >= 0; i-- {
+		value = fmt.Sprintf("new %s(%s)", newTypes[i], value)
+	}
+	return value
+}
@@ -0,0 +1,224 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printSum prints a sum as a sealed interface with a record per variant holding
+// its value. Its serializer writes the value where the encoding of the sum
+// puts it, and its deserializer reads the variant from a tree of the JSON.
+func (p *javaPrinter) printSum(s ast.Sum) string {
+	for _, imp := range []string{
+		"java.io.IOException",
+		"com.fasterxml.jackson.core.JsonGenerator",
+		"com.fasterxml.jackson.core.JsonParser",
+		"com.fasterxml.jackson.databind.DeserializationContext",
+		"com.fasterxml.jackson.databind.JsonMappingException",
+		"com.fasterxml.jackson.databind.JsonNode",
+		"com.fasterxml.jackson.databind.SerializerProvider",
+		"com.fasterxml.jackson.databind.annotation.JsonDeserialize",
+		"com.fasterxml.jackson.databind.annotation.JsonSerialize",
+		"com.fasterxml.jackson.databind.deser.std.StdDeserializer",
+		"com.fasterxml.jackson.databind.ser.std.StdSerializer",
+		"com.fasterxml.jackson.databind.type.TypeFactory",
+	} {
+		p.useImport(imp)
+	}
+	typeParams := printJavaTypeParams(s.TypeParams)
+	// Serializers handle any type arguments
+	var wildcards []string
+	for range s.TypeParams {
+		wildcards = append(wildcards, "?")
+	}
+	wildcardType := s.Id + printJavaTypeParams(wildcards)
+	diamond := ""
+	if len(s.TypeParams) > 0 {
+		diamond = "<>"
+	}
+
+	var variantsString string
+	var serializeCasesString string
+	var deserializeCasesString string
+	var untaggedAttemptsString string
+	for _, variant := range s.Variants {
+		className := s.Id + capitalizeHead(variant.Id)
+		variantsString += "\n" + printJavaDoc(variant.Doc, variant.Attributes) + fmt.Sprintf("public record %s%s(%s value) implements %s%s {\n}\n", className, typeParams, p.printType(variant.Type, false), s.Id, typeParams)
+
+		key := printJavaString(fieldJsonName(variant))
+		writeValue := "provider.defaultSerializeValue(variant.value(), gen);\n"
+		if t := p.resolveAliases(variant.Type); t.TypeIdent != nil && t.TypeIdent.Id == "Int64" {
+			writeValue = "gen.writeString(String.valueOf(variant.value()));\n"
+		}
+		var writeString string
+		switch s.Encoding {
+		case ast.SumEncodingExternal:
+			writeString = "gen.writeStartObject();\n" +
+				fmt.Sprintf("gen.writeFieldName(%s);\n", key) +
+				writeValue +
+				"gen.writeEndObject();\n"
+		case ast.SumEncodingInternal:
+			p.useImport("com.fasterxml.jackson.databind.util.NameTransformer")
+			// The fields of the variant are written next to the tag
+			writeString = "gen.writeStartObject();\n" +
+				fmt.Sprintf("gen.writeStringField(%s, %s);\n", printJavaString(s.Tag), key) +
+				"provider.findValueSerializer(variant.value().getClass())\n" +
+				"        .unwrappingSerializer(NameTransformer.NOP)\n" +
+				"        .serialize(variant.value(), gen, provider);\n" +
+				"gen.writeEndObject();\n"
+		case ast.SumEncodingAdjacent:
+			writeString = "gen.writeStartObject();\n" +
+				fmt.Sprintf("gen.writeStringField(%s, %s);\n", printJavaString(s.Tag), key) +
+				fmt.Sprintf("gen.writeFieldName(%s);\n", printJavaString(s.Content)) +
+				writeValue +
+				"gen.writeEndObject();\n"
+		case ast.SumEncodingUntagged:
+			writeString = writeValue
+		}
+		variantType := className + printJavaTypeParams(wildcards)
+		serializeCasesString += fmt.Sprintf("            if (value instanceof %s variant) {\n%s                return;\n            }\n", variantType, indentJavaBy(writeString, 4))
+
+		read := fmt.Sprintf("new %s%s(ctxt.readTreeAsValue(%%s, %s))", className, diamond, p.javaTypeExpression(variant.Type, s.TypeParams))
+		deserializeCasesString += fmt.Sprintf("                case %s -> %s;\n", key, fmt.Sprintf(read, "variant"))
+		untaggedAttemptsString += fmt.Sprintf("            try {\n                return %s;\n            } catch (IOException e) {\n                // The next variant is tried\n            }\n", fmt.Sprintf(read, "node"))
+	}
+
+	unknownString := fmt.Sprintf("                default -> throw JsonMappingException.from(p, %s + key);\n", printJavaString("Unknown "+s.Id+" variant "))
+	var readString string
+	switch s.Encoding {
+	case ast.SumEncodingExternal:
+		p.useImport("java.util.Map")
+		readString = fmt.Sprintf(`            if (!node.isObject() || node.size() != 1) {
+                throw JsonMappingException.from(p, %s);
+            }
+            Map.Entry<String, JsonNode> entry = node.fields().next();
+            String key = entry.getKey();
+            JsonNode variant = entry.getValue();
+            return switch (key) {
+%s%s            };
+`, printJavaString(s.Id+" must have a single key"), deserializeCasesString, unknownString)
+	case ast.SumEncodingInternal:
+		p.useImport("com.fasterxml.jackson.databind.node.ObjectNode")
+		readString = fmt.Sprintf(`            if (!(node instanceof ObjectNode variant) || !variant.path(%s).isTextual()) {
+                throw JsonMappingException.from(p, %s);
+            }
+            String key = variant.remove(%s).asText();
+            return switch (key) {
+%s%s            };
+`, printJavaString(s.Tag), printJavaString(s.Id+" must be an object with "+s.Tag), printJavaString(s.Tag), deserializeCasesString, unknownString)
+	case ast.SumEncodingAdjacent:
+		p.useImport("com.fasterxml.jackson.databind.node.ObjectNode")
+		readString = fmt.Sprintf(`            if (!(node instanceof ObjectNode object) || !object.path(%s).isTextual() || !object.has(%s)) {
+                throw JsonMappingException.from(p, %s);
+            }
+            String key = object.get(%s).asText();
+            JsonNode variant = object.get(%s);
+            return switch (key) {
+%s%s            };
+`, printJavaString(s.Tag), printJavaString(s.Content), printJavaString(s.Id+" must be an object with "+s.Tag+" and "+s.Content), printJavaString(s.Tag), printJavaString(s.Content), deserializeCasesString, unknownString)
+	case ast.SumEncodingUntagged:
+		// The first variant which can be read is taken
+		readString = untaggedAttemptsString + fmt.Sprintf("            throw JsonMappingException.from(p, %s);\n", printJavaString(s.Id+" does not match any of its variants"))
+	}
+
+	serializerSuper := fmt.Sprintf("super(%s.class);", s.Id)
+	deserializerString := fmt.Sprintf(`    final class Deserializer extends StdDeserializer<%s> {
+        public Deserializer() {
+            super(%s.class);
+        }
+
+`, wildcardType, s.Id)
+	if len(s.TypeParams) > 0 {
+		p.useImport("com.fasterxml.jackson.databind.BeanProperty")
+		p.useImport("com.fasterxml.jackson.databind.JavaType")
+		p.useImport("com.fasterxml.jackson.databind.JsonDeserializer")
+		p.useImport("com.fasterxml.jackson.databind.deser.ContextualDeserializer")
+		serializerSuper = fmt.Sprintf("super(%s.class, false);", s.Id)
+		// The type arguments are taken from the type being read
+		deserializerString = fmt.Sprintf(`    final class Deserializer extends StdDeserializer<%s> implements ContextualDeserializer {
+        private final JavaType type;
+
+        public Deserializer() {
+            this(null);
+        }
+
+        private Deserializer(JavaType type) {
+            super(%s.class);
+            this.type = type;
+        }
+
+        @Override
+        public JsonDeserializer<?> createContextual(DeserializationContext ctxt, BeanProperty property) {
+            return new Deserializer(ctxt.getContextualType());
+        }
+
+`, wildcardType, s.Id)
+	}
+	typeString := ""
+	if len(s.TypeParams) > 0 {
+		typeString = fmt.Sprintf("            JavaType type = this.type != null ? this.type : types.constructType(%s.class);\n", s.Id)
+	}
+
+	bodyString := fmt.Sprintf(`    final class Serializer extends StdSerializer<%s> {
+        public Serializer() {
+            %s
+        }
+
+        @Override
+        public void serialize(%s value, JsonGenerator gen, SerializerProvider provider) throws IOException {
+%s        }
+    }
+
+%s        @Override
+        public %s deserialize(JsonParser p, DeserializationContext ctxt) throws IOException {
+            TypeFactory types = ctxt.getTypeFactory();
+%s            JsonNode node = ctxt.readTree(p);
+%s        }
+    }
+`, wildcardType, serializerSuper, wildcardType, serializeCasesString, deserializerString, wildcardType, typeString, readString)
+
+	sumString := printJavaDoc(s.Doc, s.Attributes) + fmt.Sprintf("@JsonSerialize(using = %s.Serializer.class)\n@JsonDeserialize(using = %s.Deserializer.class)\npublic sealed interface %s%s {\n%s}\n", s.Id, s.Id, s.Id, typeParams, bodyString)
+	return sumString + variantsString
+}
+
+// javaTypeExpression prints an expression building the JavaType of a type,
+// which Jackson needs to read generic types.
+func (p *javaPrinter) javaTypeExpression(t ast.Type, typeParams []string) string {
+	if t.List != nil {
+		p.useImport("java.util.List")
+		return fmt.Sprintf("types.constructCollectionType(List.class, %s)", p.javaTypeExpression(t.List.Type, typeParams))
+	}
+	if t.Map != nil {
+		p.useImport("java.util.Map")
+		return fmt.Sprintf("types.constructMapType(Map.class, %s, %s)", p.javaTypeExpression(t.Map.Key, typeParams), p.javaTypeExpression(t.Map.Value, typeParams))
+	}
+	id := t.TypeIdent.Id
+	for i, param := range typeParams {
+		if id == param {
+			return fmt.Sprintf("type.containedTypeOrUnknown(%d)", i)
+		}
+	}
+	if d, ok := p.definitions[id]; ok && d.Alias != nil {
+		return p.javaTypeExpression(d.Alias.Type, typeParams)
+	}
+	if len(t.TypeIdent.TypeArgs) > 0 {
+		var args []string
+		for _, arg := range t.TypeIdent.TypeArgs {
+			args = append(args, p.javaTypeExpression(arg, typeParams))
+		}
+		return fmt.Sprintf("types.constructParametricType(%s.class, %s)", id, strings.Join(args, ", "))
+	}
+	// Classes of generic externs are taken without their type arguments
+	class, _, _ := strings.Cut(p.printType(t, true), "<")
+	return fmt.Sprintf("types.constructType(%s.class)", class)
+}
+
+// indentJavaBy indents each line of code by a number of levels.
+func indentJavaBy(code string, levels int) string {
+	for i := 0; i < levels; i++ {
+		code = indentJava(code)
+	}
+	return code
+}
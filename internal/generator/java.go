@@ -0,0 +1,488 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// JAVA_PRIMITIVES maps primitives to Java types, and JAVA_BOXED_PRIMITIVES to
+// the types used where they can be null or are type arguments. Int64s are
+// longs written as strings, and the other types which are strings in JSON are
+// Strings.
+var JAVA_PRIMITIVES map[string]string = map[string]string{
+	"Float":    "float",
+	"Float64":  "double",
+	"Str":      "String",
+	"Bool":     "boolean",
+	"Int":      "long",
+	"Int32":    "int",
+	"Int64":    "long",
+	"Any":      "JsonNode",
+	"Object":   "ObjectNode",
+	"Decimal":  "String",
+	"UUID":     "String",
+	"Bytes":    "String",
+	"Date":     "String",
+	"DateTime": "String",
+	"Duration": "String",
+}
+
+var JAVA_BOXED_PRIMITIVES map[string]string = map[string]string{
+	"float":   "Float",
+	"double":  "Double",
+	"boolean": "Boolean",
+	"long":    "Long",
+	"int":     "Integer",
+}
+
+// javaKeywords and the literals cannot be used as names.
+var javaKeywords = map[string]struct{}{
+	"abstract": {}, "assert": {}, "boolean": {}, "break": {}, "byte": {},
+	"case": {}, "catch": {}, "char": {}, "class": {}, "const": {},
+	"continue": {}, "default": {}, "do": {}, "double": {}, "else": {},
+	"enum": {}, "extends": {}, "final": {}, "finally": {}, "float": {},
+	"for": {}, "goto": {}, "if": {}, "implements": {}, "import": {},
+	"instanceof": {}, "int": {}, "interface": {}, "long": {}, "native": {},
+	"new": {}, "package": {}, "private": {}, "protected": {}, "public": {},
+	"return": {}, "short": {}, "static": {}, "strictfp": {}, "super": {},
+	"switch": {}, "synchronized": {}, "this": {}, "throw": {}, "throws": {},
+	"transient": {}, "try": {}, "void": {}, "volatile": {}, "while": {},
+	"true": {}, "false": {}, "null": {}, "_": {},
+}
+
+// javaRestrictedTypeNames are contextual keywords, which can name fields but
+// not classes.
+var javaRestrictedTypeNames = map[string]struct{}{
+	"permits": {}, "record": {}, "sealed": {}, "var": {}, "yield": {},
+}
+
+// javaReservedNames are suffixed with an underscore when used as names. Besides
+// keywords, records cannot have components named like the methods of Object.
+var javaReservedNames = func() map[string]struct{} {
+	names := map[string]struct{}{
+		"clone": {}, "finalize": {}, "getClass": {}, "hashCode": {}, "notify": {},
+		"notifyAll": {}, "toString": {}, "wait": {},
+	}
+	for keyword := range javaKeywords {
+		names[keyword] = struct{}{}
+	}
+	return names
+}()
+
+type JavaGeneratorOptions struct {
+	// Package of the generated code, the default package if empty
+	PackageName string
+	// Name of the class the definitions are nested in, which has to be the
+	// name of the generated file
+	ClassName string
+	// Fully qualified classes of the other files being generated, whose types
+	// are imported
+	ImportedClasses []string
+	// Definitions of every file being generated, to resolve the aliases and
+	// newtypes of types from other files
+	Definitions []ast.Definition
+}
+
+// javaPrinter prints definitions, keeping track of what the printed code uses
+// to add the imports it needs.
+type javaPrinter struct {
+	definitions map[string]ast.Definition
+	imports     map[string]struct{}
+}
+
+// PrintJavaDefinitions prints the definitions as records, enums and interfaces
+// nested in a class, as a Java file can only have one public class.
+func PrintJavaDefinitions(ds []ast.Definition, options JavaGeneratorOptions) string {
+	p := &javaPrinter{
+		definitions: definitionsById(options.Definitions),
+		imports:     make(map[string]struct{}),
+	}
+	var definitionStrings []string
+	for _, d := range ds {
+		definitionStrings = append(definitionStrings, p.printDefinition(d))
+	}
+
+	var importLines []string
+	for imp := range p.imports {
+		importLines = append(importLines, fmt.Sprintf("import %s;", imp))
+	}
+	for _, class := range options.ImportedClasses {
+		importLines = append(importLines, fmt.Sprintf("import %s.*;", class))
+	}
+	sort.Strings(importLines)
+
+	var headerString string
+	if options.PackageName != "" {
+		headerString = fmt.Sprintf("package %s;\n\n", options.PackageName)
+	}
+	if len(importLines) > 0 {
+		headerString += strings.Join(importLines, "\n") + "\n\n"
+	}
+	classString := fmt.Sprintf("public final class %s {\n    private %s() {\n    }\n", options.ClassName, options.ClassName)
+	for _, definitionString := range definitionStrings {
+		classString += "\n" + indentJava(definitionString)
+	}
+	return headerString + classString + "}\n"
+}
+
+// indentJava indents code by a level.
+func indentJava(code string) string {
+	lines := strings.Split(strings.TrimSuffix(code, "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = "    " + line
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (p *javaPrinter) useImport(imp string) {
+	p.imports[imp] = struct{}{}
+}
+
+// printDefinition prints a definition. Java has no type aliases, so aliases
+// and externs are replaced by their types where they are used.
+func (p *javaPrinter) printDefinition(d ast.Definition) string {
+	if d.SumStr != nil {
+		return p.printSumStr(*d.SumStr)
+	}
+	if d.SumInt != nil {
+		return p.printSumInt(*d.SumInt)
+	}
+	if d.Alias != nil {
+		return fmt.Sprintf("// %s is %s\n", d.Alias.Id, p.printType(d.Alias.Type, false))
+	}
+	if d.NewType != nil {
+		return p.printNewType(*d.NewType)
+	}
+	if d.Extern != nil {
+		return fmt.Sprintf("// %s is %s\n", d.Extern.Id, d.Extern.Targets["java"])
+	}
+	if d.Sum != nil {
+		return p.printSum(*d.Sum)
+	}
+	if d.Product != nil {
+		return p.printProduct(*d.Product)
+	}
+	panic("Invalid definition")
+}
+
+func (p *javaPrinter) printProduct(prod ast.Product) string {
+	// Inherited fields are copied in, as records cannot extend each other
+	fields := prod.AllFields()
+	var componentStrings []string
+	var params []string
+	var defaultsString string
+	for _, f := range fields {
+		name := javaIdent(f.Id)
+		componentStrings = append(componentStrings, "        "+p.printComponent(f))
+		if len(f.Doc) > 0 {
+			params = append(params, fmt.Sprintf("@param %s %s", name, strings.Join(f.Doc, " ")))
+		}
+		if f.Default != nil {
+			defaultsString += fmt.Sprintf("        if (%s == null) {\n            %s = %s;\n        }\n", name, name, p.printDefault(f))
+		}
+	}
+
+	doc := append([]string{}, prod.Doc...)
+	if len(params) > 0 {
+		if len(doc) > 0 {
+			doc = append(doc, "")
+		}
+		doc = append(doc, params...)
+	}
+	var bodyString string
+	if defaultsString != "" {
+		// Absent fields are null, and are given their defaults
+		bodyString = fmt.Sprintf("    public %s {\n%s    }\n", prod.Id, defaultsString)
+	}
+	return printJavaDoc(doc, prod.Attributes) + fmt.Sprintf("public record %s%s(\n%s) {\n%s}\n", prod.Id, printJavaTypeParams(prod.TypeParams), strings.Join(componentStrings, ",\n"), bodyString)
+}
+
+func (p *javaPrinter) printComponent(f ast.Field) string {
+	name := javaIdent(f.Id)
+	var annotations []string
+	if _, ok := f.Attributes.Get("deprecated"); ok {
+		annotations = append(annotations, "@Deprecated")
+	}
+	if jsonName := fieldJsonName(f); jsonName != name {
+		p.useImport("com.fasterxml.jackson.annotation.JsonProperty")
+		annotations = append(annotations, fmt.Sprintf("@JsonProperty(%s)", printJavaString(jsonName)))
+	}
+	if f.Type.IsOptional() {
+		p.useImport("com.fasterxml.jackson.annotation.JsonInclude")
+		annotations = append(annotations, "@JsonInclude(JsonInclude.Include.NON_NULL)")
+	}
+	if annotation := p.int64Annotation(f.Type); annotation != "" {
+		annotations = append(annotations, annotation)
+	}
+	// Fields with defaults are boxed, to tell when they are absent
+	annotations = append(annotations, p.printType(f.Type, f.Default != nil), name)
+	return strings.Join(annotations, " ")
+}
+
+// int64Annotation returns the annotation which writes the Int64s of a type as
+// strings, Jackson reads them from strings already.
+func (p *javaPrinter) int64Annotation(t ast.Type) string {
+	t = p.resolveAliases(t)
+	if t.TypeIdent != nil && t.TypeIdent.Id == "Int64" {
+		p.useImport("com.fasterxml.jackson.annotation.JsonFormat")
+		return "@JsonFormat(shape = JsonFormat.Shape.STRING)"
+	}
+	var content ast.Type
+	if t.List != nil {
+		content = p.resolveAliases(t.List.Type)
+	} else if t.Map != nil {
+		content = p.resolveAliases(t.Map.Value)
+	}
+	if content.TypeIdent != nil && content.TypeIdent.Id == "Int64" {
+		p.useImport("com.fasterxml.jackson.databind.annotation.JsonSerialize")
+		p.useImport("com.fasterxml.jackson.databind.ser.std.ToStringSerializer")
+		return "@JsonSerialize(contentUsing = ToStringSerializer.class)"
+	}
+	return ""
+}
+
+// printNewType prints a record holding the value, which is encoded as the
+// value.
+func (p *javaPrinter) printNewType(n ast.NewType) string {
+	p.useImport("com.fasterxml.jackson.annotation.JsonCreator")
+	p.useImport("com.fasterxml.jackson.annotation.JsonValue")
+	component := fmt.Sprintf("@JsonValue %s value", p.printType(n.Type, false))
+	var jsonValueString string
+	if t := p.resolveAliases(n.Type); t.TypeIdent != nil && t.TypeIdent.Id == "Int64" {
+		// @JsonFormat does not apply to a @JsonValue
+		component = fmt.Sprintf("%s value", p.printType(n.Type, false))
+		jsonValueString = "\n    @JsonValue\n    public String json() {\n        return Long.toString(value);\n    }\n"
+	}
+	return printJavaDoc(n.Doc, n.Attributes) + fmt.Sprintf("public record %s(%s) {\n    @JsonCreator(mode = JsonCreator.Mode.DELEGATING)\n    public %s {\n    }\n%s}\n", n.Id, component, n.Id, jsonValueString)
+}
+
+func (p *javaPrinter) printSumStr(s ast.SumStr) string {
+	var variantsString string
+	for _, variant := range s.Variants {
+		name := javaIdent(variant.Id)
+		var annotationsString string
+		if _, ok := variant.Attributes.Get("deprecated"); ok {
+			annotationsString += "    @Deprecated\n"
+		}
+		value := variant.Id
+		if variant.JsonName != nil {
+			value = *variant.JsonName
+		}
+		if value != name {
+			p.useImport("com.fasterxml.jackson.annotation.JsonProperty")
+			annotationsString += fmt.Sprintf("    @JsonProperty(%s)\n", printJavaString(value))
+		}
+		variantsString += printJavaVariantDoc(variant.Doc) + annotationsString + fmt.Sprintf("    %s,\n", name)
+	}
+	return printJavaDoc(s.Doc, s.Attributes) + fmt.Sprintf("public enum %s {\n%s}\n", s.Id, variantsString)
+}
+
+// printSumInt prints an enum holding the value of each variant, which is
+// encoded as the value.
+func (p *javaPrinter) printSumInt(s ast.SumInt) string {
+	p.useImport("com.fasterxml.jackson.annotation.JsonCreator")
+	p.useImport("com.fasterxml.jackson.annotation.JsonValue")
+	var variantStrings []string
+	for _, variant := range s.Variants {
+		var annotationsString string
+		if _, ok := variant.Attributes.Get("deprecated"); ok {
+			annotationsString = "    @Deprecated\n"
+		}
+		variantStrings = append(variantStrings, printJavaVariantDoc(variant.Doc)+annotationsString+fmt.Sprintf("    %s(%dL)", javaIdent(variant.Id), variant.Value))
+	}
+	bodyString := fmt.Sprintf(`    private final long value;
+
+    %s(long value) {
+        this.value = value;
+    }
+
+    @JsonValue
+    public long value() {
+        return value;
+    }
+
+    @JsonCreator
+    public static %s of(long value) {
+        for (%s variant : values()) {
+            if (variant.value == value) {
+                return variant;
+            }
+        }
+        throw new IllegalArgumentException(%s + value);
+    }
+`, s.Id, s.Id, s.Id, printJavaString("Unknown "+s.Id+" value "))
+	return printJavaDoc(s.Doc, s.Attributes) + fmt.Sprintf("public enum %s {\n%s;\n\n%s}\n", s.Id, strings.Join(variantStrings, ",\n"), bodyString)
+}
+
+func printJavaTypeParams(params []string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("<%s>", strings.Join(params, ", "))
+}
+
+// printType prints a type, boxing primitives when boxed is set or when the type
+// can be null.
+func (p *javaPrinter) printType(t ast.Type, boxed bool) string {
+	typeString := p.printValueType(t)
+	if boxed || t.IsOptional() || t.IsNullable() {
+		if boxedString, ok := JAVA_BOXED_PRIMITIVES[typeString]; ok {
+			return boxedString
+		}
+	}
+	return typeString
+}
+
+func (p *javaPrinter) printValueType(t ast.Type) string {
+	if t.List != nil {
+		p.useImport("java.util.List")
+		return fmt.Sprintf("List<%s>", p.printType(t.List.Type, true))
+	}
+	if t.Map != nil {
+		p.useImport("java.util.Map")
+		return fmt.Sprintf("Map<%s, %s>", p.printType(t.Map.Key, true), p.printType(t.Map.Value, true))
+	}
+	id := t.TypeIdent.Id
+	switch id {
+	case "Any":
+		p.useImport("com.fasterxml.jackson.databind.JsonNode")
+	case "Object":
+		p.useImport("com.fasterxml.jackson.databind.node.ObjectNode")
+	}
+	if d, ok := p.definitions[id]; ok && d.Alias != nil {
+		return p.printValueType(d.Alias.Type)
+	}
+	if d, ok := p.definitions[id]; ok && d.Extern != nil {
+		return d.Extern.Targets["java"]
+	}
+	typeString, ok := JAVA_PRIMITIVES[id]
+	if !ok {
+		typeString = id
+	}
+	if len(t.TypeIdent.TypeArgs) > 0 {
+		var args []string
+		for _, arg := range t.TypeIdent.TypeArgs {
+			args = append(args, p.printType(arg, true))
+		}
+		typeString += fmt.Sprintf("<%s>", strings.Join(args, ", "))
+	}
+	return typeString
+}
+
+// resolveAliases follows the aliases a type is made of.
+func (p *javaPrinter) resolveAliases(t ast.Type) ast.Type {
+	for i := 0; t.TypeIdent != nil && i < len(p.definitions); i++ {
+		d, ok := p.definitions[t.TypeIdent.Id]
+		if !ok || d.Alias == nil {
+			break
+		}
+		t = d.Alias.Type
+	}
+	return t
+}
+
+// printJavaDoc prints doc lines as a Javadoc comment, with a @Deprecated
+// annotation for @deprecated.
+func printJavaDoc(doc []string, attributes ast.Attributes) string {
+	deprecated, isDeprecated := attributes.Get("deprecated")
+	lines := append([]string{}, doc...)
+	if reason, ok := deprecated.Arg("", 0); isDeprecated && ok {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "@deprecated "+reason)
+	}
+	var docString string
+	if len(lines) > 0 {
+		docString = "/**\n"
+		for _, line := range lines {
+			// Unicode escapes are read even in comments
+			line = strings.ReplaceAll(strings.ReplaceAll(line, "*/", "* /"), `\u`, `\\u`)
+			docString += strings.TrimRight(" * "+line, " ") + "\n"
+		}
+		docString += " */\n"
+	}
+	if isDeprecated {
+		docString += "@Deprecated\n"
+	}
+	return docString
+}
+
+// printJavaVariantDoc prints the doc of an enum constant.
+func printJavaVariantDoc(doc []string) string {
+	if len(doc) == 0 {
+		return ""
+	}
+	return indentJava(printJavaDoc(doc, ast.Attributes{}))
+}
+
+// printJavaString prints s as a Java string literal.
+func printJavaString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func javaIdent(name string) string {
+	if _, ok := javaReservedNames[name]; ok {
+		return name + "_"
+	}
+	return name
+}
+
+// CheckJavaClassName checks the name of the class the definitions of a Java
+// file are nested in, which is the name of the file. It has to be an
+// identifier, and nested classes cannot be named like the class.
+func CheckJavaClassName(name string, ds []ast.Definition) error {
+	if !isJavaClassName(name) {
+		return fmt.Errorf("%s cannot be the name of a Java class, which Java files are named after", name)
+	}
+	for _, d := range ds {
+		if definitionId(d) == name {
+			return fmt.Errorf("%s cannot be the name of the Java class holding the types of the file, as a type is named %s", name, name)
+		}
+	}
+	return nil
+}
+
+func isJavaClassName(name string) bool {
+	if _, ok := javaKeywords[name]; ok || name == "" {
+		return false
+	}
+	if _, ok := javaRestrictedTypeNames[name]; ok {
+		return false
+	}
+	for i, r := range name {
+		if !unicode.IsLetter(r) && r != '_' && r != '$' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printDefault prints the default of a field, wrapped in the newtypes the type
+// of the field is made of.
+func (p *kotlinPrinter) printDefault(f ast.Field) string {
+	newTypes, base := resolveNewTypes(p.definitions, f.Type)
+	d := *f.Default
+	var value string
+	switch d.Kind {
+	case ast.DefaultString:
+		value = printKotlinString(d.Value)
+	case ast.DefaultNumber:
+		value = d.Value
+		if base.TypeIdent != nil {
+			switch base.TypeIdent.Id {
+			case "Float":
+				value += "f"
+			case "Float64":
+				if !strings.ContainsAny(value, ".eE") {
+					value += ".0"
+				}
+			}
+		}
+	case ast.DefaultBool:
+		value = d.Value
+	case ast.DefaultSumStr, ast.DefaultSumInt:
+		value = fmt.Sprintf("%s.%s", d.Enum, kotlinIdent(d.Variant))
+	case ast.DefaultEmptyList:
+		value = "emptyList()"
+	case ast.DefaultEmptyMap:
+		value = "emptyMap()"
+	default:
+		panic("Invalid default")
+	}
+	for i := len(newTypes) - 1; i >= 0; i-- {
+		value = fmt.Sprintf("%s(%s)", newTypes[i], value)
+	}
+	return value
+}
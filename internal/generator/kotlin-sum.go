@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printSum prints a sum as a sealed class with a data class per variant
+// holding its value. Its serializer encodes the variants through their data
+// classes, as {"value": ...}, and moves the value to where the encoding of the
+// sum puts it.
+func (p *kotlinPrinter) printSum(s ast.Sum) string {
+	p.useSerializerImports()
+	for _, imp := range []string{
+		"kotlinx.serialization.Serializable",
+		"kotlinx.serialization.SerializationException",
+		"kotlinx.serialization.json.JsonDecoder",
+		"kotlinx.serialization.json.JsonElement",
+		"kotlinx.serialization.json.JsonEncoder",
+		"kotlinx.serialization.json.JsonObject",
+		"kotlinx.serialization.json.jsonObject",
+	} {
+		p.imports[imp] = struct{}{}
+	}
+	typeParams := printKotlinTypeParams(s.TypeParams)
+	sumType := s.Id + typeParams
+
+	var variantsString string
+	var encodeCasesString string
+	var decodeCasesString string
+	var untaggedAttemptsString string
+	for _, variant := range s.Variants {
+		className := s.Id + capitalizeHead(variant.Id)
+		variantsString += "\n" + printKotlinDoc(variant.Doc, variant.Attributes, "") + fmt.Sprintf("@Serializable\ndata class %s%s(val value: %s) : %s()\n", className, typeParams, p.printType(variant.Type), sumType)
+
+		serializer := fmt.Sprintf("%s.serializer(%s)", className, strings.Join(kotlinSerializerParams(s.TypeParams), ", "))
+		encoded := fmt.Sprintf(`output.json.encodeToJsonElement(%s, value).jsonObject.getValue("value")`, serializer)
+		key := printKotlinString(fieldJsonName(variant))
+		var element string
+		switch s.Encoding {
+		case ast.SumEncodingExternal:
+			element = fmt.Sprintf("JsonObject(mapOf(%s to %s))", key, encoded)
+		case ast.SumEncodingInternal:
+			p.imports["kotlinx.serialization.json.JsonPrimitive"] = struct{}{}
+			element = fmt.Sprintf("JsonObject(mapOf<String, JsonElement>(%s to JsonPrimitive(%s)) + %s.jsonObject)", printKotlinString(s.Tag), key, encoded)
+		case ast.SumEncodingAdjacent:
+			p.imports["kotlinx.serialization.json.JsonPrimitive"] = struct{}{}
+			element = fmt.Sprintf("JsonObject(mapOf<String, JsonElement>(%s to JsonPrimitive(%s), %s to %s))", printKotlinString(s.Tag), key, printKotlinString(s.Content), encoded)
+		case ast.SumEncodingUntagged:
+			element = encoded
+		}
+		encodeCasesString += fmt.Sprintf("                is %s -> %s\n", className, element)
+
+		decoded := fmt.Sprintf(`input.json.decodeFromJsonElement(%s, JsonObject(mapOf("value" to variant)))`, serializer)
+		decodeCasesString += fmt.Sprintf("                %s -> %s\n", key, decoded)
+		untaggedAttemptsString += fmt.Sprintf("            runCatching { return %s }\n", strings.Replace(decoded, `"value" to variant`, `"value" to element`, 1))
+	}
+
+	unknownString := fmt.Sprintf("                else -> throw SerializationException(%s + key)\n", printKotlinString("Unknown "+s.Id+" variant "))
+	var decodeString string
+	switch s.Encoding {
+	case ast.SumEncodingExternal:
+		decodeString = fmt.Sprintf(`            val (key, variant) = element.jsonObject.entries.singleOrNull() ?: throw SerializationException(%s)
+            return when (key) {
+%s%s            }
+`, printKotlinString(s.Id+" must have a single key"), decodeCasesString, unknownString)
+	case ast.SumEncodingInternal:
+		p.imports["kotlinx.serialization.json.jsonPrimitive"] = struct{}{}
+		decodeString = fmt.Sprintf(`            val key = element.jsonObject[%s]?.jsonPrimitive?.content
+            val variant = JsonObject(element.jsonObject - %s)
+            return when (key) {
+%s%s            }
+`, printKotlinString(s.Tag), printKotlinString(s.Tag), decodeCasesString, unknownString)
+	case ast.SumEncodingAdjacent:
+		p.imports["kotlinx.serialization.json.jsonPrimitive"] = struct{}{}
+		decodeString = fmt.Sprintf(`            val key = element.jsonObject[%s]?.jsonPrimitive?.content
+            val variant = element.jsonObject[%s] ?: throw SerializationException(%s)
+            return when (key) {
+%s%s            }
+`, printKotlinString(s.Tag), printKotlinString(s.Content), printKotlinString(s.Id+" is missing "+s.Content), decodeCasesString, unknownString)
+	case ast.SumEncodingUntagged:
+		// The first variant which can be decoded is taken
+		decodeString = untaggedAttemptsString + fmt.Sprintf("            throw SerializationException(%s)\n", printKotlinString(s.Id+" does not match any of its variants"))
+	}
+
+	serializerDeclaration := fmt.Sprintf("object Serializer : KSerializer<%s>", sumType)
+	if len(s.TypeParams) > 0 {
+		// Serializers of generic classes take the serializers of their type
+		// arguments
+		var params []string
+		for i, param := range s.TypeParams {
+			params = append(params, fmt.Sprintf("private val %s: KSerializer<%s>", kotlinSerializerParams(s.TypeParams)[i], param))
+		}
+		serializerDeclaration = fmt.Sprintf("class Serializer%s(%s) : KSerializer<%s>", typeParams, strings.Join(params, ", "), sumType)
+	}
+	serializerString := fmt.Sprintf(`    %s {
+        override val descriptor: SerialDescriptor = JsonElement.serializer().descriptor
+
+        override fun serialize(encoder: Encoder, value: %s) {
+            val output = encoder as JsonEncoder
+            val element = when (value) {
+%s            }
+            output.encodeJsonElement(element)
+        }
+
+        override fun deserialize(decoder: Decoder): %s {
+            val input = decoder as JsonDecoder
+            val element = input.decodeJsonElement()
+%s        }
+    }
+`, serializerDeclaration, sumType, encodeCasesString, sumType, decodeString)
+
+	sumString := printKotlinDoc(s.Doc, s.Attributes, "") + fmt.Sprintf("@Serializable(with = %s.Serializer::class)\nsealed class %s {\n%s}\n", s.Id, sumType, serializerString)
+	return sumString + variantsString
+}
+
+// kotlinSerializerParams are the names of the serializers of type parameters.
+func kotlinSerializerParams(typeParams []string) []string {
+	var params []string
+	for _, param := range typeParams {
+		params = append(params, lowerCaseHead(param)+"Serializer")
+	}
+	return params
+}
@@ -0,0 +1,407 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// KOTLIN_PRIMITIVES maps primitives to Kotlin types. Int64 is a helper type,
+// as it is a string in JSON, and the other types which are strings in JSON are
+// Strings.
+var KOTLIN_PRIMITIVES map[string]string = map[string]string{
+	"Float":    "Float",
+	"Float64":  "Double",
+	"Str":      "String",
+	"Bool":     "Boolean",
+	"Int":      "Long",
+	"Int32":    "Int",
+	"Int64":    "Int64",
+	"Any":      "JsonElement",
+	"Object":   "JsonObject",
+	"Decimal":  "String",
+	"UUID":     "String",
+	"Bytes":    "String",
+	"Date":     "String",
+	"DateTime": "String",
+	"Duration": "String",
+}
+
+// kotlinKeywords are escaped with backticks when used as names.
+var kotlinKeywords = map[string]struct{}{
+	"as": {}, "break": {}, "class": {}, "continue": {}, "do": {}, "else": {},
+	"false": {}, "for": {}, "fun": {}, "if": {}, "in": {}, "interface": {},
+	"is": {}, "null": {}, "object": {}, "package": {}, "return": {},
+	"super": {}, "this": {}, "throw": {}, "true": {}, "try": {},
+	"typealias": {}, "typeof": {}, "val": {}, "var": {}, "when": {},
+	"while": {},
+}
+
+const kotlinInt64Helper = `/**
+ * A Long which is a string in JSON, as JavaScript numbers cannot hold every
+ * Long.
+ */
+typealias Int64 = @Serializable(with = Int64Serializer::class) Long
+
+object Int64Serializer : KSerializer<Long> {
+    override val descriptor: SerialDescriptor = PrimitiveSerialDescriptor("Int64", PrimitiveKind.STRING)
+
+    override fun serialize(encoder: Encoder, value: Long) = encoder.encodeString(value.toString())
+
+    override fun deserialize(decoder: Decoder): Long = decoder.decodeString().toLong()
+}
+`
+
+const kotlinOptionalHelper = `/**
+ * A property which can be absent, null or have a value. Absent properties are
+ * the default, which is left out when encodeDefaults is false, as it is by
+ * default.
+ */
+@Serializable(with = OptionalSerializer::class)
+sealed interface Optional<out T> {
+    object Absent : Optional<Nothing>
+
+    data class Present<out T>(val value: T) : Optional<T>
+}
+
+class OptionalSerializer<T>(private val valueSerializer: KSerializer<T>) : KSerializer<Optional<T>> {
+    override val descriptor: SerialDescriptor = valueSerializer.descriptor
+
+    override fun serialize(encoder: Encoder, value: Optional<T>) = when (value) {
+        is Optional.Present -> encoder.encodeSerializableValue(valueSerializer, value.value)
+        Optional.Absent -> throw SerializationException("Absent properties are left out by not encoding defaults")
+    }
+
+    override fun deserialize(decoder: Decoder): Optional<T> = Optional.Present(decoder.decodeSerializableValue(valueSerializer))
+}
+`
+
+type KotlinGeneratorOptions struct {
+	// Package of the generated code, the default package if empty
+	PackageName string
+	// Packages of the source files the file being generated imports, by path
+	Packages map[string]string
+	// Definitions of every file being generated, to resolve the aliases and
+	// newtypes of types from other files
+	Definitions []ast.Definition
+	// Helper types that are already declared in the package, by another file
+	DeclaredHelpers map[string]struct{}
+}
+
+// kotlinPrinter prints definitions, keeping track of what the printed code uses
+// to add the imports and helpers it needs.
+type kotlinPrinter struct {
+	definitions  map[string]ast.Definition
+	imports      map[string]struct{}
+	usesInt64    bool
+	usesOptional bool
+}
+
+func newKotlinPrinter(options KotlinGeneratorOptions) *kotlinPrinter {
+	return &kotlinPrinter{
+		definitions: definitionsById(options.Definitions),
+		imports:     make(map[string]struct{}),
+	}
+}
+
+func PrintKotlinDefinitions(ds []ast.Definition, options KotlinGeneratorOptions) string {
+	return newKotlinPrinter(options).printFile(ds, nil, options)
+}
+
+// PrintKotlinFile prints the definitions of a single source file, importing the
+// types it uses from other source files from their packages.
+func PrintKotlinFile(f ast.File, options KotlinGeneratorOptions) string {
+	return newKotlinPrinter(options).printFile(f.Definitions, f.Imports, options)
+}
+
+// KotlinHelpers returns the helper types the definitions need.
+func KotlinHelpers(ds []ast.Definition, options KotlinGeneratorOptions) []string {
+	p := newKotlinPrinter(options)
+	for _, d := range ds {
+		p.printDefinition(d)
+	}
+	return p.helpers()
+}
+
+func (p *kotlinPrinter) helpers() []string {
+	var helpers []string
+	if p.usesInt64 {
+		helpers = append(helpers, "Int64")
+	}
+	if p.usesOptional {
+		helpers = append(helpers, "Optional")
+	}
+	return helpers
+}
+
+func (p *kotlinPrinter) printFile(ds []ast.Definition, imports []ast.Import, options KotlinGeneratorOptions) string {
+	var definitionStrings []string
+	for _, d := range ds {
+		definitionStrings = append(definitionStrings, p.printDefinition(d))
+	}
+	if _, ok := options.DeclaredHelpers["Int64"]; p.usesInt64 && !ok {
+		p.useSerializerImports()
+		p.imports["kotlinx.serialization.Serializable"] = struct{}{}
+		p.imports["kotlinx.serialization.descriptors.PrimitiveKind"] = struct{}{}
+		p.imports["kotlinx.serialization.descriptors.PrimitiveSerialDescriptor"] = struct{}{}
+		definitionStrings = append(definitionStrings, kotlinInt64Helper)
+	}
+	if _, ok := options.DeclaredHelpers["Optional"]; p.usesOptional && !ok {
+		p.useSerializerImports()
+		p.imports["kotlinx.serialization.Serializable"] = struct{}{}
+		p.imports["kotlinx.serialization.SerializationException"] = struct{}{}
+		definitionStrings = append(definitionStrings, kotlinOptionalHelper)
+	}
+
+	for _, imp := range imports {
+		// Files of the same package see each other's types
+		if pkg := options.Packages[imp.Path]; pkg != options.PackageName {
+			for _, id := range imp.Ids {
+				p.imports[pkg+"."+id] = struct{}{}
+			}
+		}
+	}
+	var importLines []string
+	for imp := range p.imports {
+		importLines = append(importLines, "import "+imp)
+	}
+	sort.Strings(importLines)
+
+	var headerString string
+	if options.PackageName != "" {
+		headerString = fmt.Sprintf("package %s\n\n", options.PackageName)
+	}
+	if len(importLines) > 0 {
+		headerString += strings.Join(importLines, "\n") + "\n\n"
+	}
+	return headerString + strings.Join(definitionStrings, "\n")
+}
+
+// useSerializerImports adds the imports of a hand written serializer.
+func (p *kotlinPrinter) useSerializerImports() {
+	for _, imp := range []string{
+		"kotlinx.serialization.KSerializer",
+		"kotlinx.serialization.descriptors.SerialDescriptor",
+		"kotlinx.serialization.encoding.Decoder",
+		"kotlinx.serialization.encoding.Encoder",
+	} {
+		p.imports[imp] = struct{}{}
+	}
+}
+
+func (p *kotlinPrinter) printDefinition(d ast.Definition) string {
+	if d.SumStr != nil {
+		return p.printSumStr(*d.SumStr)
+	}
+	if d.SumInt != nil {
+		return p.printSumInt(*d.SumInt)
+	}
+	if d.Alias != nil {
+		return printKotlinDoc(d.Alias.Doc, d.Alias.Attributes, "") + fmt.Sprintf("typealias %s = %s\n", d.Alias.Id, p.printType(d.Alias.Type))
+	}
+	if d.NewType != nil {
+		p.imports["kotlinx.serialization.Serializable"] = struct{}{}
+		n := *d.NewType
+		return printKotlinDoc(n.Doc, n.Attributes, "") + fmt.Sprintf("@Serializable\n@JvmInline\nvalue class %s(val value: %s)\n", n.Id, p.printType(n.Type))
+	}
+	if d.Extern != nil {
+		return printKotlinDoc(d.Extern.Doc, d.Extern.Attributes, "") + fmt.Sprintf("typealias %s = %s\n", d.Extern.Id, d.Extern.Targets["kt"])
+	}
+	if d.Sum != nil {
+		return p.printSum(*d.Sum)
+	}
+	if d.Product != nil {
+		return p.printProduct(*d.Product)
+	}
+	panic("Invalid definition")
+}
+
+func (p *kotlinPrinter) printProduct(prod ast.Product) string {
+	p.imports["kotlinx.serialization.Serializable"] = struct{}{}
+	docString := printKotlinDoc(prod.Doc, prod.Attributes, "")
+	fields := prod.AllFields()
+	if len(fields) == 0 {
+		// Data classes need a property
+		return docString + fmt.Sprintf("@Serializable\nclass %s%s\n", prod.Id, printKotlinTypeParams(prod.TypeParams))
+	}
+	// Inherited fields are copied in, as data classes cannot extend each other
+	var fieldsString string
+	var optInString string
+	for _, f := range fields {
+		fieldsString += p.printField(f)
+		if f.Default != nil {
+			// EncodeDefault is experimental
+			p.imports["kotlinx.serialization.ExperimentalSerializationApi"] = struct{}{}
+			optInString = "@OptIn(ExperimentalSerializationApi::class)\n"
+		}
+	}
+	return docString + optInString + fmt.Sprintf("@Serializable\ndata class %s%s(\n%s)\n", prod.Id, printKotlinTypeParams(prod.TypeParams), fieldsString)
+}
+
+func (p *kotlinPrinter) printField(f ast.Field) string {
+	var serialNameString string
+	if jsonName := fieldJsonName(f); jsonName != f.Id {
+		p.imports["kotlinx.serialization.SerialName"] = struct{}{}
+		serialNameString = fmt.Sprintf("    @SerialName(%s)\n", printKotlinString(jsonName))
+	}
+	fieldString := fmt.Sprintf("    val %s: %s", kotlinIdent(f.Id), p.printType(f.Type))
+	if f.Default != nil {
+		// Defaults are written, as other targets need the fields, leaving
+		// encodeDefaults false for absent Optionals
+		p.imports["kotlinx.serialization.EncodeDefault"] = struct{}{}
+		fieldString = "    @EncodeDefault\n" + fieldString + " = " + p.printDefault(f)
+	} else if f.Type.IsOptional() && f.Type.IsNullable() {
+		// null is a value, so absent is told apart by an Optional
+		p.usesOptional = true
+		fieldString = fmt.Sprintf("    val %s: Optional<%s> = Optional.Absent", kotlinIdent(f.Id), p.printType(f.Type))
+	} else if f.Type.IsOptional() {
+		// Properties with their default value are left out when encoding
+		fieldString += " = null"
+	}
+	return printKotlinDoc(f.Doc, f.Attributes, "    ") + serialNameString + fieldString + ",\n"
+}
+
+func (p *kotlinPrinter) printSumStr(s ast.SumStr) string {
+	p.imports["kotlinx.serialization.Serializable"] = struct{}{}
+	var variantsString string
+	for _, variant := range s.Variants {
+		var serialNameString string
+		if variant.JsonName != nil && *variant.JsonName != variant.Id {
+			p.imports["kotlinx.serialization.SerialName"] = struct{}{}
+			serialNameString = fmt.Sprintf("    @SerialName(%s)\n", printKotlinString(*variant.JsonName))
+		}
+		variantsString += printKotlinDoc(variant.Doc, variant.Attributes, "    ") + serialNameString + fmt.Sprintf("    %s,\n", kotlinIdent(variant.Id))
+	}
+	return printKotlinDoc(s.Doc, s.Attributes, "") + fmt.Sprintf("@Serializable\nenum class %s {\n%s}\n", s.Id, variantsString)
+}
+
+// printSumInt prints an enum class holding the value of each variant, with a
+// serializer which encodes the variants as their values.
+func (p *kotlinPrinter) printSumInt(s ast.SumInt) string {
+	p.useSerializerImports()
+	p.imports["kotlinx.serialization.Serializable"] = struct{}{}
+	p.imports["kotlinx.serialization.SerializationException"] = struct{}{}
+	p.imports["kotlinx.serialization.descriptors.PrimitiveKind"] = struct{}{}
+	p.imports["kotlinx.serialization.descriptors.PrimitiveSerialDescriptor"] = struct{}{}
+	var variantsString string
+	for _, variant := range s.Variants {
+		variantsString += printKotlinDoc(variant.Doc, variant.Attributes, "    ") + fmt.Sprintf("    %s(%d),\n", kotlinIdent(variant.Id), variant.Value)
+	}
+	serializerString := fmt.Sprintf(`    object Serializer : KSerializer<%s> {
+        override val descriptor: SerialDescriptor = PrimitiveSerialDescriptor(%s, PrimitiveKind.LONG)
+
+        override fun serialize(encoder: Encoder, value: %s) = encoder.encodeLong(value.value)
+
+        override fun deserialize(decoder: Decoder): %s {
+            val value = decoder.decodeLong()
+            return %s.values().firstOrNull { it.value == value } ?: throw SerializationException(%s + value)
+        }
+    }
+`, s.Id, printKotlinString(s.Id), s.Id, s.Id, s.Id, printKotlinString("Unknown "+s.Id+" value "))
+	return printKotlinDoc(s.Doc, s.Attributes, "") + fmt.Sprintf("@Serializable(with = %s.Serializer::class)\nenum class %s(val value: Long) {\n%s    ;\n\n%s}\n", s.Id, s.Id, variantsString, serializerString)
+}
+
+func printKotlinTypeParams(params []string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("<%s>", strings.Join(params, ", "))
+}
+
+func (p *kotlinPrinter) printType(t ast.Type) string {
+	typeString := p.printValueType(t)
+	if t.IsOptional() || t.IsNullable() {
+		return typeString + "?"
+	}
+	return typeString
+}
+
+func (p *kotlinPrinter) printValueType(t ast.Type) string {
+	if t.List != nil {
+		return fmt.Sprintf("List<%s>", p.printType(t.List.Type))
+	}
+	if t.Map != nil {
+		return fmt.Sprintf("Map<%s, %s>", p.printType(t.Map.Key), p.printType(t.Map.Value))
+	}
+	id := t.TypeIdent.Id
+	switch id {
+	case "Int64":
+		p.usesInt64 = true
+	case "Any":
+		p.imports["kotlinx.serialization.json.JsonElement"] = struct{}{}
+	case "Object":
+		p.imports["kotlinx.serialization.json.JsonObject"] = struct{}{}
+	}
+	typeString, ok := KOTLIN_PRIMITIVES[id]
+	if !ok {
+		typeString = id
+	}
+	if len(t.TypeIdent.TypeArgs) > 0 {
+		var args []string
+		for _, arg := range t.TypeIdent.TypeArgs {
+			args = append(args, p.printType(arg))
+		}
+		typeString += fmt.Sprintf("<%s>", strings.Join(args, ", "))
+	}
+	return typeString
+}
+
+// printKotlinDoc prints doc lines as a KDoc comment, with a @Deprecated
+// annotation for @deprecated.
+func printKotlinDoc(doc []string, attributes ast.Attributes, indent string) string {
+	var docString string
+	if len(doc) > 0 {
+		docString = indent + "/**\n"
+		for _, line := range doc {
+			docString += strings.TrimRight(indent+" * "+strings.ReplaceAll(line, "*/", "* /"), " ") + "\n"
+		}
+		docString += indent + " */\n"
+	}
+	if deprecated, ok := attributes.Get("deprecated"); ok {
+		reason, ok := deprecated.Arg("", 0)
+		if !ok {
+			reason = "Deprecated"
+		}
+		docString += indent + fmt.Sprintf("@Deprecated(%s)\n", printKotlinString(reason))
+	}
+	return docString
+}
+
+// printKotlinString prints s as a Kotlin string literal.
+func printKotlinString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '$':
+			b.WriteString(`\$`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func kotlinIdent(name string) string {
+	if _, ok := kotlinKeywords[name]; ok {
+		return "`" + name + "`"
+	}
+	return name
+}
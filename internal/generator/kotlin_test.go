@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brahms116/between/internal/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+const jvmTestPackage = "com.example.api"
+
+func TestKotlinGolden(t *testing.T) {
+	for _, entry := range []string{"testdata/golden/api.bt", "testdata/golden/patch.bt"} {
+		files, _ := translateFile(t, entry, "kt")
+		packages := make(map[string]string)
+		for _, f := range files {
+			packages[f.Path] = jvmTestPackage
+		}
+		declaredHelpers := make(map[string]struct{})
+		for _, f := range files {
+			options := KotlinGeneratorOptions{
+				PackageName:     jvmTestPackage,
+				Packages:        packages,
+				Definitions:     ast.Definitions(files),
+				DeclaredHelpers: declaredHelpers,
+			}
+			assertGolden(t, f.Path, "kt", PrintKotlinFile(f, options))
+			for _, helper := range KotlinHelpers(f.Definitions, options) {
+				declaredHelpers[helper] = struct{}{}
+			}
+		}
+	}
+}
+
+func TestJavaGolden(t *testing.T) {
+	// Java cannot have fields which are optional and nullable, as in patch.bt
+	files, _ := translateFile(t, "testdata/golden/api.bt", "java")
+	for _, f := range files {
+		var importedClasses []string
+		for _, other := range files {
+			if other.Path != f.Path {
+				importedClasses = append(importedClasses, jvmTestPackage+"."+javaTestClassName(other.Path))
+			}
+		}
+		assertGolden(t, f.Path, "java", PrintJavaDefinitions(f.Definitions, JavaGeneratorOptions{
+			PackageName:     jvmTestPackage,
+			ClassName:       javaTestClassName(f.Path),
+			ImportedClasses: importedClasses,
+			Definitions:     ast.Definitions(files),
+		}))
+	}
+}
+
+// javaTestClassName is the class of a file, named after the file as its
+// generated file is.
+func javaTestClassName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+func TestCheckJavaClassName(t *testing.T) {
+	ds := []ast.Definition{{Product: &ast.Product{Id: "User"}}}
+	assert.NoError(t, CheckJavaClassName("Api", ds))
+	assert.NoError(t, CheckJavaClassName("api_v2", ds))
+	assert.EqualError(t, CheckJavaClassName("my-api", ds), "my-api cannot be the name of a Java class, which Java files are named after")
+	assert.EqualError(t, CheckJavaClassName("2api", ds), "2api cannot be the name of a Java class, which Java files are named after")
+	assert.EqualError(t, CheckJavaClassName("class", ds), "class cannot be the name of a Java class, which Java files are named after")
+	assert.EqualError(t, CheckJavaClassName("record", ds), "record cannot be the name of a Java class, which Java files are named after")
+	assert.EqualError(t, CheckJavaClassName("User", ds), "User cannot be the name of the Java class holding the types of the file, as a type is named User")
+}
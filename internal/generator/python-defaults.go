@@ -0,0 +1,28 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printPyDefault prints the default of a field which is not an empty list or
+// map, which are given as default factories instead. Defaults are validated,
+// so strings become the dates or decimals of their fields.
+func printPyDefault(d ast.Default) string {
+	switch d.Kind {
+	case ast.DefaultString:
+		return strconv.Quote(d.Value)
+	case ast.DefaultNumber:
+		return d.Value
+	case ast.DefaultBool:
+		if d.Value == "true" {
+			return "True"
+		}
+		return "False"
+	case ast.DefaultSumStr, ast.DefaultSumInt:
+		return fmt.Sprintf("%s.%s", d.Enum, pyIdent(d.Variant, pyKeywords))
+	}
+	panic("Invalid default")
+}
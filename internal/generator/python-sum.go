@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printSum prints a sum as a Union. Tagged sums get a model per variant,
+// holding the variant under its key for externally tagged sums, adding the
+// tag to the variant for internally tagged sums, and holding the tag and the
+// variant for adjacently tagged ones, which pydantic tells apart by the tag.
+func (p *pyPrinter) printSum(s ast.Sum) string {
+	var modelsString string
+	var members []string
+	tagName := pyKeyName(s.Tag, "tag")
+	p.quotedPending = false
+	for _, variant := range s.Variants {
+		if s.Encoding == ast.SumEncodingUntagged {
+			members = append(members, p.printType(variant.Type, true))
+			continue
+		}
+		id := s.Id + capitalizeHead(variant.Id)
+		member := id
+		if len(s.TypeParams) > 0 {
+			member += fmt.Sprintf("[%s]", strings.Join(s.TypeParams, ", "))
+		}
+		members = append(members, member)
+
+		var config []string
+		var fieldsString string
+		base := "BaseModel"
+		switch s.Encoding {
+		case ast.SumEncodingExternal:
+			p.useImport("pydantic", "BaseModel")
+			// Other keys would be variants of their own
+			config = append(config, `extra="forbid"`)
+			fieldsString = p.printField(variant)
+		case ast.SumEncodingInternal:
+			base = p.printType(variant.Type, true)
+			fieldsString = p.printTagField(tagName, s.Tag, fieldJsonName(variant))
+		case ast.SumEncodingAdjacent:
+			p.useImport("pydantic", "BaseModel")
+			fieldsString = p.printTagField(tagName, s.Tag, fieldJsonName(variant))
+			contentField := ast.Field{Id: pyKeyName(s.Content, "content"), JsonName: &s.Content, Type: variant.Type}
+			fieldsString += p.printField(contentField)
+		}
+		if strings.Contains(fieldsString, "alias=") {
+			config = append(config, "populate_by_name=True")
+		}
+		modelsString += p.printClass(id, base, s.TypeParams, variant.Doc, variant.Attributes, config, fieldsString) + "\n\n"
+	}
+
+	p.useImport("typing", "Union")
+	union := fmt.Sprintf("Union[%s]", strings.Join(members, ", "))
+	isTagged := s.Encoding == ast.SumEncodingInternal || s.Encoding == ast.SumEncodingAdjacent
+	if isTagged && len(members) > 1 {
+		p.useImport("typing", "Annotated")
+		p.useImport("pydantic", "Field")
+		union = fmt.Sprintf("Annotated[%s, Field(discriminator=%s)]", union, strconv.Quote(tagName))
+	}
+
+	unionString := fmt.Sprintf("%s = %s\n", s.Id, union)
+	if len(s.TypeParams) > 0 {
+		// A Union of type variables takes them in the order they appear in, so
+		// they are given explicitly
+		p.useImport("typing_extensions", "TypeAliasType")
+		typeParams := strings.Join(s.TypeParams, ", ")
+		if len(s.TypeParams) == 1 {
+			typeParams += ","
+		}
+		unionString = fmt.Sprintf("%s = TypeAliasType(%s, %s, type_params=(%s))\n", s.Id, strconv.Quote(s.Id), union, typeParams)
+	} else if p.quotedPending {
+		// pydantic only validates unions which refer to themselves when they
+		// are named
+		p.useImport("typing_extensions", "TypeAliasType")
+		unionString = fmt.Sprintf("%s = TypeAliasType(%s, %s)\n", s.Id, strconv.Quote(s.Id), union)
+	}
+	return modelsString + printPyComments(s.Doc, s.Attributes, "") + unionString
+}
+
+// printTagField prints the field holding the tag of a variant, which has to
+// be given when constructing it so it is kept when dumping only the fields
+// which were set.
+func (p *pyPrinter) printTagField(name string, tag string, value string) string {
+	p.useImport("typing", "Literal")
+	fieldString := fmt.Sprintf("    %s: Literal[%s]", name, strconv.Quote(value))
+	if name != tag {
+		p.useImport("pydantic", "Field")
+		fieldString += fmt.Sprintf(" = Field(alias=%s)", strconv.Quote(tag))
+	}
+	return fieldString + "\n"
+}
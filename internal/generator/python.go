@@ -0,0 +1,622 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// PY_PRIMITIVES maps primitives to Python types. Int64 is a helper type, as it
+// is a string in JSON. Durations are strings as pydantic encodes timedeltas in
+// ISO 8601, and bytes are base64 strings.
+var PY_PRIMITIVES map[string]string = map[string]string{
+	"Float":    "float",
+	"Float64":  "float",
+	"Str":      "str",
+	"Bool":     "bool",
+	"Int":      "int",
+	"Int32":    "int",
+	"Int64":    "Int64",
+	"Any":      "Any",
+	"Object":   "dict[str, Any]",
+	"Decimal":  "Decimal",
+	"UUID":     "UUID",
+	"Bytes":    "str",
+	"Date":     "date",
+	"DateTime": "datetime",
+	"Duration": "str",
+}
+
+// pyPrimitiveImports are the modules and names the Python types of primitives
+// are imported from.
+var pyPrimitiveImports = map[string][2]string{
+	"Any":      {"typing", "Any"},
+	"Object":   {"typing", "Any"},
+	"Decimal":  {"decimal", "Decimal"},
+	"UUID":     {"uuid", "UUID"},
+	"Date":     {"datetime", "date"},
+	"DateTime": {"datetime", "datetime"},
+}
+
+// pyStdlibModules are imported before the third party modules.
+var pyStdlibModules = map[string]struct{}{
+	"datetime": {}, "decimal": {}, "enum": {}, "typing": {}, "uuid": {},
+}
+
+var pyKeywords = map[string]struct{}{
+	"False": {}, "None": {}, "True": {}, "and": {}, "as": {}, "assert": {},
+	"async": {}, "await": {}, "break": {}, "class": {}, "continue": {},
+	"def": {}, "del": {}, "elif": {}, "else": {}, "except": {}, "finally": {},
+	"for": {}, "from": {}, "global": {}, "if": {}, "import": {}, "in": {},
+	"is": {}, "lambda": {}, "nonlocal": {}, "not": {}, "or": {}, "pass": {},
+	"raise": {}, "return": {}, "try": {}, "while": {}, "with": {}, "yield": {},
+}
+
+// pyReservedFieldNames are suffixed with an underscore when used as field
+// names. Besides keywords, these are the attributes of BaseModel, which fields
+// cannot shadow, and the lowercase names used in annotations, which a field
+// with a default would shadow in the class body.
+var pyReservedFieldNames = map[string]struct{}{
+	"construct": {}, "copy": {}, "from_orm": {}, "json": {}, "parse_file": {},
+	"parse_obj": {}, "parse_raw": {}, "schema": {}, "schema_json": {},
+	"update_forward_refs": {}, "validate": {}, "model_computed_fields": {},
+	"model_config": {}, "model_construct": {}, "model_copy": {},
+	"model_dump": {}, "model_dump_json": {}, "model_extra": {},
+	"model_fields": {}, "model_fields_set": {}, "model_json_schema": {},
+	"model_parametrized_name": {}, "model_post_init": {}, "model_rebuild": {},
+	"model_validate": {}, "model_validate_json": {}, "model_validate_strings": {},
+	"str": {}, "int": {}, "float": {}, "bool": {}, "list": {}, "dict": {},
+	"date": {}, "datetime": {},
+}
+
+var pyIdentifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+const pyInt64Helper = `# An int which is a string in JSON, as JavaScript numbers cannot hold every
+# 64 bit integer.
+Int64 = Annotated[int, PlainSerializer(str, return_type=str, when_used="json")]
+`
+
+// pyPrinter prints definitions, keeping track of what the printed code uses
+// to add the imports it needs.
+type pyPrinter struct {
+	// definitions of the output which have not been printed yet, which are
+	// quoted where they are evaluated
+	pending map[string]struct{}
+	// the names imported from each module
+	imports map[string]map[string]struct{}
+	// the modules of externs, which are imported whole
+	modules  map[string]struct{}
+	typeVars map[string]struct{}
+
+	usesInt64 bool
+	// whether the class being printed refers to a pending definition
+	refersToPending bool
+	// whether the type being printed quoted a pending definition
+	quotedPending bool
+	// the classes to rebuild once every definition has been printed
+	rebuilds []string
+}
+
+func newPyPrinter() *pyPrinter {
+	return &pyPrinter{
+		pending:  make(map[string]struct{}),
+		imports:  make(map[string]map[string]struct{}),
+		modules:  make(map[string]struct{}),
+		typeVars: make(map[string]struct{}),
+	}
+}
+
+func PrintPyDefinitions(ds []ast.Definition) string {
+	return newPyPrinter().printFile(ds, "")
+}
+
+// PrintPyFile prints the definitions of a single source file as a module of a
+// package, importing the types it uses from other source files from their
+// sibling modules.
+func PrintPyFile(f ast.File) string {
+	var importsString string
+	for _, imp := range f.Imports {
+		importsString += fmt.Sprintf("from %s import %s\n", pyModulePath(f.Path, imp.Path), strings.Join(imp.Ids, ", "))
+	}
+	return newPyPrinter().printFile(f.Definitions, importsString)
+}
+
+// pyModulePath returns the relative import path from the module generated for
+// the source file at from, to the one generated for to.
+func pyModulePath(from string, to string) string {
+	rel, err := filepath.Rel(filepath.Dir(from), to)
+	if err != nil {
+		rel = to
+	}
+	rel = filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+	dots := "."
+	var segments []string
+	for _, segment := range strings.Split(rel, "/") {
+		if segment == ".." {
+			dots += "."
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	return dots + strings.Join(segments, ".")
+}
+
+func (p *pyPrinter) printFile(ds []ast.Definition, localImportsString string) string {
+	ordered := pyOrder(ds)
+	for _, d := range ordered {
+		p.pending[definitionId(d)] = struct{}{}
+	}
+	var definitionStrings []string
+	for _, d := range ordered {
+		definitionStrings = append(definitionStrings, p.printDefinition(d))
+		delete(p.pending, definitionId(d))
+	}
+	var rebuildsString string
+	for _, id := range p.rebuilds {
+		rebuildsString += fmt.Sprintf("%s.model_rebuild()\n", id)
+	}
+	if rebuildsString != "" {
+		definitionStrings = append(definitionStrings, rebuildsString)
+	}
+
+	var typeVarsString string
+	var typeVars []string
+	for typeVar := range p.typeVars {
+		typeVars = append(typeVars, typeVar)
+	}
+	sort.Strings(typeVars)
+	for _, typeVar := range typeVars {
+		p.useImport("typing", "TypeVar")
+		typeVarsString += fmt.Sprintf("%s = TypeVar(%s)\n", typeVar, strconv.Quote(typeVar))
+	}
+	if p.usesInt64 {
+		p.useImport("typing", "Annotated")
+		p.useImport("pydantic", "PlainSerializer")
+	}
+
+	sections := []string{"from __future__ import annotations\n"}
+	for _, section := range []string{p.printImports(true), p.printImports(false), localImportsString, typeVarsString} {
+		if section != "" {
+			sections = append(sections, section)
+		}
+	}
+	if p.usesInt64 {
+		sections = append(sections, pyInt64Helper)
+	}
+	return strings.Join(sections, "\n") + "\n\n" + strings.Join(definitionStrings, "\n\n")
+}
+
+func (p *pyPrinter) useImport(module string, name string) {
+	if p.imports[module] == nil {
+		p.imports[module] = make(map[string]struct{})
+	}
+	p.imports[module][name] = struct{}{}
+}
+
+// printImports prints the imports of either the standard library or the third
+// party modules.
+func (p *pyPrinter) printImports(stdlib bool) string {
+	var lines []string
+	for module := range p.modules {
+		if _, ok := pyStdlibModules[module]; ok == stdlib {
+			lines = append(lines, fmt.Sprintf("import %s", module))
+		}
+	}
+	for module, names := range p.imports {
+		if _, ok := pyStdlibModules[module]; ok != stdlib {
+			continue
+		}
+		var nameList []string
+		for name := range names {
+			nameList = append(nameList, name)
+		}
+		sort.Strings(nameList)
+		lines = append(lines, fmt.Sprintf("from %s import %s", module, strings.Join(nameList, ", ")))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	// Plain imports come first, as isort orders them
+	sort.Strings(lines)
+	sort.SliceStable(lines, func(i, j int) bool {
+		return strings.HasPrefix(lines[i], "import ") && !strings.HasPrefix(lines[j], "import ")
+	})
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (p *pyPrinter) printDefinition(d ast.Definition) string {
+	if d.SumStr != nil {
+		return p.printSumStr(*d.SumStr)
+	}
+	if d.SumInt != nil {
+		return p.printSumInt(*d.SumInt)
+	}
+	if d.Alias != nil {
+		return printPyComments(d.Alias.Doc, d.Alias.Attributes, "") + fmt.Sprintf("%s = %s\n", d.Alias.Id, p.printType(d.Alias.Type, true))
+	}
+	if d.NewType != nil {
+		return p.printNewType(*d.NewType)
+	}
+	if d.Extern != nil {
+		target := d.Extern.Targets["py"]
+		if i := strings.LastIndex(target, "."); i >= 0 {
+			p.modules[target[:i]] = struct{}{}
+		}
+		return printPyComments(d.Extern.Doc, d.Extern.Attributes, "") + fmt.Sprintf("%s = %s\n", d.Extern.Id, target)
+	}
+	if d.Sum != nil {
+		return p.printSum(*d.Sum)
+	}
+	if d.Product != nil {
+		return p.printProduct(*d.Product)
+	}
+	panic("Invalid definition")
+}
+
+func (p *pyPrinter) printProduct(prod ast.Product) string {
+	base := "BaseModel"
+	if prod.Extends != nil {
+		base = p.printType(ast.Type{TypeIdent: prod.Extends}, true)
+	} else {
+		p.useImport("pydantic", "BaseModel")
+	}
+
+	var config []string
+	var fieldsString string
+	p.refersToPending = false
+	for _, f := range prod.Fields {
+		if fieldJsonName(f) != pyFieldName(f.Id) && !containsString(config, "populate_by_name=True") {
+			config = append(config, "populate_by_name=True")
+		}
+		if f.Default != nil && !containsString(config, "validate_default=True") {
+			// Defaults are validated to convert them to the types of their
+			// fields, such as strings to dates
+			config = append(config, "validate_default=True")
+		}
+		fieldsString += p.printField(f)
+	}
+	fieldsString += p.printOptionalFieldsSerializer(prod)
+	return p.printClass(prod.Id, base, prod.TypeParams, prod.Doc, prod.Attributes, config, fieldsString)
+}
+
+// printOptionalFieldsSerializer prints a serializer leaving out the optional
+// fields which were not given, rather than writing them as null, and the ones
+// which cannot be null when they are None. Products inherit the serializer of
+// the product they extend when they have no optional fields of their own.
+func (p *pyPrinter) printOptionalFieldsSerializer(prod ast.Product) string {
+	hasOwn := false
+	for _, f := range prod.Fields {
+		hasOwn = hasOwn || f.Type.IsOptional()
+	}
+	if !hasOwn {
+		return ""
+	}
+	var fieldStrings []string
+	for _, f := range prod.AllFields() {
+		if !f.Type.IsOptional() {
+			continue
+		}
+		nullable := "False"
+		if f.Type.IsNullable() {
+			nullable = "True"
+		}
+		fieldStrings = append(fieldStrings, fmt.Sprintf("(%s, %s, %s)", strconv.Quote(pyFieldName(f.Id)), strconv.Quote(fieldJsonName(f)), nullable))
+	}
+	fieldsString := strings.Join(fieldStrings, ", ")
+	if len(fieldStrings) == 1 {
+		fieldsString += ","
+	}
+	p.useImport("typing", "Any")
+	p.useImport("pydantic", "SerializationInfo")
+	p.useImport("pydantic", "SerializerFunctionWrapHandler")
+	p.useImport("pydantic", "model_serializer")
+	return fmt.Sprintf(`
+    @model_serializer(mode="wrap")
+    def _leave_out_absent_fields(self, handler: SerializerFunctionWrapHandler, info: SerializationInfo) -> Any:
+        data = handler(self)
+        for name, alias, nullable in (%s):
+            if name not in self.model_fields_set or (not nullable and getattr(self, name) is None):
+                data.pop(alias if info.by_alias else name, None)
+        return data
+`, fieldsString)
+}
+
+// printClass prints a class with a docstring, a model config if there is any
+// and a body, noting it to be rebuilt if its fields refer to definitions which
+// are printed after it.
+func (p *pyPrinter) printClass(id string, base string, typeParams []string, doc []string, attributes ast.Attributes, config []string, bodyString string) string {
+	bases := base
+	if len(typeParams) > 0 {
+		p.useImport("typing", "Generic")
+		for _, param := range typeParams {
+			p.typeVars[param] = struct{}{}
+		}
+		bases += fmt.Sprintf(", Generic[%s]", strings.Join(typeParams, ", "))
+	}
+	classString := fmt.Sprintf("class %s(%s):\n", id, bases) + printPyDocstring(doc, attributes)
+	if len(config) > 0 {
+		p.useImport("pydantic", "ConfigDict")
+		classString += fmt.Sprintf("    model_config = ConfigDict(%s)\n", strings.Join(config, ", "))
+	}
+	classString += bodyString
+	if !strings.Contains(classString, "\n    ") {
+		classString += "    pass\n"
+	}
+	if p.refersToPending {
+		p.rebuilds = append(p.rebuilds, id)
+	}
+	p.refersToPending = false
+	return classString
+}
+
+func (p *pyPrinter) printField(f ast.Field) string {
+	name := pyFieldName(f.Id)
+	var value string
+	var fieldArgs []string
+	if f.Default != nil {
+		switch f.Default.Kind {
+		case ast.DefaultEmptyList:
+			fieldArgs = append(fieldArgs, "default_factory=list")
+		case ast.DefaultEmptyMap:
+			fieldArgs = append(fieldArgs, "default_factory=dict")
+		default:
+			value = printPyDefault(*f.Default)
+		}
+	} else if f.Type.IsOptional() {
+		value = "None"
+	}
+	if jsonName := fieldJsonName(f); jsonName != name {
+		fieldArgs = append(fieldArgs, fmt.Sprintf("alias=%s", strconv.Quote(jsonName)))
+	}
+	if len(fieldArgs) > 0 {
+		p.useImport("pydantic", "Field")
+		if value != "" {
+			fieldArgs = append([]string{value}, fieldArgs...)
+		}
+		value = fmt.Sprintf("Field(%s)", strings.Join(fieldArgs, ", "))
+	}
+	fieldString := fmt.Sprintf("    %s: %s", name, p.printType(f.Type, false))
+	if value != "" {
+		fieldString += " = " + value
+	}
+	return printPyComments(f.Doc, f.Attributes, "    ") + fieldString + "\n"
+}
+
+func (p *pyPrinter) printNewType(n ast.NewType) string {
+	p.quotedPending = false
+	typeString := p.printType(n.Type, true)
+	newTypeString := fmt.Sprintf("%s = NewType(%s, %s)\n", n.Id, strconv.Quote(n.Id), typeString)
+	if p.quotedPending {
+		// pydantic cannot validate NewTypes which refer to themselves, so
+		// these are aliases
+		p.useImport("typing_extensions", "TypeAliasType")
+		newTypeString = fmt.Sprintf("%s = TypeAliasType(%s, %s)\n", n.Id, strconv.Quote(n.Id), typeString)
+	} else {
+		p.useImport("typing", "NewType")
+	}
+	return printPyComments(n.Doc, n.Attributes, "") + newTypeString
+}
+
+func (p *pyPrinter) printSumStr(s ast.SumStr) string {
+	p.useImport("enum", "Enum")
+	var variantsString string
+	for _, variant := range s.Variants {
+		value := variant.Id
+		if variant.JsonName != nil {
+			value = *variant.JsonName
+		}
+		variantsString += printPyComments(variant.Doc, variant.Attributes, "    ") + fmt.Sprintf("    %s = %s\n", pyIdent(variant.Id, pyKeywords), strconv.Quote(value))
+	}
+	return fmt.Sprintf("class %s(str, Enum):\n", s.Id) + printPyDocstring(s.Doc, s.Attributes) + variantsString
+}
+
+func (p *pyPrinter) printSumInt(s ast.SumInt) string {
+	p.useImport("enum", "IntEnum")
+	var variantsString string
+	for _, variant := range s.Variants {
+		variantsString += printPyComments(variant.Doc, variant.Attributes, "    ") + fmt.Sprintf("    %s = %d\n", pyIdent(variant.Id, pyKeywords), variant.Value)
+	}
+	return fmt.Sprintf("class %s(IntEnum):\n", s.Id) + printPyDocstring(s.Doc, s.Attributes) + variantsString
+}
+
+// printType prints a type. Where it is evaluated when the module is loaded,
+// rather than in an annotation, definitions which are printed after it are
+// quoted.
+func (p *pyPrinter) printType(t ast.Type, evaluated bool) string {
+	typeString := p.printValueType(t, evaluated)
+	if t.IsOptional() || t.IsNullable() {
+		p.useImport("typing", "Optional")
+		return fmt.Sprintf("Optional[%s]", typeString)
+	}
+	return typeString
+}
+
+func (p *pyPrinter) printValueType(t ast.Type, evaluated bool) string {
+	if t.List != nil {
+		return fmt.Sprintf("list[%s]", p.printType(t.List.Type, evaluated))
+	}
+	if t.Map != nil {
+		return fmt.Sprintf("dict[%s, %s]", p.printType(t.Map.Key, evaluated), p.printType(t.Map.Value, evaluated))
+	}
+	id := t.TypeIdent.Id
+	if id == "Int64" {
+		p.usesInt64 = true
+	}
+	if imp, ok := pyPrimitiveImports[id]; ok {
+		p.useImport(imp[0], imp[1])
+	}
+	typeString, ok := PY_PRIMITIVES[id]
+	if !ok {
+		typeString = id
+	}
+	if len(t.TypeIdent.TypeArgs) > 0 {
+		var args []string
+		for _, arg := range t.TypeIdent.TypeArgs {
+			args = append(args, p.printType(arg, evaluated))
+		}
+		typeString += fmt.Sprintf("[%s]", strings.Join(args, ", "))
+	}
+	if _, ok := p.pending[id]; ok {
+		if evaluated {
+			p.quotedPending = true
+			return strconv.Quote(typeString)
+		}
+		p.refersToPending = true
+	}
+	return typeString
+}
+
+// pyOrder orders definitions so the definitions each one refers to come
+// before it, apart from those in cycles, keeping the order of the source
+// otherwise.
+func pyOrder(ds []ast.Definition) []ast.Definition {
+	byId := make(map[string]ast.Definition)
+	for _, d := range ds {
+		byId[definitionId(d)] = d
+	}
+	visited := make(map[string]struct{})
+	var ordered []ast.Definition
+	var visit func(d ast.Definition)
+	visit = func(d ast.Definition) {
+		id := definitionId(d)
+		if _, ok := visited[id]; ok {
+			return
+		}
+		visited[id] = struct{}{}
+		for _, reference := range pyReferences(d) {
+			if next, ok := byId[reference]; ok {
+				visit(next)
+			}
+		}
+		ordered = append(ordered, d)
+	}
+	for _, d := range ds {
+		visit(d)
+	}
+	return ordered
+}
+
+// pyReferences are the types a definition refers to.
+func pyReferences(d ast.Definition) []string {
+	var types []ast.Type
+	switch {
+	case d.Product != nil:
+		if d.Product.Extends != nil {
+			types = append(types, ast.Type{TypeIdent: d.Product.Extends})
+		}
+		for _, f := range d.Product.Fields {
+			types = append(types, f.Type)
+		}
+	case d.Sum != nil:
+		for _, v := range d.Sum.Variants {
+			types = append(types, v.Type)
+		}
+	case d.NewType != nil:
+		types = append(types, d.NewType.Type)
+	case d.Alias != nil:
+		types = append(types, d.Alias.Type)
+	}
+	var references []string
+	var walk func(t ast.Type)
+	walk = func(t ast.Type) {
+		switch {
+		case t.List != nil:
+			walk(t.List.Type)
+		case t.Map != nil:
+			walk(t.Map.Key)
+			walk(t.Map.Value)
+		default:
+			references = append(references, t.TypeIdent.Id)
+			for _, arg := range t.TypeIdent.TypeArgs {
+				walk(arg)
+			}
+		}
+	}
+	for _, t := range types {
+		walk(t)
+	}
+	return references
+}
+
+// printPyDocstring prints doc lines as the docstring of a class, with a
+// Deprecated: paragraph for @deprecated.
+func printPyDocstring(doc []string, attributes ast.Attributes) string {
+	lines := pyDocLines(doc, attributes)
+	if len(lines) == 0 {
+		return ""
+	}
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(strings.ReplaceAll(line, `\`, `\\`), `"`, `\"`)
+	}
+	if len(lines) == 1 {
+		return fmt.Sprintf("    \"\"\"%s\"\"\"\n", lines[0])
+	}
+	var docString string
+	for _, line := range lines {
+		docString += strings.TrimRight("    "+line, " ") + "\n"
+	}
+	return "    \"\"\"\n" + docString + "    \"\"\"\n"
+}
+
+// printPyComments prints doc lines as comments, for the definitions and
+// fields which cannot have docstrings.
+func printPyComments(doc []string, attributes ast.Attributes, indent string) string {
+	var docString string
+	for _, line := range pyDocLines(doc, attributes) {
+		docString += strings.TrimRight(indent+"# "+line, " ") + "\n"
+	}
+	return docString
+}
+
+func pyDocLines(doc []string, attributes ast.Attributes) []string {
+	lines := append([]string{}, doc...)
+	if deprecated, ok := attributes.Get("deprecated"); ok {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		if reason, ok := deprecated.Arg("", 0); ok {
+			lines = append(lines, "Deprecated: "+reason)
+		} else {
+			lines = append(lines, "Deprecated.")
+		}
+	}
+	return lines
+}
+
+// pyFieldName converts a field name to snake case, escaping reserved names.
+func pyFieldName(id string) string {
+	return pyIdent(snakeCase(id), pyReservedFieldNames)
+}
+
+// pyKeyName is the field name of a JSON key which is not a field, such as the
+// tag of a sum, or fallback if the key is not an identifier.
+func pyKeyName(key string, fallback string) string {
+	if !pyIdentifierRegex.MatchString(key) {
+		return fallback
+	}
+	return pyIdent(key, pyReservedFieldNames)
+}
+
+func pyIdent(name string, reserved map[string]struct{}) string {
+	if _, ok := pyKeywords[name]; ok {
+		return name + "_"
+	}
+	if _, ok := reserved[name]; ok {
+		return name + "_"
+	}
+	return name
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
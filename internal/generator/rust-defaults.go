@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// rustDefaultFnName is the name of the function serde calls for the default
+// of a field of the product.
+func rustDefaultFnName(prod ast.Product, f ast.Field) string {
+	return fmt.Sprintf("default_%s_%s", snakeCase(prod.Id), snakeCase(f.Id))
+}
+
+// printDefault prints the default of a field, wrapped in the newtypes the
+// type of the field is made of.
+func (p *rustPrinter) printDefault(f ast.Field) string {
+	newTypes, base := resolveNewTypes(p.definitions, f.Type)
+	d := *f.Default
+	var value string
+	switch d.Kind {
+	case ast.DefaultString:
+		value = printRustString(d.Value) + ".to_string()"
+	case ast.DefaultNumber:
+		value = d.Value
+		isFloat := base.TypeIdent != nil && (base.TypeIdent.Id == "Float" || base.TypeIdent.Id == "Float64")
+		if isFloat && !strings.Contains(value, ".") {
+			value += ".0"
+		}
+	case ast.DefaultBool:
+		value = d.Value
+	case ast.DefaultSumStr, ast.DefaultSumInt:
+		value = fmt.Sprintf("%s::%s", d.Enum, rustIdent(d.Variant))
+	case ast.DefaultEmptyList:
+		value = "Vec::new()"
+	case ast.DefaultEmptyMap:
+		value = "HashMap::new()"
+	default:
+		panic("Invalid default")
+	}
+	for i := len(newTypes) - 1; i >= 0; i-- {
+		value = fmt.Sprintf("%s(%s)", newTypes[i], value)
+	}
+	return value
+}
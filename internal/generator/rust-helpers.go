@@ -0,0 +1,41 @@
+package generator
+
+// rustHelpers is the code of the helpers generated alongside the definitions
+// which use them.
+var rustHelpers = map[string]string{
+	"Int64": `
+/// An i64 which is a string in JSON, as JavaScript numbers cannot hold every
+/// i64.
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Hash, PartialOrd, Ord, Default)]
+pub struct Int64(pub i64);
+
+impl Serialize for Int64 {
+fn serialize<S: Serializer>(&self, serializer: S) -> Result<S::Ok, S::Error> { serializer.collect_str(&self.0) }
+}
+
+impl<'de> Deserialize<'de> for Int64 {
+fn deserialize<D: Deserializer<'de>>(deserializer: D) -> Result<Self, D::Error> {
+let s = String::deserialize(deserializer)?;
+s.parse().map(Int64).map_err(serde::de::Error::custom)
+}
+}
+`,
+	"deserialize_nullable": `
+/// Deserializes a field which can be absent, null or have a value, which
+/// serde would otherwise read as absent when null.
+fn deserialize_nullable<'de, T: Deserialize<'de>, D: Deserializer<'de>>(deserializer: D) -> Result<Option<Option<T>>, D::Error> {
+Option::<T>::deserialize(deserializer).map(Some)
+}
+`,
+}
+
+func (p *rustPrinter) printHelpers() string {
+	var helpersString string
+	if p.usesInt64 {
+		helpersString += rustHelpers["Int64"]
+	}
+	if p.usesNullable {
+		helpersString += rustHelpers["deserialize_nullable"]
+	}
+	return helpersString
+}
@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printSum prints a sum as an enum with a variant per variant of the sum,
+// tagged by serde the way the encoding of the sum is.
+func (p *rustPrinter) printSum(s ast.Sum) string {
+	var variantsString string
+	for _, variant := range s.Variants {
+		rename := fmt.Sprintf("#[serde(rename = %s)]\n", printRustString(fieldJsonName(variant)))
+		variantsString += printRustDoc(variant.Doc, variant.Attributes) + rename + fmt.Sprintf("%s(%s),\n", rustVariantName(variant.Id), p.printType(variant.Type, s.Id))
+	}
+
+	var encodingString string
+	switch s.Encoding {
+	case ast.SumEncodingInternal:
+		encodingString = fmt.Sprintf("#[serde(tag = %s)]\n", printRustString(s.Tag))
+	case ast.SumEncodingAdjacent:
+		encodingString = fmt.Sprintf("#[serde(tag = %s, content = %s)]\n", printRustString(s.Tag), printRustString(s.Content))
+	case ast.SumEncodingUntagged:
+		encodingString = "#[serde(untagged)]\n"
+	}
+
+	derives := "#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]\n"
+	return printRustDoc(s.Doc, s.Attributes) + derives + encodingString + fmt.Sprintf("pub enum %s%s {\n%s}\n", s.Id, printRustTypeParams(s.TypeParams), variantsString)
+}
+
+// printSumInt prints an enum with the values of the variants as its
+// discriminants, serialized as those values.
+func (p *rustPrinter) printSumInt(s ast.SumInt) string {
+	p.usesSerdeImpls = true
+	var variantsString string
+	var casesString string
+	for _, variant := range s.Variants {
+		name := rustIdent(variant.Id)
+		variantsString += printRustDoc(variant.Doc, variant.Attributes) + fmt.Sprintf("%s = %d,\n", name, variant.Value)
+		casesString += fmt.Sprintf("%d => Ok(%s::%s),\n", variant.Value, s.Id, name)
+	}
+	casesString += fmt.Sprintf("value => Err(serde::de::Error::custom(format!(%s, value))),\n", printRustString("unknown "+s.Id+" value {}"))
+
+	derives := "#[derive(Debug, Clone, Copy, PartialEq, Eq, Hash)]\n"
+	enumString := printRustDoc(s.Doc, s.Attributes) + derives + fmt.Sprintf("#[repr(i64)]\npub enum %s {\n%s}\n", s.Id, variantsString)
+	serializeString := fmt.Sprintf(`impl Serialize for %s {
+fn serialize<S: Serializer>(&self, serializer: S) -> Result<S::Ok, S::Error> { serializer.serialize_i64(*self as i64) }
+}
+`, s.Id)
+	deserializeString := fmt.Sprintf(`impl<'de> Deserialize<'de> for %s {
+fn deserialize<D: Deserializer<'de>>(deserializer: D) -> Result<Self, D::Error> {
+match i64::deserialize(deserializer)? {
+%s}
+}
+}
+`, s.Id, casesString)
+	return enumString + serializeString + deserializeString
+}
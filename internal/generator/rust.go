@@ -0,0 +1,399 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// RUST_PRIMITIVES maps primitives to Rust types. Int64 is a helper type, as it
+// is a string in JSON, and the other types which are strings in JSON are
+// Strings, so no crates beyond serde and serde_json are needed.
+var RUST_PRIMITIVES map[string]string = map[string]string{
+	"Float":    "f32",
+	"Float64":  "f64",
+	"Str":      "String",
+	"Bool":     "bool",
+	"Int":      "i64",
+	"Int32":    "i32",
+	"Int64":    "Int64",
+	"Any":      "serde_json::Value",
+	"Object":   "serde_json::Map<String, serde_json::Value>",
+	"Decimal":  "String",
+	"UUID":     "String",
+	"Bytes":    "String",
+	"Date":     "String",
+	"DateTime": "String",
+	"Duration": "String",
+}
+
+// rustHashablePrimitives are the primitives whose Rust types implement Eq and
+// Hash, so newtypes of them can be the keys of maps.
+var rustHashablePrimitives = map[string]struct{}{
+	"Str": {}, "Bool": {}, "Int": {}, "Int32": {}, "Int64": {}, "Decimal": {},
+	"UUID": {}, "Bytes": {}, "Date": {}, "DateTime": {}, "Duration": {},
+}
+
+// rustKeywords are escaped as raw identifiers when used as field or variant
+// names, apart from rustNonRawKeywords, which cannot be raw identifiers and
+// are suffixed with an underscore instead.
+var rustKeywords = map[string]struct{}{
+	"as": {}, "break": {}, "const": {}, "continue": {}, "else": {}, "enum": {},
+	"extern": {}, "false": {}, "fn": {}, "for": {}, "if": {}, "impl": {},
+	"in": {}, "let": {}, "loop": {}, "match": {}, "mod": {}, "move": {},
+	"mut": {}, "pub": {}, "ref": {}, "return": {}, "static": {}, "struct": {},
+	"trait": {}, "true": {}, "type": {}, "unsafe": {}, "use": {}, "where": {},
+	"while": {}, "async": {}, "await": {}, "dyn": {}, "abstract": {},
+	"become": {}, "box": {}, "do": {}, "final": {}, "macro": {}, "override": {},
+	"priv": {}, "typeof": {}, "unsized": {}, "virtual": {}, "yield": {},
+	"try": {}, "gen": {},
+}
+
+var rustNonRawKeywords = map[string]struct{}{
+	"self": {}, "Self": {}, "super": {}, "crate": {},
+}
+
+type RustGeneratorOptions struct {
+	// Definitions of every file being generated, to resolve the aliases and
+	// newtypes of types from other files
+	Definitions []ast.Definition
+}
+
+// rustPrinter prints definitions, keeping track of what the printed code uses
+// to add the imports and helpers it needs.
+type rustPrinter struct {
+	definitions map[string]ast.Definition
+	// the types each definition contains outside of a Vec or HashMap
+	references map[string][]string
+
+	usesMap      bool
+	usesNullable bool
+	usesInt64    bool
+	// whether serde traits are implemented by hand
+	usesSerdeImpls bool
+}
+
+func newRustPrinter(options RustGeneratorOptions) *rustPrinter {
+	p := &rustPrinter{
+		definitions: definitionsById(options.Definitions),
+		references:  make(map[string][]string),
+	}
+	for id, d := range p.definitions {
+		p.references[id] = definitionReferences(d)
+	}
+	return p
+}
+
+func PrintRustDefinitions(ds []ast.Definition, options RustGeneratorOptions) string {
+	p := newRustPrinter(options)
+	var definitionsString string
+	for _, d := range ds {
+		definitionsString += p.printDefinition(d)
+	}
+	return p.printUses() + definitionsString + p.printHelpers()
+}
+
+// PrintRustFile prints the definitions of a single source file as a module,
+// using the types it imports from the sibling modules of the other source
+// files.
+func PrintRustFile(f ast.File, options RustGeneratorOptions) string {
+	var importsString string
+	for _, imp := range f.Imports {
+		importsString += fmt.Sprintf("use %s::{%s};\n", rustModulePath(f.Path, imp.Path), strings.Join(imp.Ids, ", "))
+	}
+	p := newRustPrinter(options)
+	var definitionsString string
+	for _, d := range f.Definitions {
+		definitionsString += p.printDefinition(d)
+	}
+	return p.printUses() + importsString + definitionsString + p.printHelpers()
+}
+
+// rustModulePath returns the path from the module generated for the source
+// file at from, to the one generated for to, assuming the modules mirror the
+// layout of the source files.
+func rustModulePath(from string, to string) string {
+	rel, err := filepath.Rel(filepath.Dir(from), to)
+	if err != nil {
+		rel = to
+	}
+	rel = filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+	segments := []string{"super"}
+	for _, segment := range strings.Split(rel, "/") {
+		if segment == ".." {
+			segment = "super"
+		}
+		segments = append(segments, segment)
+	}
+	return strings.Join(segments, "::")
+}
+
+func (p *rustPrinter) printUses() string {
+	serdeUses := []string{"Deserialize", "Serialize"}
+	if p.usesSerdeImpls || p.usesInt64 || p.usesNullable {
+		serdeUses = append(serdeUses, "Deserializer", "Serializer")
+		sort.Strings(serdeUses)
+	}
+	usesString := fmt.Sprintf("use serde::{%s};\n", strings.Join(serdeUses, ", "))
+	if p.usesMap {
+		usesString += "use std::collections::HashMap;\n"
+	}
+	return usesString
+}
+
+func (p *rustPrinter) printDefinition(d ast.Definition) string {
+	if d.SumStr != nil {
+		return p.printSumStr(*d.SumStr)
+	}
+	if d.SumInt != nil {
+		return p.printSumInt(*d.SumInt)
+	}
+	if d.Alias != nil {
+		return printRustDoc(d.Alias.Doc, d.Alias.Attributes) + fmt.Sprintf("pub type %s = %s;\n", d.Alias.Id, p.printType(d.Alias.Type, ""))
+	}
+	if d.NewType != nil {
+		return p.printNewType(*d.NewType)
+	}
+	if d.Extern != nil {
+		return printRustDoc(d.Extern.Doc, d.Extern.Attributes) + fmt.Sprintf("pub type %s = %s;\n", d.Extern.Id, d.Extern.Targets["rs"])
+	}
+	if d.Sum != nil {
+		return p.printSum(*d.Sum)
+	}
+	if d.Product != nil {
+		return p.printProduct(*d.Product)
+	}
+	panic("Invalid definition")
+}
+
+func (p *rustPrinter) printProduct(prod ast.Product) string {
+	var fieldsString string
+	var defaultsString string
+	// Inherited fields are copied in, as flattening does not work with every
+	// sum encoding
+	for _, f := range prod.AllFields() {
+		fieldsString += p.printField(prod, f)
+		if f.Default != nil {
+			defaultsString += fmt.Sprintf("fn %s() -> %s { %s }\n", rustDefaultFnName(prod, f), p.printType(f.Type, ""), p.printDefault(f))
+		}
+	}
+	derives := "#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]\n"
+	return printRustDoc(prod.Doc, prod.Attributes) + derives + fmt.Sprintf("pub struct %s%s {\n%s}\n", prod.Id, printRustTypeParams(prod.TypeParams), fieldsString) + defaultsString
+}
+
+func (p *rustPrinter) printField(prod ast.Product, f ast.Field) string {
+	name := rustFieldName(f.Id)
+	var serdeArgs []string
+	if jsonName := fieldJsonName(f); jsonName != strings.TrimPrefix(name, "r#") {
+		serdeArgs = append(serdeArgs, fmt.Sprintf("rename = %s", printRustString(jsonName)))
+	}
+	if f.Type.IsOptional() && f.Type.IsNullable() {
+		// serde only reads absent Options as None without deserialize_with,
+		// and a bare default would require the type parameters to be Default
+		p.usesNullable = true
+		serdeArgs = append(serdeArgs, `default = "Option::default"`, `skip_serializing_if = "Option::is_none"`, `deserialize_with = "deserialize_nullable"`)
+	} else if f.Type.IsOptional() {
+		serdeArgs = append(serdeArgs, `skip_serializing_if = "Option::is_none"`)
+	}
+	if f.Default != nil {
+		serdeArgs = append(serdeArgs, fmt.Sprintf("default = %s", printRustString(rustDefaultFnName(prod, f))))
+	}
+	var serdeString string
+	if len(serdeArgs) > 0 {
+		serdeString = fmt.Sprintf("#[serde(%s)]\n", strings.Join(serdeArgs, ", "))
+	}
+	return printRustDoc(f.Doc, f.Attributes) + serdeString + fmt.Sprintf("pub %s: %s,\n", name, p.printType(f.Type, prod.Id))
+}
+
+func (p *rustPrinter) printNewType(n ast.NewType) string {
+	derives := "Debug, Clone, PartialEq, Serialize, Deserialize"
+	if p.isHashable(n.Type, make(map[string]struct{})) {
+		derives = "Debug, Clone, PartialEq, Eq, Hash, Serialize, Deserialize"
+	}
+	return printRustDoc(n.Doc, n.Attributes) + fmt.Sprintf("#[derive(%s)]\n#[serde(transparent)]\npub struct %s(pub %s);\n", derives, n.Id, p.printType(n.Type, n.Id))
+}
+
+func (p *rustPrinter) printSumStr(s ast.SumStr) string {
+	var variantsString string
+	for _, variant := range s.Variants {
+		name := rustIdent(variant.Id)
+		var renameString string
+		if variant.JsonName != nil || name != variant.Id {
+			value := variant.Id
+			if variant.JsonName != nil {
+				value = *variant.JsonName
+			}
+			renameString = fmt.Sprintf("#[serde(rename = %s)]\n", printRustString(value))
+		}
+		variantsString += printRustDoc(variant.Doc, variant.Attributes) + renameString + name + ",\n"
+	}
+	derives := "#[derive(Debug, Clone, Copy, PartialEq, Eq, Hash, Serialize, Deserialize)]\n"
+	return printRustDoc(s.Doc, s.Attributes) + derives + fmt.Sprintf("pub enum %s {\n%s}\n", s.Id, variantsString)
+}
+
+func printRustTypeParams(params []string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("<%s>", strings.Join(params, ", "))
+}
+
+// printType prints a type, boxing the types which contain the definition
+// owner, which would otherwise be infinitely large. Types in a Vec or HashMap
+// are already behind a pointer.
+func (p *rustPrinter) printType(t ast.Type, owner string) string {
+	typeString := p.printValueType(t, owner)
+	if t.IsOptional() && t.IsNullable() {
+		// None when absent and Some(None) when null
+		return fmt.Sprintf("Option<Option<%s>>", typeString)
+	}
+	if t.IsOptional() || t.IsNullable() {
+		return fmt.Sprintf("Option<%s>", typeString)
+	}
+	return typeString
+}
+
+func (p *rustPrinter) printValueType(t ast.Type, owner string) string {
+	if t.List != nil {
+		return fmt.Sprintf("Vec<%s>", p.printType(t.List.Type, ""))
+	}
+	if t.Map != nil {
+		p.usesMap = true
+		return fmt.Sprintf("HashMap<%s, %s>", p.printType(t.Map.Key, ""), p.printType(t.Map.Value, ""))
+	}
+	if t.TypeIdent.Id == "Int64" {
+		p.usesInt64 = true
+	}
+	typeString, ok := RUST_PRIMITIVES[t.TypeIdent.Id]
+	if !ok {
+		typeString = t.TypeIdent.Id
+	}
+	if len(t.TypeIdent.TypeArgs) > 0 {
+		var args []string
+		for _, arg := range t.TypeIdent.TypeArgs {
+			args = append(args, p.printType(arg, owner))
+		}
+		typeString += fmt.Sprintf("<%s>", strings.Join(args, ", "))
+	}
+	if owner != "" && reaches(p.references, t.TypeIdent.Id, owner) {
+		typeString = fmt.Sprintf("Box<%s>", typeString)
+	}
+	return typeString
+}
+
+// isHashable reports whether the Rust type of t implements Eq and Hash.
+func (p *rustPrinter) isHashable(t ast.Type, seen map[string]struct{}) bool {
+	if t.TypeIdent == nil || t.IsOptional() || t.IsNullable() || len(t.TypeIdent.TypeArgs) > 0 {
+		return false
+	}
+	id := t.TypeIdent.Id
+	if _, ok := rustHashablePrimitives[id]; ok {
+		return true
+	}
+	if _, ok := seen[id]; ok {
+		return false
+	}
+	seen[id] = struct{}{}
+	d, ok := p.definitions[id]
+	if !ok {
+		return false
+	}
+	switch {
+	case d.SumStr != nil, d.SumInt != nil:
+		return true
+	case d.Alias != nil:
+		return p.isHashable(d.Alias.Type, seen)
+	case d.NewType != nil:
+		return p.isHashable(d.NewType.Type, seen)
+	}
+	return false
+}
+
+// printRustDoc prints doc lines as doc comments, with a #[deprecated] attribute
+// for @deprecated.
+func printRustDoc(doc []string, attributes ast.Attributes) string {
+	var docString string
+	for _, line := range doc {
+		docString += strings.TrimRight("/// "+line, " ") + "\n"
+	}
+	if deprecated, ok := attributes.Get("deprecated"); ok {
+		if reason, ok := deprecated.Arg("", 0); ok {
+			docString += fmt.Sprintf("#[deprecated(note = %s)]\n", printRustString(reason))
+		} else {
+			docString += "#[deprecated]\n"
+		}
+	}
+	return docString
+}
+
+// printRustString prints s as a Rust string literal.
+func printRustString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if unicode.IsControl(r) {
+				fmt.Fprintf(&b, `\u{%x}`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// rustFieldName converts a field name to snake case, escaping keywords.
+func rustFieldName(id string) string {
+	return rustIdent(snakeCase(id))
+}
+
+// rustVariantName converts the name of a sum variant to pascal case, escaping
+// keywords.
+func rustVariantName(id string) string {
+	return rustIdent(capitalizeHead(id))
+}
+
+func rustIdent(name string) string {
+	if _, ok := rustNonRawKeywords[name]; ok {
+		return name + "_"
+	}
+	if _, ok := rustKeywords[name]; ok {
+		return "r#" + name
+	}
+	return name
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Runs of capitals, as in userID, are a single word
+			previousUpper := i > 0 && unicode.IsUpper(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if i > 0 && (!previousUpper || nextLower) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,16 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+func TestRustGolden(t *testing.T) {
+	for _, entry := range []string{"testdata/golden/api.bt", "testdata/golden/patch.bt"} {
+		files, _ := translateFile(t, entry, "rs")
+		for _, f := range files {
+			assertGolden(t, f.Path, "rs", PrintRustFile(f, RustGeneratorOptions{Definitions: ast.Definitions(files)}))
+		}
+	}
+}
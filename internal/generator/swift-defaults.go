@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printDefault prints the default of a field, wrapped in the newtypes the type
+// of the field is made of.
+func (p *swiftPrinter) printDefault(f ast.Field) string {
+	newTypes, _ := resolveNewTypes(p.definitions, f.Type)
+	d := *f.Default
+	var value string
+	switch d.Kind {
+	case ast.DefaultString:
+		value = printSwiftString(d.Value)
+	case ast.DefaultNumber, ast.DefaultBool:
+		value = d.Value
+	case ast.DefaultSumStr, ast.DefaultSumInt:
+		value = fmt.Sprintf("%s.%s", d.Enum, swiftIdent(lowerCaseHead(d.Variant)))
+	case ast.DefaultEmptyList:
+		value = "[]"
+	case ast.DefaultEmptyMap:
+		value = "[:]"
+	default:
+		panic("Invalid default")
+	}
+	for i := len(newTypes) - 1; i >= 0; i-- {
+		value = fmt.Sprintf("%s(%s)", newTypes[i], value)
+	}
+	return value
+}
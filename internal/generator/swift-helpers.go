@@ -0,0 +1,119 @@
+package generator
+
+// swiftHelpers is the code of the helpers generated alongside the definitions
+// which use them.
+var swiftHelpers = map[string]string{
+	"Int64String": `/// An Int64 which is a string in JSON, as JavaScript numbers cannot hold every
+/// Int64.
+struct Int64String: Codable, Hashable {
+    var value: Int64
+
+    init(_ value: Int64) {
+        self.value = value
+    }
+
+    init(from decoder: Decoder) throws {
+        let container = try decoder.singleValueContainer()
+        let string = try container.decode(String.self)
+        guard let value = Int64(string) else {
+            throw DecodingError.dataCorruptedError(in: container, debugDescription: "Invalid Int64 \(string)")
+        }
+        self.value = value
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.singleValueContainer()
+        try container.encode(String(value))
+    }
+}
+`,
+	"JSONValue": `/// Any JSON value.
+enum JSONValue: Codable, Hashable {
+    case null
+    case bool(Bool)
+    case number(Double)
+    case string(String)
+    case array([JSONValue])
+    case object([String: JSONValue])
+
+    init(from decoder: Decoder) throws {
+        let container = try decoder.singleValueContainer()
+        if container.decodeNil() {
+            self = .null
+        } else if let value = try? container.decode(Bool.self) {
+            self = .bool(value)
+        } else if let value = try? container.decode(Double.self) {
+            self = .number(value)
+        } else if let value = try? container.decode(String.self) {
+            self = .string(value)
+        } else if let value = try? container.decode([JSONValue].self) {
+            self = .array(value)
+        } else {
+            self = .object(try container.decode([String: JSONValue].self))
+        }
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.singleValueContainer()
+        switch self {
+        case .null:
+            try container.encodeNil()
+        case .bool(let value):
+            try container.encode(value)
+        case .number(let value):
+            try container.encode(value)
+        case .string(let value):
+            try container.encode(value)
+        case .array(let value):
+            try container.encode(value)
+        case .object(let value):
+            try container.encode(value)
+        }
+    }
+}
+`,
+	"Box": `/// A reference to a value, which lets a struct contain itself.
+final class Box<Value: Codable>: Codable {
+    var value: Value
+
+    init(_ value: Value) {
+        self.value = value
+    }
+
+    init(from decoder: Decoder) throws {
+        self.value = try decoder.singleValueContainer().decode(Value.self)
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.singleValueContainer()
+        try container.encode(value)
+    }
+}
+`,
+}
+
+func (p *swiftPrinter) helpers() []string {
+	var helpers []string
+	if p.usesInt64 {
+		helpers = append(helpers, "Int64String")
+	}
+	if p.usesJSONValue {
+		helpers = append(helpers, "JSONValue")
+	}
+	if p.usesBox {
+		helpers = append(helpers, "Box")
+	}
+	return helpers
+}
+
+// printHelpers prints the helpers the definitions use, which are not declared
+// by another file of the module already.
+func (p *swiftPrinter) printHelpers(declaredHelpers map[string]struct{}) []string {
+	var helpersStrings []string
+	for _, helper := range p.helpers() {
+		if _, ok := declaredHelpers[helper]; !ok {
+			helpersStrings = append(helpersStrings, swiftHelpers[helper])
+		}
+	}
+	return helpersStrings
+}
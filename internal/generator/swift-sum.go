@@ -0,0 +1,136 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printSum prints a sum as an enum with a case per variant holding its value,
+// which is indirect when the sum contains itself. Its Codable conformance is
+// written out for the encoding of the sum.
+func (p *swiftPrinter) printSum(s ast.Sum) string {
+	var casesString string
+	var keysString string
+	var decodeCasesString string
+	var encodeCasesString string
+	var untaggedAttemptsString string
+	for i, variant := range s.Variants {
+		name := swiftIdent(lowerCaseHead(variant.Id))
+		valueType := p.printValueType(variant.Type, "")
+		casesString += printSwiftDoc(variant.Doc, variant.Attributes, "    ") + fmt.Sprintf("    case %s(%s)\n", name, valueType)
+
+		jsonName := fieldJsonName(variant)
+		keysString += "        case " + name
+		if jsonName != strings.Trim(name, "`") {
+			keysString += " = " + printSwiftString(jsonName)
+		}
+		keysString += "\n"
+
+		var decoded string
+		var encodeString string
+		switch s.Encoding {
+		case ast.SumEncodingExternal:
+			decodeCasesString += fmt.Sprintf("        case .%s:\n            self = .%s(try container.decode(%s.self, forKey: .%s))\n", name, name, valueType, name)
+			encodeString = fmt.Sprintf("            try container.encode(value, forKey: .%s)\n", name)
+		case ast.SumEncodingInternal:
+			// The variant is decoded from the same object as the tag
+			decoded = fmt.Sprintf("%s(from: decoder)", valueType)
+			encodeString = fmt.Sprintf("            try container.encode(%s, forKey: .tag)\n            try value.encode(to: encoder)\n", printSwiftString(jsonName))
+		case ast.SumEncodingAdjacent:
+			decoded = fmt.Sprintf("container.decode(%s.self, forKey: .content)", valueType)
+			encodeString = fmt.Sprintf("            try container.encode(%s, forKey: .tag)\n            try container.encode(value, forKey: .content)\n", printSwiftString(jsonName))
+		case ast.SumEncodingUntagged:
+			encodeString = "            try container.encode(value)\n"
+			if i > 0 {
+				untaggedAttemptsString += " else "
+			} else {
+				untaggedAttemptsString += "        "
+			}
+			untaggedAttemptsString += fmt.Sprintf("if let value = try? container.decode(%s.self) {\n            self = .%s(value)\n        }", valueType, name)
+		}
+		if decoded != "" {
+			decodeCasesString += fmt.Sprintf("        case %s:\n            self = .%s(try %s)\n", printSwiftString(jsonName), name, decoded)
+		}
+		encodeCasesString += fmt.Sprintf("        case .%s(let value):\n%s", name, encodeString)
+	}
+
+	unknownString := fmt.Sprintf("        default:\n            throw DecodingError.dataCorruptedError(forKey: .tag, in: container, debugDescription: \"Unknown %s variant \\(tag)\")\n", s.Id)
+	var keysDeclarationString string
+	var decodeString string
+	var encodeContainer string
+	switch s.Encoding {
+	case ast.SumEncodingExternal:
+		// The keys of the object are counted with AnyKey, as a container keyed
+		// by CodingKeys leaves out the keys which are not variants
+		keysDeclarationString = fmt.Sprintf(`    private enum CodingKeys: String, CodingKey {
+%s    }
+
+    private struct AnyKey: CodingKey {
+        var stringValue: String
+        var intValue: Int? { nil }
+
+        init(stringValue: String) {
+            self.stringValue = stringValue
+        }
+
+        init?(intValue: Int) {
+            return nil
+        }
+    }
+`, keysString)
+		decodeString = fmt.Sprintf(`        let keys = try decoder.container(keyedBy: AnyKey.self).allKeys
+        guard keys.count == 1 else {
+            throw DecodingError.dataCorrupted(DecodingError.Context(codingPath: decoder.codingPath, debugDescription: "%s must have a single key"))
+        }
+        guard let key = CodingKeys(stringValue: keys[0].stringValue) else {
+            throw DecodingError.dataCorrupted(DecodingError.Context(codingPath: decoder.codingPath, debugDescription: "Unknown %s variant \(keys[0].stringValue)"))
+        }
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        switch key {
+%s        }
+`, s.Id, s.Id, decodeCasesString)
+		encodeContainer = "encoder.container(keyedBy: CodingKeys.self)"
+	case ast.SumEncodingInternal, ast.SumEncodingAdjacent:
+		keysDeclarationString = fmt.Sprintf("    private enum TagKeys: String, CodingKey {\n        case tag = %s\n", printSwiftString(s.Tag))
+		if s.Encoding == ast.SumEncodingAdjacent {
+			keysDeclarationString += fmt.Sprintf("        case content = %s\n", printSwiftString(s.Content))
+		}
+		keysDeclarationString += "    }\n"
+		decodeString = fmt.Sprintf(`        let container = try decoder.container(keyedBy: TagKeys.self)
+        let tag = try container.decode(String.self, forKey: .tag)
+        switch tag {
+%s%s        }
+`, decodeCasesString, unknownString)
+		encodeContainer = "encoder.container(keyedBy: TagKeys.self)"
+	case ast.SumEncodingUntagged:
+		// The first variant which can be decoded is taken
+		decodeString = fmt.Sprintf(`        let container = try decoder.singleValueContainer()
+%s else {
+            throw DecodingError.dataCorruptedError(in: container, debugDescription: "%s does not match any of its variants")
+        }
+`, untaggedAttemptsString, s.Id)
+		encodeContainer = "encoder.singleValueContainer()"
+	}
+	if keysDeclarationString != "" {
+		keysDeclarationString = "\n" + keysDeclarationString
+	}
+
+	indirectString := ""
+	if _, ok := p.indirect[s.Id]; ok {
+		indirectString = "indirect "
+	}
+	return printSwiftDoc(s.Doc, s.Attributes, "") + fmt.Sprintf(`%senum %s%s: Codable {
+%s%s
+    init(from decoder: Decoder) throws {
+%s    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = %s
+        switch self {
+%s        }
+    }
+}
+`, indirectString, s.Id, printSwiftTypeParams(s.TypeParams), casesString, keysDeclarationString, decodeString, encodeContainer, encodeCasesString)
+}
@@ -0,0 +1,510 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// SWIFT_PRIMITIVES maps primitives to Swift types. Int64String and JSONValue
+// are helper types, generated alongside the definitions which use them.
+var SWIFT_PRIMITIVES map[string]string = map[string]string{
+	"Float":    "Float",
+	"Float64":  "Double",
+	"Str":      "String",
+	"Bool":     "Bool",
+	"Int":      "Int",
+	"Int32":    "Int32",
+	"Int64":    "Int64String",
+	"Any":      "JSONValue",
+	"Object":   "[String: JSONValue]",
+	"Decimal":  "String",
+	"UUID":     "String",
+	"Bytes":    "String",
+	"Date":     "String",
+	"DateTime": "String",
+	"Duration": "String",
+}
+
+// swiftKeywords are written in backticks when used as names, except for
+// swiftUnescapableNames which get a trailing underscore.
+var swiftKeywords = map[string]struct{}{
+	"associatedtype": {}, "class": {}, "deinit": {}, "enum": {}, "extension": {},
+	"fileprivate": {}, "func": {}, "import": {}, "init": {}, "inout": {},
+	"internal": {}, "let": {}, "open": {}, "operator": {}, "private": {},
+	"precedencegroup": {}, "protocol": {}, "public": {}, "rethrows": {},
+	"static": {}, "struct": {}, "subscript": {}, "typealias": {}, "var": {},
+	"break": {}, "case": {}, "catch": {}, "continue": {}, "default": {},
+	"defer": {}, "do": {}, "else": {}, "fallthrough": {}, "for": {},
+	"guard": {}, "if": {}, "in": {}, "repeat": {}, "return": {}, "throw": {},
+	"switch": {}, "where": {}, "while": {}, "Any": {}, "as": {}, "await": {},
+	"false": {}, "is": {}, "nil": {}, "throws": {}, "true": {}, "try": {},
+}
+
+var swiftUnescapableNames = map[string]struct{}{
+	"self": {}, "Self": {}, "super": {},
+}
+
+type SwiftGeneratorOptions struct {
+	// Definitions of every file being generated, to resolve the aliases and
+	// newtypes of types from other files
+	Definitions []ast.Definition
+	// Helper types that are already declared in the module, by another file
+	DeclaredHelpers map[string]struct{}
+}
+
+// swiftPrinter prints definitions, keeping track of the helpers the printed
+// code uses.
+type swiftPrinter struct {
+	definitions map[string]ast.Definition
+	// the types each definition contains outside of an array or dictionary,
+	// which are none for indirect enums
+	references map[string][]string
+	// the sums which contain themselves, and are indirect enums
+	indirect map[string]struct{}
+	// the types which are the keys of dictionaries, or are what those are made
+	// of
+	mapKeys map[string]struct{}
+
+	usesInt64     bool
+	usesJSONValue bool
+	usesBox       bool
+}
+
+func newSwiftPrinter(options SwiftGeneratorOptions) *swiftPrinter {
+	p := &swiftPrinter{
+		definitions: definitionsById(options.Definitions),
+		references:  make(map[string][]string),
+		indirect:    make(map[string]struct{}),
+		mapKeys:     make(map[string]struct{}),
+	}
+	for id, d := range p.definitions {
+		p.references[id] = definitionReferences(d)
+	}
+	for id, d := range p.definitions {
+		if d.Sum == nil {
+			continue
+		}
+		for _, reference := range p.references[id] {
+			if reaches(p.references, reference, id) {
+				p.indirect[id] = struct{}{}
+			}
+		}
+	}
+	// The cases of indirect enums are already behind a pointer
+	for id := range p.indirect {
+		p.references[id] = nil
+	}
+	for _, d := range p.definitions {
+		for _, t := range definitionTypes(d) {
+			p.addMapKeys(t)
+		}
+	}
+	return p
+}
+
+// definitionTypes are the types a definition is made of.
+func definitionTypes(d ast.Definition) []ast.Type {
+	var types []ast.Type
+	switch {
+	case d.Product != nil:
+		for _, f := range d.Product.Fields {
+			types = append(types, f.Type)
+		}
+	case d.Sum != nil:
+		for _, v := range d.Sum.Variants {
+			types = append(types, v.Type)
+		}
+	case d.NewType != nil:
+		types = append(types, d.NewType.Type)
+	case d.Alias != nil:
+		types = append(types, d.Alias.Type)
+	}
+	return types
+}
+
+func (p *swiftPrinter) addMapKeys(t ast.Type) {
+	if t.List != nil {
+		p.addMapKeys(t.List.Type)
+		return
+	}
+	if t.Map != nil {
+		p.addMapKeys(t.Map.Value)
+		for key := t.Map.Key; key.TypeIdent != nil; {
+			id := key.TypeIdent.Id
+			if _, ok := p.mapKeys[id]; ok {
+				break
+			}
+			p.mapKeys[id] = struct{}{}
+			d, ok := p.definitions[id]
+			if !ok {
+				break
+			}
+			if d.Alias != nil {
+				key = d.Alias.Type
+			} else if d.NewType != nil {
+				key = d.NewType.Type
+			} else {
+				break
+			}
+		}
+		return
+	}
+	for _, arg := range t.TypeIdent.TypeArgs {
+		p.addMapKeys(arg)
+	}
+}
+
+// PrintSwiftDefinitions prints the definitions as Codable types. The files of
+// a Swift module see each other's types, so they do not import anything.
+func PrintSwiftDefinitions(ds []ast.Definition, options SwiftGeneratorOptions) string {
+	p := newSwiftPrinter(options)
+	var definitionStrings []string
+	for _, d := range ds {
+		definitionStrings = append(definitionStrings, p.printDefinition(d))
+	}
+	definitionStrings = append(definitionStrings, p.printHelpers(options.DeclaredHelpers)...)
+	return "import Foundation\n\n" + strings.Join(definitionStrings, "\n")
+}
+
+// SwiftHelpers returns the helper types the definitions need.
+func SwiftHelpers(ds []ast.Definition, options SwiftGeneratorOptions) []string {
+	p := newSwiftPrinter(options)
+	for _, d := range ds {
+		p.printDefinition(d)
+	}
+	return p.helpers()
+}
+
+func (p *swiftPrinter) printDefinition(d ast.Definition) string {
+	if d.SumStr != nil {
+		return p.printSumStr(*d.SumStr)
+	}
+	if d.SumInt != nil {
+		return p.printSumInt(*d.SumInt)
+	}
+	if d.Alias != nil {
+		return printSwiftDoc(d.Alias.Doc, d.Alias.Attributes, "") + fmt.Sprintf("typealias %s = %s\n", d.Alias.Id, p.printType(d.Alias.Type, ""))
+	}
+	if d.NewType != nil {
+		return p.printNewType(*d.NewType)
+	}
+	if d.Extern != nil {
+		return printSwiftDoc(d.Extern.Doc, d.Extern.Attributes, "") + fmt.Sprintf("typealias %s = %s\n", d.Extern.Id, d.Extern.Targets["swift"])
+	}
+	if d.Sum != nil {
+		return p.printSum(*d.Sum)
+	}
+	if d.Product != nil {
+		return p.printProduct(*d.Product)
+	}
+	panic("Invalid definition")
+}
+
+// printProduct prints a struct with synthesized Codable conformance. Decoding
+// is written out when fields have defaults, which it would otherwise require,
+// and encoding when fields are nullable, which it would otherwise leave out
+// when nil. Fields which are optional and nullable are double optionals, nil
+// being absent and .some(nil) null, which both are written out for.
+func (p *swiftPrinter) printProduct(prod ast.Product) string {
+	fields := prod.AllFields()
+	var propertiesString string
+	var keysString string
+	hasRenames := false
+	hasDefaults := false
+	hasNullables := false
+	hasOptionalNullables := false
+	for _, f := range fields {
+		name := swiftIdent(f.Id)
+		typeString := p.printType(f.Type, prod.Id)
+		if f.Type.IsOptional() && f.Type.IsNullable() {
+			hasOptionalNullables = true
+			typeString += "?"
+		}
+		propertyString := fmt.Sprintf("    var %s: %s", name, typeString)
+		if f.Default != nil {
+			hasDefaults = true
+			propertyString += " = " + p.printDefault(f)
+		}
+		propertiesString += printSwiftDoc(f.Doc, f.Attributes, "    ") + propertyString + "\n"
+
+		keysString += "        case " + name
+		if jsonName := fieldJsonName(f); jsonName != strings.Trim(name, "`") {
+			hasRenames = true
+			keysString += " = " + printSwiftString(jsonName)
+		}
+		keysString += "\n"
+		if f.Type.IsNullable() && !f.Type.IsOptional() {
+			hasNullables = true
+		}
+	}
+
+	var bodyString string
+	if len(fields) > 0 {
+		bodyString = propertiesString
+	}
+	// The keys are only synthesized when either method is
+	if hasRenames || hasDefaults || hasNullables || hasOptionalNullables {
+		bodyString += fmt.Sprintf("\n    enum CodingKeys: String, CodingKey {\n%s    }\n", keysString)
+	}
+	structString := printSwiftDoc(prod.Doc, prod.Attributes, "") + fmt.Sprintf("struct %s%s: Codable {\n%s}\n", prod.Id, printSwiftTypeParams(prod.TypeParams), bodyString)
+
+	// Written in an extension to keep the memberwise initializer
+	var methodStrings []string
+	if hasDefaults || hasOptionalNullables {
+		var decodeString string
+		for _, f := range fields {
+			name := swiftIdent(f.Id)
+			valueType := p.printValueType(f.Type, prod.Id)
+			switch {
+			case f.Type.IsOptional() && f.Type.IsNullable():
+				// decodeIfPresent is nil for null too
+				decodeString += fmt.Sprintf("        self.%s = try container.contains(.%s) ? .some(container.decode(%s?.self, forKey: .%s)) : nil\n", name, name, valueType, name)
+			case f.Default != nil:
+				decodeString += fmt.Sprintf("        self.%s = try container.decodeIfPresent(%s.self, forKey: .%s) ?? %s\n", name, valueType, name, p.printDefault(f))
+			case f.Type.IsOptional() || f.Type.IsNullable():
+				decodeString += fmt.Sprintf("        self.%s = try container.decodeIfPresent(%s.self, forKey: .%s)\n", name, valueType, name)
+			default:
+				decodeString += fmt.Sprintf("        self.%s = try container.decode(%s.self, forKey: .%s)\n", name, valueType, name)
+			}
+		}
+		methodStrings = append(methodStrings, fmt.Sprintf("    init(from decoder: Decoder) throws {\n        let container = try decoder.container(keyedBy: CodingKeys.self)\n%s    }\n", decodeString))
+	}
+	if hasNullables || hasOptionalNullables {
+		var encodeString string
+		for _, f := range fields {
+			name := swiftIdent(f.Id)
+			if f.Type.IsOptional() && f.Type.IsNullable() {
+				encodeString += fmt.Sprintf("        if let value = self.%s {\n            try container.encode(value, forKey: .%s)\n        }\n", name, name)
+				continue
+			}
+			method := "encode"
+			if f.Type.IsOptional() {
+				method = "encodeIfPresent"
+			}
+			encodeString += fmt.Sprintf("        try container.%s(self.%s, forKey: .%s)\n", method, name, name)
+		}
+		methodStrings = append(methodStrings, fmt.Sprintf("    func encode(to encoder: Encoder) throws {\n        var container = encoder.container(keyedBy: CodingKeys.self)\n%s    }\n", encodeString))
+	}
+	if len(methodStrings) > 0 {
+		structString += fmt.Sprintf("\nextension %s {\n%s}\n", prod.Id, strings.Join(methodStrings, "\n"))
+	}
+	return structString
+}
+
+// printNewType prints a struct holding the value, which is encoded as the
+// value.
+func (p *swiftPrinter) printNewType(n ast.NewType) string {
+	valueType := p.printType(n.Type, n.Id)
+	conformances := "Codable"
+	if p.isHashable(n.Type, make(map[string]struct{})) {
+		conformances += ", Hashable"
+	}
+	newTypeString := printSwiftDoc(n.Doc, n.Attributes, "") + fmt.Sprintf(`struct %s: %s {
+    var value: %s
+
+    init(_ value: %s) {
+        self.value = value
+    }
+
+    init(from decoder: Decoder) throws {
+        self.value = try decoder.singleValueContainer().decode(%s.self)
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.singleValueContainer()
+        try container.encode(value)
+    }
+}
+`, n.Id, conformances, valueType, valueType, valueType)
+	if _, ok := p.mapKeys[n.Id]; ok {
+		// Dictionaries are only encoded as objects when their keys are
+		// CodingKeyRepresentable
+		newTypeString += fmt.Sprintf(`
+extension %s: CodingKeyRepresentable {
+    var codingKey: CodingKey {
+        value.codingKey
+    }
+
+    init?<T: CodingKey>(codingKey: T) {
+        guard let value = %s(codingKey: codingKey) else {
+            return nil
+        }
+        self.init(value)
+    }
+}
+`, n.Id, valueType)
+	}
+	return newTypeString
+}
+
+func (p *swiftPrinter) printSumStr(s ast.SumStr) string {
+	conformances := "String, Codable"
+	if _, ok := p.mapKeys[s.Id]; ok {
+		conformances += ", CodingKeyRepresentable"
+	}
+	var casesString string
+	for _, variant := range s.Variants {
+		name := swiftIdent(lowerCaseHead(variant.Id))
+		value := variant.Id
+		if variant.JsonName != nil {
+			value = *variant.JsonName
+		}
+		caseString := "    case " + name
+		if value != strings.Trim(name, "`") {
+			caseString += " = " + printSwiftString(value)
+		}
+		casesString += printSwiftDoc(variant.Doc, variant.Attributes, "    ") + caseString + "\n"
+	}
+	return printSwiftDoc(s.Doc, s.Attributes, "") + fmt.Sprintf("enum %s: %s {\n%s}\n", s.Id, conformances, casesString)
+}
+
+func (p *swiftPrinter) printSumInt(s ast.SumInt) string {
+	var casesString string
+	for _, variant := range s.Variants {
+		casesString += printSwiftDoc(variant.Doc, variant.Attributes, "    ") + fmt.Sprintf("    case %s = %d\n", swiftIdent(lowerCaseHead(variant.Id)), variant.Value)
+	}
+	return printSwiftDoc(s.Doc, s.Attributes, "") + fmt.Sprintf("enum %s: Int, Codable {\n%s}\n", s.Id, casesString)
+}
+
+func printSwiftTypeParams(params []string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	var constrained []string
+	for _, param := range params {
+		constrained = append(constrained, param+": Codable")
+	}
+	return fmt.Sprintf("<%s>", strings.Join(constrained, ", "))
+}
+
+// printType prints a type, boxing the types which contain the definition
+// owner, which a struct cannot do. Types in an array or dictionary are already
+// behind a pointer.
+func (p *swiftPrinter) printType(t ast.Type, owner string) string {
+	typeString := p.printValueType(t, owner)
+	if t.IsOptional() || t.IsNullable() {
+		return typeString + "?"
+	}
+	return typeString
+}
+
+func (p *swiftPrinter) printValueType(t ast.Type, owner string) string {
+	if t.List != nil {
+		return fmt.Sprintf("[%s]", p.printType(t.List.Type, ""))
+	}
+	if t.Map != nil {
+		return fmt.Sprintf("[%s: %s]", p.printType(t.Map.Key, ""), p.printType(t.Map.Value, ""))
+	}
+	switch t.TypeIdent.Id {
+	case "Int64":
+		p.usesInt64 = true
+	case "Any", "Object":
+		p.usesJSONValue = true
+	}
+	typeString, ok := SWIFT_PRIMITIVES[t.TypeIdent.Id]
+	if !ok {
+		typeString = t.TypeIdent.Id
+	}
+	if len(t.TypeIdent.TypeArgs) > 0 {
+		var args []string
+		for _, arg := range t.TypeIdent.TypeArgs {
+			args = append(args, p.printType(arg, owner))
+		}
+		typeString += fmt.Sprintf("<%s>", strings.Join(args, ", "))
+	}
+	if owner != "" && reaches(p.references, t.TypeIdent.Id, owner) {
+		p.usesBox = true
+		typeString = fmt.Sprintf("Box<%s>", typeString)
+	}
+	return typeString
+}
+
+// isHashable reports whether the Swift type of t is Hashable.
+func (p *swiftPrinter) isHashable(t ast.Type, seen map[string]struct{}) bool {
+	if t.List != nil {
+		return p.isHashable(t.List.Type, seen)
+	}
+	if t.Map != nil {
+		return p.isHashable(t.Map.Value, seen)
+	}
+	id := t.TypeIdent.Id
+	if _, ok := SWIFT_PRIMITIVES[id]; ok {
+		return true
+	}
+	if _, ok := seen[id]; ok || len(t.TypeIdent.TypeArgs) > 0 {
+		return false
+	}
+	seen[id] = struct{}{}
+	d, ok := p.definitions[id]
+	if !ok {
+		return false
+	}
+	switch {
+	case d.SumStr != nil, d.SumInt != nil:
+		return true
+	case d.Alias != nil:
+		return p.isHashable(d.Alias.Type, seen)
+	case d.NewType != nil:
+		return p.isHashable(d.NewType.Type, seen)
+	}
+	return false
+}
+
+// printSwiftDoc prints doc lines as doc comments, with an @available attribute
+// for @deprecated.
+func printSwiftDoc(doc []string, attributes ast.Attributes, indent string) string {
+	var docString string
+	for _, line := range doc {
+		docString += strings.TrimRight(indent+"/// "+line, " ") + "\n"
+	}
+	if deprecated, ok := attributes.Get("deprecated"); ok {
+		if reason, ok := deprecated.Arg("", 0); ok {
+			docString += fmt.Sprintf("%s@available(*, deprecated, message: %s)\n", indent, printSwiftString(reason))
+		} else {
+			docString += indent + "@available(*, deprecated)\n"
+		}
+	}
+	return docString
+}
+
+// printSwiftString prints s as a Swift string literal.
+func printSwiftString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case 0:
+			b.WriteString(`\0`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u{%x}`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func swiftIdent(name string) string {
+	if _, ok := swiftUnescapableNames[name]; ok {
+		return name + "_"
+	}
+	if _, ok := swiftKeywords[name]; ok {
+		return "`" + name + "`"
+	}
+	return name
+}
@@ -0,0 +1,24 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+func TestSwiftGolden(t *testing.T) {
+	for _, entry := range []string{"testdata/golden/api.bt", "testdata/golden/patch.bt"} {
+		files, _ := translateFile(t, entry, "swift")
+		declaredHelpers := make(map[string]struct{})
+		for _, f := range files {
+			options := SwiftGeneratorOptions{
+				Definitions:     ast.Definitions(files),
+				DeclaredHelpers: declaredHelpers,
+			}
+			assertGolden(t, f.Path, "swift", PrintSwiftDefinitions(f.Definitions, options))
+			for _, helper := range SwiftHelpers(f.Definitions, options) {
+				declaredHelpers[helper] = struct{}{}
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func roundTrip[T any](input string) {
+	var v T
+	if err := json.Unmarshal([]byte(input), &v); err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Println(string(b))
+}
+
+func main() {
+	roundTrip[Base](`{"say \"hi\"":"a"}`)
+	roundTrip[User](`{"say \"hi\"":"a","C:\\":"b","":1,"a,b":null,"plain":"c","other":2}`)
+	roundTrip[User](`{"say \"hi\"":"a","C:\\":"b","a,b":"d","plain":"c"}`)
+	roundTrip[User](`{"say \"hi\"":"a","C:\\":"b","plain":"c"}`)
+	roundTrip[Internal](`{"k,v":"user","C:\\":"b","plain":"c","say \"hi\"":"a"}`)
+	roundTrip[Adjacent](`{"t\"":"count","c\\":2}`)
+	roundTrip[Adjacent](`{"t\"":"user","c\\":{"plain":"c"}}`)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+func roundTrip[T any](input string) {
+	var v T
+	if err := json.Unmarshal([]byte(input), &v); err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Println(string(b))
+}
+
+func marshal(v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Println(string(b))
+}
+
+func main() {
+	roundTrip[External](`{"customer":{"name":"a"}}`)
+	roundTrip[External](`{"admin":{"level":1},"customer":{"name":"a"}}`)
+	roundTrip[External](`{}`)
+	roundTrip[External](`{"other":1}`)
+	roundTrip[Internal](`{"kind":"customer","name":"a"}`)
+	roundTrip[Internal](`{"kind":"other"}`)
+	roundTrip[Adjacent](`{"kind":"count","data":2}`)
+	roundTrip[Adjacent](`{"kind":"other","data":2}`)
+	roundTrip[Untagged](`{"level":3}`)
+	roundTrip[Untagged](`"a"`)
+	roundTrip[Untagged](`{"level":3,"other":1}`)
+	roundTrip[Result[int]](`{"ok":4}`)
+
+	_, err := External{}.MarshalJSON()
+	fmt.Println("error:", err)
+	_, err = External{Admin: &Admin{}, Customer: &Customer{}}.MarshalJSON()
+	fmt.Println("error:", err)
+	marshal(NewInternalAdmin(Admin{Level: 2}))
+	marshal(NewAdjacentAdmin(Admin{Level: 2}))
+	marshal(NewUntaggedName("b"))
+	fmt.Println(NewExternalCustomer(Customer{}).Which() == ExternalVariant_Customer, External{}.Which() == "")
+}
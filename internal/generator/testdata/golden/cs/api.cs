@@ -0,0 +1,337 @@
+#nullable enable
+
+using System.Collections.Generic;
+using System.Linq;
+using System.Text.Json.Serialization;
+using System.Text.Json;
+using System;
+
+namespace Example.Api;
+
+/// <summary>
+/// A user of the API
+/// </summary>
+public sealed record User
+{
+    [JsonPropertyName("id")]
+    public required UserId Id { get; init; }
+
+    [JsonPropertyName("name")]
+    public required string Name { get; init; }
+
+    [JsonPropertyName("nickname")]
+    [JsonIgnore(Condition = JsonIgnoreCondition.WhenWritingNull)]
+    public string? Nickname { get; init; }
+
+    [JsonPropertyName("bio")]
+    public required string? Bio { get; init; }
+
+    [JsonPropertyName("balance")]
+    [JsonNumberHandling(JsonNumberHandling.WriteAsString | JsonNumberHandling.AllowReadingFromString)]
+    public required long Balance { get; init; }
+
+    [JsonPropertyName("ids")]
+    [JsonNumberHandling(JsonNumberHandling.WriteAsString | JsonNumberHandling.AllowReadingFromString)]
+    public required List<long> Ids { get; init; }
+
+    [JsonPropertyName("status")]
+    public required Status Status { get; init; }
+
+    [JsonPropertyName("address")]
+    [JsonIgnore(Condition = JsonIgnoreCondition.WhenWritingNull)]
+    public Address? Address { get; init; }
+}
+
+public sealed record Settings
+{
+    [JsonPropertyName("retries")]
+    public long Retries { get; init; } = 3;
+
+    [JsonPropertyName("status")]
+    public Status Status { get; init; } = Status.Active;
+
+    [JsonPropertyName("priority")]
+    public Priority Priority { get; init; } = Priority.High;
+
+    [JsonPropertyName("tags")]
+    public List<string> Tags { get; init; } = new();
+
+    [JsonPropertyName("labels")]
+    public Dictionary<string, string> Labels { get; init; } = new();
+}
+
+public sealed record Page<T>
+{
+    [JsonPropertyName("items")]
+    public required List<T> Items { get; init; }
+
+    [JsonPropertyName("next")]
+    [JsonIgnore(Condition = JsonIgnoreCondition.WhenWritingNull)]
+    public string? Next { get; init; }
+}
+
+[JsonConverter(typeof(ExternalConverter))]
+public abstract record External;
+
+public sealed record ExternalUser(User Value) : External;
+
+public sealed record ExternalCount(long Value) : External;
+
+public sealed class ExternalConverter : JsonConverter<External>
+{
+    public override External Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)
+    {
+        using var document = JsonDocument.ParseValue(ref reader);
+        var element = document.RootElement;
+        if (element.ValueKind != JsonValueKind.Object || element.EnumerateObject().Count() != 1)
+        {
+            throw new JsonException("External must be an object with a single key");
+        }
+        var property = element.EnumerateObject().First();
+        return property.Name switch
+        {
+            "user" => new ExternalUser(property.Value.Deserialize<User>(options)!),
+            "count" => new ExternalCount(property.Value.Deserialize<long>(options)!),
+            var key => throw new JsonException($"Unknown External variant {key}"),
+        };
+    }
+
+    public override void Write(Utf8JsonWriter writer, External value, JsonSerializerOptions options)
+    {
+        switch (value)
+        {
+            case ExternalUser variant:
+                writer.WriteStartObject();
+                writer.WritePropertyName("user");
+                JsonSerializer.Serialize(writer, variant.Value, options);
+                writer.WriteEndObject();
+                break;
+            case ExternalCount variant:
+                writer.WriteStartObject();
+                writer.WritePropertyName("count");
+                JsonSerializer.Serialize(writer, variant.Value, options);
+                writer.WriteEndObject();
+                break;
+            default:
+                throw new JsonException($"Unknown External variant {value.GetType()}");
+        }
+    }
+}
+
+[JsonConverter(typeof(InternalConverter))]
+public abstract record Internal;
+
+public sealed record InternalUser(User Value) : Internal;
+
+public sealed record InternalSettings(Settings Value) : Internal;
+
+public sealed class InternalConverter : JsonConverter<Internal>
+{
+    public override Internal Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)
+    {
+        using var document = JsonDocument.ParseValue(ref reader);
+        var element = document.RootElement;
+        if (element.ValueKind != JsonValueKind.Object || !element.TryGetProperty("kind", out var tag) || tag.ValueKind != JsonValueKind.String)
+        {
+            throw new JsonException("Internal must be an object with kind");
+        }
+        return tag.GetString() switch
+        {
+            "user" => new InternalUser(element.Deserialize<User>(options)!),
+            "settings" => new InternalSettings(element.Deserialize<Settings>(options)!),
+            var key => throw new JsonException($"Unknown Internal variant {key}"),
+        };
+    }
+
+    public override void Write(Utf8JsonWriter writer, Internal value, JsonSerializerOptions options)
+    {
+        switch (value)
+        {
+            case InternalUser variant:
+                writer.WriteStartObject();
+                writer.WriteString("kind", "user");
+                foreach (var property in JsonSerializer.SerializeToElement(variant.Value, options).EnumerateObject())
+                {
+                    property.WriteTo(writer);
+                }
+                writer.WriteEndObject();
+                break;
+            case InternalSettings variant:
+                writer.WriteStartObject();
+                writer.WriteString("kind", "settings");
+                foreach (var property in JsonSerializer.SerializeToElement(variant.Value, options).EnumerateObject())
+                {
+                    property.WriteTo(writer);
+                }
+                writer.WriteEndObject();
+                break;
+            default:
+                throw new JsonException($"Unknown Internal variant {value.GetType()}");
+        }
+    }
+}
+
+[JsonConverter(typeof(AdjacentConverter))]
+public abstract record Adjacent;
+
+public sealed record AdjacentUser(User Value) : Adjacent;
+
+public sealed record AdjacentCount(long Value) : Adjacent;
+
+public sealed class AdjacentConverter : JsonConverter<Adjacent>
+{
+    public override Adjacent Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)
+    {
+        using var document = JsonDocument.ParseValue(ref reader);
+        var element = document.RootElement;
+        if (element.ValueKind != JsonValueKind.Object || !element.TryGetProperty("kind", out var tag) || tag.ValueKind != JsonValueKind.String || !element.TryGetProperty("data", out var content))
+        {
+            throw new JsonException("Adjacent must be an object with kind and data");
+        }
+        return tag.GetString() switch
+        {
+            "user" => new AdjacentUser(content.Deserialize<User>(options)!),
+            "count" => new AdjacentCount(content.Deserialize<long>(options)!),
+            var key => throw new JsonException($"Unknown Adjacent variant {key}"),
+        };
+    }
+
+    public override void Write(Utf8JsonWriter writer, Adjacent value, JsonSerializerOptions options)
+    {
+        switch (value)
+        {
+            case AdjacentUser variant:
+                writer.WriteStartObject();
+                writer.WriteString("kind", "user");
+                writer.WritePropertyName("data");
+                JsonSerializer.Serialize(writer, variant.Value, options);
+                writer.WriteEndObject();
+                break;
+            case AdjacentCount variant:
+                writer.WriteStartObject();
+                writer.WriteString("kind", "count");
+                writer.WritePropertyName("data");
+                JsonSerializer.Serialize(writer, variant.Value, options);
+                writer.WriteEndObject();
+                break;
+            default:
+                throw new JsonException($"Unknown Adjacent variant {value.GetType()}");
+        }
+    }
+}
+
+[JsonConverter(typeof(UntaggedConverter))]
+public abstract record Untagged;
+
+public sealed record UntaggedUser(User Value) : Untagged;
+
+public sealed record UntaggedName(string Value) : Untagged;
+
+public sealed class UntaggedConverter : JsonConverter<Untagged>
+{
+    public override Untagged Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)
+    {
+        using var document = JsonDocument.ParseValue(ref reader);
+        var element = document.RootElement;
+        try
+        {
+            return new UntaggedUser(element.Deserialize<User>(options)!);
+        }
+        catch (JsonException)
+        {
+            // The next variant is tried
+        }
+
+        try
+        {
+            return new UntaggedName(element.Deserialize<string>(options)!);
+        }
+        catch (JsonException)
+        {
+            // The next variant is tried
+        }
+
+        throw new JsonException("Untagged does not match any of its variants");
+    }
+
+    public override void Write(Utf8JsonWriter writer, Untagged value, JsonSerializerOptions options)
+    {
+        switch (value)
+        {
+            case UntaggedUser variant:
+                JsonSerializer.Serialize(writer, variant.Value, options);
+                break;
+            case UntaggedName variant:
+                JsonSerializer.Serialize(writer, variant.Value, options);
+                break;
+            default:
+                throw new JsonException($"Unknown Untagged variant {value.GetType()}");
+        }
+    }
+}
+
+[JsonConverter(typeof(OutcomeConverterFactory))]
+public abstract record Outcome<T>;
+
+public sealed record OutcomeOk<T>(T Value) : Outcome<T>;
+
+public sealed record OutcomeErr<T>(string Value) : Outcome<T>;
+
+public sealed class OutcomeConverter<T> : JsonConverter<Outcome<T>>
+{
+    public override Outcome<T> Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options)
+    {
+        using var document = JsonDocument.ParseValue(ref reader);
+        var element = document.RootElement;
+        if (element.ValueKind != JsonValueKind.Object || element.EnumerateObject().Count() != 1)
+        {
+            throw new JsonException("Outcome must be an object with a single key");
+        }
+        var property = element.EnumerateObject().First();
+        return property.Name switch
+        {
+            "ok" => new OutcomeOk<T>(property.Value.Deserialize<T>(options)!),
+            "err" => new OutcomeErr<T>(property.Value.Deserialize<string>(options)!),
+            var key => throw new JsonException($"Unknown Outcome variant {key}"),
+        };
+    }
+
+    public override void Write(Utf8JsonWriter writer, Outcome<T> value, JsonSerializerOptions options)
+    {
+        switch (value)
+        {
+            case OutcomeOk<T> variant:
+                writer.WriteStartObject();
+                writer.WritePropertyName("ok");
+                JsonSerializer.Serialize(writer, variant.Value, options);
+                writer.WriteEndObject();
+                break;
+            case OutcomeErr<T> variant:
+                writer.WriteStartObject();
+                writer.WritePropertyName("err");
+                JsonSerializer.Serialize(writer, variant.Value, options);
+                writer.WriteEndObject();
+                break;
+            default:
+                throw new JsonException($"Unknown Outcome variant {value.GetType()}");
+        }
+    }
+}
+
+public sealed class OutcomeConverterFactory : JsonConverterFactory
+{
+    public override bool CanConvert(Type typeToConvert) =>
+        typeToConvert.IsGenericType && typeToConvert.GetGenericTypeDefinition() == typeof(Outcome<>);
+
+    public override JsonConverter CreateConverter(Type typeToConvert, JsonSerializerOptions options) =>
+        (JsonConverter)Activator.CreateInstance(typeof(OutcomeConverter<>).MakeGenericType(typeToConvert.GetGenericArguments()))!;
+}
+
+public sealed record Response
+{
+    [JsonPropertyName("users")]
+    public required Page<User> Users { get; init; }
+
+    [JsonPropertyName("outcome")]
+    public required Outcome<long> Outcome { get; init; }
+}
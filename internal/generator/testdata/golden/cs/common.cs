@@ -0,0 +1,49 @@
+#nullable enable
+
+using System.Text.Json.Serialization;
+using System.Text.Json;
+using System;
+
+namespace Example.Api;
+
+[JsonConverter(typeof(JsonStringEnumConverter<Status>))]
+public enum Status
+{
+    Active,
+    [JsonStringEnumMemberName("done")]
+    Done,
+}
+
+public enum Priority : long
+{
+    Low = 1,
+    High = 10,
+}
+
+public sealed record Address
+{
+    [JsonPropertyName("street")]
+    public required string Street { get; init; }
+
+    [JsonPropertyName("city")]
+    public required string City { get; init; }
+}
+
+[JsonConverter(typeof(UserId.Converter))]
+public readonly record struct UserId(string Value)
+{
+    public sealed class Converter : JsonConverter<UserId>
+    {
+        public override UserId Read(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options) =>
+            new(JsonSerializer.Deserialize<string>(ref reader, options)!);
+
+        public override void Write(Utf8JsonWriter writer, UserId value, JsonSerializerOptions options) =>
+            JsonSerializer.Serialize(writer, value.Value, options);
+
+        public override UserId ReadAsPropertyName(ref Utf8JsonReader reader, Type typeToConvert, JsonSerializerOptions options) =>
+            new(reader.GetString()!);
+
+        public override void WriteAsPropertyName(Utf8JsonWriter writer, UserId value, JsonSerializerOptions options) =>
+            writer.WritePropertyName(value.Value);
+    }
+}
@@ -0,0 +1,460 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// A user of the API
+type User struct {
+	Id       UserId   `json:"id"`
+	Name     string   `json:"name"`
+	Nickname *string  `json:"nickname,omitempty"`
+	Bio      *string  `json:"bio"`
+	Balance  Int64    `json:"balance"`
+	Ids      []Int64  `json:"ids"`
+	Status   Status   `json:"status"`
+	Address  *Address `json:"address,omitempty"`
+}
+type Settings struct {
+	Retries  int               `json:"retries"`
+	Status   Status            `json:"status"`
+	Priority Priority          `json:"priority"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+}
+
+// NewSettings returns a Settings with the defaults of its fields set.
+func NewSettings() Settings {
+	var x Settings
+	x.Retries = 3
+	x.Status = Status_Active
+	x.Priority = Priority_High
+	x.Tags = []string{}
+	x.Labels = map[string]string{}
+	return x
+}
+
+type Page[T any] struct {
+	Items []T     `json:"items"`
+	Next  *string `json:"next,omitempty"`
+}
+type External struct {
+	User  *User `json:"user,omitempty"`
+	Count *int  `json:"count,omitempty"`
+}
+
+// ExternalVariant names a variant of External.
+type ExternalVariant string
+
+const ExternalVariant_User ExternalVariant = "user"
+const ExternalVariant_Count ExternalVariant = "count"
+
+// Which returns the variant which is set, or an empty string when none is.
+func (x External) Which() ExternalVariant {
+	switch {
+	case x.User != nil:
+		return ExternalVariant_User
+	case x.Count != nil:
+		return ExternalVariant_Count
+	}
+	return ""
+}
+
+// NewExternalUser wraps v as the User variant of External.
+func NewExternalUser(v User) External { return External{User: &v} }
+
+// NewExternalCount wraps v as the Count variant of External.
+func NewExternalCount(v int) External { return External{Count: &v} }
+
+// MarshalJSON encodes the variant which is set, failing unless exactly one is.
+func (x External) MarshalJSON() ([]byte, error) {
+	set := 0
+	if x.User != nil {
+		set++
+	}
+	if x.Count != nil {
+		set++
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("External must have exactly one variant set, got %d", set)
+	}
+	var tag string
+	var value any
+	switch {
+	case x.User != nil:
+		tag, value = "user", x.User
+	case x.Count != nil:
+		tag, value = "count", x.Count
+	}
+	return json.Marshal(map[string]any{tag: value})
+}
+
+// UnmarshalJSON decodes the variant named by the tag.
+func (x *External) UnmarshalJSON(b []byte) error {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(b, &object); err != nil {
+		return err
+	}
+	if len(object) != 1 {
+		return fmt.Errorf("External must have exactly one variant set, got %d", len(object))
+	}
+	var tagged struct {
+		Tag     string
+		Content json.RawMessage
+	}
+	for tag, content := range object {
+		tagged.Tag, tagged.Content = tag, content
+	}
+	*x = External{}
+	switch tagged.Tag {
+	case "user":
+		x.User = new(User)
+		return json.Unmarshal(tagged.Content, x.User)
+	case "count":
+		x.Count = new(int)
+		return json.Unmarshal(tagged.Content, x.Count)
+	default:
+		return fmt.Errorf("unknown External variant %q", tagged.Tag)
+	}
+}
+
+type Internal struct {
+	User     *User     `json:"user,omitempty"`
+	Settings *Settings `json:"settings,omitempty"`
+}
+
+// InternalVariant names a variant of Internal.
+type InternalVariant string
+
+const InternalVariant_User InternalVariant = "user"
+const InternalVariant_Settings InternalVariant = "settings"
+
+// Which returns the variant which is set, or an empty string when none is.
+func (x Internal) Which() InternalVariant {
+	switch {
+	case x.User != nil:
+		return InternalVariant_User
+	case x.Settings != nil:
+		return InternalVariant_Settings
+	}
+	return ""
+}
+
+// NewInternalUser wraps v as the User variant of Internal.
+func NewInternalUser(v User) Internal { return Internal{User: &v} }
+
+// NewInternalSettings wraps v as the Settings variant of Internal.
+func NewInternalSettings(v Settings) Internal { return Internal{Settings: &v} }
+
+// MarshalJSON encodes the variant which is set, failing unless exactly one is.
+func (x Internal) MarshalJSON() ([]byte, error) {
+	set := 0
+	if x.User != nil {
+		set++
+	}
+	if x.Settings != nil {
+		set++
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("Internal must have exactly one variant set, got %d", set)
+	}
+	var tag string
+	var value any
+	switch {
+	case x.User != nil:
+		tag, value = "user", x.User
+	case x.Settings != nil:
+		tag, value = "settings", x.Settings
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	tagged, err := json.Marshal(map[string]string{"kind": tag})
+	if err != nil {
+		return nil, err
+	}
+	if string(b) == "{}" {
+		return tagged, nil
+	}
+	return append(append(tagged[:len(tagged)-1], ','), b[1:]...), nil
+}
+
+// UnmarshalJSON decodes the variant named by the tag.
+func (x *Internal) UnmarshalJSON(b []byte) error {
+	var tagged struct {
+		Tag string `json:"kind"`
+	}
+	if err := json.Unmarshal(b, &tagged); err != nil {
+		return err
+	}
+	*x = Internal{}
+	switch tagged.Tag {
+	case "user":
+		x.User = new(User)
+		return json.Unmarshal(b, x.User)
+	case "settings":
+		x.Settings = new(Settings)
+		return json.Unmarshal(b, x.Settings)
+	default:
+		return fmt.Errorf("unknown Internal variant %q", tagged.Tag)
+	}
+}
+
+type Adjacent struct {
+	User  *User `json:"user,omitempty"`
+	Count *int  `json:"count,omitempty"`
+}
+
+// AdjacentVariant names a variant of Adjacent.
+type AdjacentVariant string
+
+const AdjacentVariant_User AdjacentVariant = "user"
+const AdjacentVariant_Count AdjacentVariant = "count"
+
+// Which returns the variant which is set, or an empty string when none is.
+func (x Adjacent) Which() AdjacentVariant {
+	switch {
+	case x.User != nil:
+		return AdjacentVariant_User
+	case x.Count != nil:
+		return AdjacentVariant_Count
+	}
+	return ""
+}
+
+// NewAdjacentUser wraps v as the User variant of Adjacent.
+func NewAdjacentUser(v User) Adjacent { return Adjacent{User: &v} }
+
+// NewAdjacentCount wraps v as the Count variant of Adjacent.
+func NewAdjacentCount(v int) Adjacent { return Adjacent{Count: &v} }
+
+// MarshalJSON encodes the variant which is set, failing unless exactly one is.
+func (x Adjacent) MarshalJSON() ([]byte, error) {
+	set := 0
+	if x.User != nil {
+		set++
+	}
+	if x.Count != nil {
+		set++
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("Adjacent must have exactly one variant set, got %d", set)
+	}
+	var tag string
+	var value any
+	switch {
+	case x.User != nil:
+		tag, value = "user", x.User
+	case x.Count != nil:
+		tag, value = "count", x.Count
+	}
+	return json.Marshal(struct {
+		Tag     string `json:"kind"`
+		Content any    `json:"data"`
+	}{tag, value})
+}
+
+// UnmarshalJSON decodes the variant named by the tag.
+func (x *Adjacent) UnmarshalJSON(b []byte) error {
+	var tagged struct {
+		Tag     string          `json:"kind"`
+		Content json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(b, &tagged); err != nil {
+		return err
+	}
+	*x = Adjacent{}
+	switch tagged.Tag {
+	case "user":
+		x.User = new(User)
+		return json.Unmarshal(tagged.Content, x.User)
+	case "count":
+		x.Count = new(int)
+		return json.Unmarshal(tagged.Content, x.Count)
+	default:
+		return fmt.Errorf("unknown Adjacent variant %q", tagged.Tag)
+	}
+}
+
+type Untagged struct {
+	User *User   `json:"user,omitempty"`
+	Name *string `json:"name,omitempty"`
+}
+
+// UntaggedVariant names a variant of Untagged.
+type UntaggedVariant string
+
+const UntaggedVariant_User UntaggedVariant = "user"
+const UntaggedVariant_Name UntaggedVariant = "name"
+
+// Which returns the variant which is set, or an empty string when none is.
+func (x Untagged) Which() UntaggedVariant {
+	switch {
+	case x.User != nil:
+		return UntaggedVariant_User
+	case x.Name != nil:
+		return UntaggedVariant_Name
+	}
+	return ""
+}
+
+// NewUntaggedUser wraps v as the User variant of Untagged.
+func NewUntaggedUser(v User) Untagged { return Untagged{User: &v} }
+
+// NewUntaggedName wraps v as the Name variant of Untagged.
+func NewUntaggedName(v string) Untagged { return Untagged{Name: &v} }
+
+// MarshalJSON encodes the variant which is set, failing unless exactly one is.
+func (x Untagged) MarshalJSON() ([]byte, error) {
+	set := 0
+	if x.User != nil {
+		set++
+	}
+	if x.Name != nil {
+		set++
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("Untagged must have exactly one variant set, got %d", set)
+	}
+	var value any
+	switch {
+	case x.User != nil:
+		value = x.User
+	case x.Name != nil:
+		value = x.Name
+	}
+	return json.Marshal(value)
+}
+
+// UnmarshalJSON decodes the first variant which matches the JSON.
+func (x *Untagged) UnmarshalJSON(b []byte) error {
+	*x = Untagged{}
+	{
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.DisallowUnknownFields()
+		var value User
+		if d.Decode(&value) == nil {
+			x.User = &value
+			return nil
+		}
+	}
+	{
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.DisallowUnknownFields()
+		var value string
+		if d.Decode(&value) == nil {
+			x.Name = &value
+			return nil
+		}
+	}
+	return errors.New("Untagged does not match any of its variants")
+}
+
+type Outcome[T any] struct {
+	Ok  *T      `json:"ok,omitempty"`
+	Err *string `json:"err,omitempty"`
+}
+
+// OutcomeVariant names a variant of Outcome.
+type OutcomeVariant string
+
+const OutcomeVariant_Ok OutcomeVariant = "ok"
+const OutcomeVariant_Err OutcomeVariant = "err"
+
+// Which returns the variant which is set, or an empty string when none is.
+func (x Outcome[T]) Which() OutcomeVariant {
+	switch {
+	case x.Ok != nil:
+		return OutcomeVariant_Ok
+	case x.Err != nil:
+		return OutcomeVariant_Err
+	}
+	return ""
+}
+
+// NewOutcomeOk wraps v as the Ok variant of Outcome.
+func NewOutcomeOk[T any](v T) Outcome[T] { return Outcome[T]{Ok: &v} }
+
+// NewOutcomeErr wraps v as the Err variant of Outcome.
+func NewOutcomeErr[T any](v string) Outcome[T] { return Outcome[T]{Err: &v} }
+
+// MarshalJSON encodes the variant which is set, failing unless exactly one is.
+func (x Outcome[T]) MarshalJSON() ([]byte, error) {
+	set := 0
+	if x.Ok != nil {
+		set++
+	}
+	if x.Err != nil {
+		set++
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("Outcome must have exactly one variant set, got %d", set)
+	}
+	var tag string
+	var value any
+	switch {
+	case x.Ok != nil:
+		tag, value = "ok", x.Ok
+	case x.Err != nil:
+		tag, value = "err", x.Err
+	}
+	return json.Marshal(map[string]any{tag: value})
+}
+
+// UnmarshalJSON decodes the variant named by the tag.
+func (x *Outcome[T]) UnmarshalJSON(b []byte) error {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(b, &object); err != nil {
+		return err
+	}
+	if len(object) != 1 {
+		return fmt.Errorf("Outcome must have exactly one variant set, got %d", len(object))
+	}
+	var tagged struct {
+		Tag     string
+		Content json.RawMessage
+	}
+	for tag, content := range object {
+		tagged.Tag, tagged.Content = tag, content
+	}
+	*x = Outcome[T]{}
+	switch tagged.Tag {
+	case "ok":
+		x.Ok = new(T)
+		return json.Unmarshal(tagged.Content, x.Ok)
+	case "err":
+		x.Err = new(string)
+		return json.Unmarshal(tagged.Content, x.Err)
+	default:
+		return fmt.Errorf("unknown Outcome variant %q", tagged.Tag)
+	}
+}
+
+type Response struct {
+	Users   Page[User]   `json:"users"`
+	Outcome Outcome[int] `json:"outcome"`
+}
+
+// Int64 is an int64 which is a string in JSON, as JavaScript numbers cannot
+// hold every int64.
+type Int64 int64
+
+func (i Int64) MarshalJSON() ([]byte, error) { return json.Marshal(strconv.FormatInt(int64(i), 10)) }
+
+func (i *Int64) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*i = Int64(v)
+	return nil
+}
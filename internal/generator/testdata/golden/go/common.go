@@ -0,0 +1,21 @@
+package main
+
+import ()
+
+type Status string
+
+const Status_Active Status = "Active"
+const Status_Done Status = "done"
+
+type Priority int
+
+const (
+	Priority_Low  Priority = 1
+	Priority_High Priority = 10
+)
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+type UserId string
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+type Patch struct {
+	Name    *string          `json:"name,omitempty"`
+	Bio     Nullable[string] `json:"bio,omitempty"`
+	Count   Nullable[int]    `json:"count,omitempty"`
+	Balance Nullable[Int64]  `json:"balance,omitempty"`
+}
+
+// Int64 is an int64 which is a string in JSON, as JavaScript numbers cannot
+// hold every int64.
+type Int64 int64
+
+func (i Int64) MarshalJSON() ([]byte, error) { return json.Marshal(strconv.FormatInt(int64(i), 10)) }
+
+func (i *Int64) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*i = Int64(v)
+	return nil
+}
+
+// Nullable is a field which can be absent, null or have a value.
+type Nullable[T any] map[bool]T
+
+// NewNullable returns a Nullable with the value.
+func NewNullable[T any](v T) Nullable[T] { return Nullable[T]{true: v} }
+
+// NewNull returns a Nullable which is null.
+func NewNull[T any]() Nullable[T] { var zero T; return Nullable[T]{false: zero} }
+
+// Get returns the value, if there is one.
+func (n Nullable[T]) Get() (T, bool) { v, ok := n[true]; return v, ok }
+
+// IsNull reports whether the field is null.
+func (n Nullable[T]) IsNull() bool { _, ok := n[false]; return ok }
+
+// IsSet reports whether the field is present, either null or with a value.
+func (n Nullable[T]) IsSet() bool { return len(n) > 0 }
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	v, ok := n[true]
+	if !ok {
+		return []byte("null"), nil
+	}
+	return json.Marshal(v)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*n = NewNull[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = NewNullable(v)
+	return nil
+}
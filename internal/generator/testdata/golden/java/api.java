@@ -0,0 +1,375 @@
+package com.example.api;
+
+import com.example.api.common.*;
+import com.fasterxml.jackson.annotation.JsonFormat;
+import com.fasterxml.jackson.annotation.JsonInclude;
+import com.fasterxml.jackson.core.JsonGenerator;
+import com.fasterxml.jackson.core.JsonParser;
+import com.fasterxml.jackson.databind.BeanProperty;
+import com.fasterxml.jackson.databind.DeserializationContext;
+import com.fasterxml.jackson.databind.JavaType;
+import com.fasterxml.jackson.databind.JsonDeserializer;
+import com.fasterxml.jackson.databind.JsonMappingException;
+import com.fasterxml.jackson.databind.JsonNode;
+import com.fasterxml.jackson.databind.SerializerProvider;
+import com.fasterxml.jackson.databind.annotation.JsonDeserialize;
+import com.fasterxml.jackson.databind.annotation.JsonSerialize;
+import com.fasterxml.jackson.databind.deser.ContextualDeserializer;
+import com.fasterxml.jackson.databind.deser.std.StdDeserializer;
+import com.fasterxml.jackson.databind.node.ObjectNode;
+import com.fasterxml.jackson.databind.ser.std.StdSerializer;
+import com.fasterxml.jackson.databind.ser.std.ToStringSerializer;
+import com.fasterxml.jackson.databind.type.TypeFactory;
+import com.fasterxml.jackson.databind.util.NameTransformer;
+import java.io.IOException;
+import java.util.List;
+import java.util.Map;
+
+public final class api {
+    private api() {
+    }
+
+    /**
+     * A user of the API
+     */
+    public record User(
+            UserId id,
+            String name,
+            @JsonInclude(JsonInclude.Include.NON_NULL) String nickname,
+            String bio,
+            @JsonFormat(shape = JsonFormat.Shape.STRING) long balance,
+            @JsonSerialize(contentUsing = ToStringSerializer.class) List<Long> ids,
+            Status status,
+            @JsonInclude(JsonInclude.Include.NON_NULL) Address address) {
+    }
+
+    public record Settings(
+            Long retries,
+            Status status,
+            Priority priority,
+            List<String> tags,
+            Map<String, String> labels) {
+        public Settings {
+            if (retries == null) {
+                retries = 3L;
+            }
+            if (status == null) {
+                status = Status.Active;
+            }
+            if (priority == null) {
+                priority = Priority.High;
+            }
+            if (tags == null) {
+                tags = List.of();
+            }
+            if (labels == null) {
+                labels = Map.of();
+            }
+        }
+    }
+
+    public record Page<T>(
+            List<T> items,
+            @JsonInclude(JsonInclude.Include.NON_NULL) String next) {
+    }
+
+    @JsonSerialize(using = External.Serializer.class)
+    @JsonDeserialize(using = External.Deserializer.class)
+    public sealed interface External {
+        final class Serializer extends StdSerializer<External> {
+            public Serializer() {
+                super(External.class);
+            }
+
+            @Override
+            public void serialize(External value, JsonGenerator gen, SerializerProvider provider) throws IOException {
+                if (value instanceof ExternalUser variant) {
+                    gen.writeStartObject();
+                    gen.writeFieldName("user");
+                    provider.defaultSerializeValue(variant.value(), gen);
+                    gen.writeEndObject();
+                    return;
+                }
+                if (value instanceof ExternalCount variant) {
+                    gen.writeStartObject();
+                    gen.writeFieldName("count");
+                    provider.defaultSerializeValue(variant.value(), gen);
+                    gen.writeEndObject();
+                    return;
+                }
+            }
+        }
+
+        final class Deserializer extends StdDeserializer<External> {
+            public Deserializer() {
+                super(External.class);
+            }
+
+            @Override
+            public External deserialize(JsonParser p, DeserializationContext ctxt) throws IOException {
+                TypeFactory types = ctxt.getTypeFactory();
+                JsonNode node = ctxt.readTree(p);
+                if (!node.isObject() || node.size() != 1) {
+                    throw JsonMappingException.from(p, "External must have a single key");
+                }
+                Map.Entry<String, JsonNode> entry = node.fields().next();
+                String key = entry.getKey();
+                JsonNode variant = entry.getValue();
+                return switch (key) {
+                    case "user" -> new ExternalUser(ctxt.readTreeAsValue(variant, types.constructType(User.class)));
+                    case "count" -> new ExternalCount(ctxt.readTreeAsValue(variant, types.constructType(Long.class)));
+                    default -> throw JsonMappingException.from(p, "Unknown External variant " + key);
+                };
+            }
+        }
+    }
+
+    public record ExternalUser(User value) implements External {
+    }
+
+    public record ExternalCount(long value) implements External {
+    }
+
+    @JsonSerialize(using = Internal.Serializer.class)
+    @JsonDeserialize(using = Internal.Deserializer.class)
+    public sealed interface Internal {
+        final class Serializer extends StdSerializer<Internal> {
+            public Serializer() {
+                super(Internal.class);
+            }
+
+            @Override
+            public void serialize(Internal value, JsonGenerator gen, SerializerProvider provider) throws IOException {
+                if (value instanceof InternalUser variant) {
+                    gen.writeStartObject();
+                    gen.writeStringField("kind", "user");
+                    provider.findValueSerializer(variant.value().getClass())
+                            .unwrappingSerializer(NameTransformer.NOP)
+                            .serialize(variant.value(), gen, provider);
+                    gen.writeEndObject();
+                    return;
+                }
+                if (value instanceof InternalSettings variant) {
+                    gen.writeStartObject();
+                    gen.writeStringField("kind", "settings");
+                    provider.findValueSerializer(variant.value().getClass())
+                            .unwrappingSerializer(NameTransformer.NOP)
+                            .serialize(variant.value(), gen, provider);
+                    gen.writeEndObject();
+                    return;
+                }
+            }
+        }
+
+        final class Deserializer extends StdDeserializer<Internal> {
+            public Deserializer() {
+                super(Internal.class);
+            }
+
+            @Override
+            public Internal deserialize(JsonParser p, DeserializationContext ctxt) throws IOException {
+                TypeFactory types = ctxt.getTypeFactory();
+                JsonNode node = ctxt.readTree(p);
+                if (!(node instanceof ObjectNode variant) || !variant.path("kind").isTextual()) {
+                    throw JsonMappingException.from(p, "Internal must be an object with kind");
+                }
+                String key = variant.remove("kind").asText();
+                return switch (key) {
+                    case "user" -> new InternalUser(ctxt.readTreeAsValue(variant, types.constructType(User.class)));
+                    case "settings" -> new InternalSettings(ctxt.readTreeAsValue(variant, types.constructType(Settings.class)));
+                    default -> throw JsonMappingException.from(p, "Unknown Internal variant " + key);
+                };
+            }
+        }
+    }
+
+    public record InternalUser(User value) implements Internal {
+    }
+
+    public record InternalSettings(Settings value) implements Internal {
+    }
+
+    @JsonSerialize(using = Adjacent.Serializer.class)
+    @JsonDeserialize(using = Adjacent.Deserializer.class)
+    public sealed interface Adjacent {
+        final class Serializer extends StdSerializer<Adjacent> {
+            public Serializer() {
+                super(Adjacent.class);
+            }
+
+            @Override
+            public void serialize(Adjacent value, JsonGenerator gen, SerializerProvider provider) throws IOException {
+                if (value instanceof AdjacentUser variant) {
+                    gen.writeStartObject();
+                    gen.writeStringField("kind", "user");
+                    gen.writeFieldName("data");
+                    provider.defaultSerializeValue(variant.value(), gen);
+                    gen.writeEndObject();
+                    return;
+                }
+                if (value instanceof AdjacentCount variant) {
+                    gen.writeStartObject();
+                    gen.writeStringField("kind", "count");
+                    gen.writeFieldName("data");
+                    provider.defaultSerializeValue(variant.value(), gen);
+                    gen.writeEndObject();
+                    return;
+                }
+            }
+        }
+
+        final class Deserializer extends StdDeserializer<Adjacent> {
+            public Deserializer() {
+                super(Adjacent.class);
+            }
+
+            @Override
+            public Adjacent deserialize(JsonParser p, DeserializationContext ctxt) throws IOException {
+                TypeFactory types = ctxt.getTypeFactory();
+                JsonNode node = ctxt.readTree(p);
+                if (!(node instanceof ObjectNode object) || !object.path("kind").isTextual() || !object.has("data")) {
+                    throw JsonMappingException.from(p, "Adjacent must be an object with kind and data");
+                }
+                String key = object.get("kind").asText();
+                JsonNode variant = object.get("data");
+                return switch (key) {
+                    case "user" -> new AdjacentUser(ctxt.readTreeAsValue(variant, types.constructType(User.class)));
+                    case "count" -> new AdjacentCount(ctxt.readTreeAsValue(variant, types.constructType(Long.class)));
+                    default -> throw JsonMappingException.from(p, "Unknown Adjacent variant " + key);
+                };
+            }
+        }
+    }
+
+    public record AdjacentUser(User value) implements Adjacent {
+    }
+
+    public record AdjacentCount(long value) implements Adjacent {
+    }
+
+    @JsonSerialize(using = Untagged.Serializer.class)
+    @JsonDeserialize(using = Untagged.Deserializer.class)
+    public sealed interface Untagged {
+        final class Serializer extends StdSerializer<Untagged> {
+            public Serializer() {
+                super(Untagged.class);
+            }
+
+            @Override
+            public void serialize(Untagged value, JsonGenerator gen, SerializerProvider provider) throws IOException {
+                if (value instanceof UntaggedUser variant) {
+                    provider.defaultSerializeValue(variant.value(), gen);
+                    return;
+                }
+                if (value instanceof UntaggedName variant) {
+                    provider.defaultSerializeValue(variant.value(), gen);
+                    return;
+                }
+            }
+        }
+
+        final class Deserializer extends StdDeserializer<Untagged> {
+            public Deserializer() {
+                super(Untagged.class);
+            }
+
+            @Override
+            public Untagged deserialize(JsonParser p, DeserializationContext ctxt) throws IOException {
+                TypeFactory types = ctxt.getTypeFactory();
+                JsonNode node = ctxt.readTree(p);
+                try {
+                    return new UntaggedUser(ctxt.readTreeAsValue(node, types.constructType(User.class)));
+                } catch (IOException e) {
+                    // The next variant is tried
+                }
+                try {
+                    return new UntaggedName(ctxt.readTreeAsValue(node, types.constructType(String.class)));
+                } catch (IOException e) {
+                    // The next variant is tried
+                }
+                throw JsonMappingException.from(p, "Untagged does not match any of its variants");
+            }
+        }
+    }
+
+    public record UntaggedUser(User value) implements Untagged {
+    }
+
+    public record UntaggedName(String value) implements Untagged {
+    }
+
+    @JsonSerialize(using = Outcome.Serializer.class)
+    @JsonDeserialize(using = Outcome.Deserializer.class)
+    public sealed interface Outcome<T> {
+        final class Serializer extends StdSerializer<Outcome<?>> {
+            public Serializer() {
+                super(Outcome.class, false);
+            }
+
+            @Override
+            public void serialize(Outcome<?> value, JsonGenerator gen, SerializerProvider provider) throws IOException {
+                if (value instanceof OutcomeOk<?> variant) {
+                    gen.writeStartObject();
+                    gen.writeFieldName("ok");
+                    provider.defaultSerializeValue(variant.value(), gen);
+                    gen.writeEndObject();
+                    return;
+                }
+                if (value instanceof OutcomeErr<?> variant) {
+                    gen.writeStartObject();
+                    gen.writeFieldName("err");
+                    provider.defaultSerializeValue(variant.value(), gen);
+                    gen.writeEndObject();
+                    return;
+                }
+            }
+        }
+
+        final class Deserializer extends StdDeserializer<Outcome<?>> implements ContextualDeserializer {
+            private final JavaType type;
+
+            public Deserializer() {
+                this(null);
+            }
+
+            private Deserializer(JavaType type) {
+                super(Outcome.class);
+                this.type = type;
+            }
+
+            @Override
+            public JsonDeserializer<?> createContextual(DeserializationContext ctxt, BeanProperty property) {
+                return new Deserializer(ctxt.getContextualType());
+            }
+
+            @Override
+            public Outcome<?> deserialize(JsonParser p, DeserializationContext ctxt) throws IOException {
+                TypeFactory types = ctxt.getTypeFactory();
+                JavaType type = this.type != null ? this.type : types.constructType(Outcome.class);
+                JsonNode node = ctxt.readTree(p);
+                if (!node.isObject() || node.size() != 1) {
+                    throw JsonMappingException.from(p, "Outcome must have a single key");
+                }
+                Map.Entry<String, JsonNode> entry = node.fields().next();
+                String key = entry.getKey();
+                JsonNode variant = entry.getValue();
+                return switch (key) {
+                    case "ok" -> new OutcomeOk<>(ctxt.readTreeAsValue(variant, type.containedTypeOrUnknown(0)));
+                    case "err" -> new OutcomeErr<>(ctxt.readTreeAsValue(variant, types.constructType(String.class)));
+                    default -> throw JsonMappingException.from(p, "Unknown Outcome variant " + key);
+                };
+            }
+        }
+    }
+
+    public record OutcomeOk<T>(T value) implements Outcome<T> {
+    }
+
+    public record OutcomeErr<T>(String value) implements Outcome<T> {
+    }
+
+    public record Response(
+            Page<User> users,
+            Outcome<Long> outcome) {
+    }
+}
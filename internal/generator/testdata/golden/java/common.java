@@ -0,0 +1,54 @@
+package com.example.api;
+
+import com.example.api.api.*;
+import com.fasterxml.jackson.annotation.JsonCreator;
+import com.fasterxml.jackson.annotation.JsonProperty;
+import com.fasterxml.jackson.annotation.JsonValue;
+
+public final class common {
+    private common() {
+    }
+
+    public enum Status {
+        Active,
+        @JsonProperty("done")
+        Done,
+    }
+
+    public enum Priority {
+        Low(1L),
+        High(10L);
+
+        private final long value;
+
+        Priority(long value) {
+            this.value = value;
+        }
+
+        @JsonValue
+        public long value() {
+            return value;
+        }
+
+        @JsonCreator
+        public static Priority of(long value) {
+            for (Priority variant : values()) {
+                if (variant.value == value) {
+                    return variant;
+                }
+            }
+            throw new IllegalArgumentException("Unknown Priority value " + value);
+        }
+    }
+
+    public record Address(
+            String street,
+            String city) {
+    }
+
+    public record UserId(@JsonValue String value) {
+        @JsonCreator(mode = JsonCreator.Mode.DELEGATING)
+        public UserId {
+        }
+    }
+}
@@ -0,0 +1,239 @@
+package com.example.api
+
+import kotlinx.serialization.EncodeDefault
+import kotlinx.serialization.ExperimentalSerializationApi
+import kotlinx.serialization.KSerializer
+import kotlinx.serialization.Serializable
+import kotlinx.serialization.SerializationException
+import kotlinx.serialization.descriptors.PrimitiveKind
+import kotlinx.serialization.descriptors.PrimitiveSerialDescriptor
+import kotlinx.serialization.descriptors.SerialDescriptor
+import kotlinx.serialization.encoding.Decoder
+import kotlinx.serialization.encoding.Encoder
+import kotlinx.serialization.json.JsonDecoder
+import kotlinx.serialization.json.JsonElement
+import kotlinx.serialization.json.JsonEncoder
+import kotlinx.serialization.json.JsonObject
+import kotlinx.serialization.json.JsonPrimitive
+import kotlinx.serialization.json.jsonObject
+import kotlinx.serialization.json.jsonPrimitive
+
+/**
+ * A user of the API
+ */
+@Serializable
+data class User(
+    val id: UserId,
+    val name: String,
+    val nickname: String? = null,
+    val bio: String?,
+    val balance: Int64,
+    val ids: List<Int64>,
+    val status: Status,
+    val address: Address? = null,
+)
+
+@OptIn(ExperimentalSerializationApi::class)
+@Serializable
+data class Settings(
+    @EncodeDefault
+    val retries: Long = 3,
+    @EncodeDefault
+    val status: Status = Status.Active,
+    @EncodeDefault
+    val priority: Priority = Priority.High,
+    @EncodeDefault
+    val tags: List<String> = emptyList(),
+    @EncodeDefault
+    val labels: Map<String, String> = emptyMap(),
+)
+
+@Serializable
+data class Page<T>(
+    val items: List<T>,
+    val next: String? = null,
+)
+
+@Serializable(with = External.Serializer::class)
+sealed class External {
+    object Serializer : KSerializer<External> {
+        override val descriptor: SerialDescriptor = JsonElement.serializer().descriptor
+
+        override fun serialize(encoder: Encoder, value: External) {
+            val output = encoder as JsonEncoder
+            val element = when (value) {
+                is ExternalUser -> JsonObject(mapOf("user" to output.json.encodeToJsonElement(ExternalUser.serializer(), value).jsonObject.getValue("value")))
+                is ExternalCount -> JsonObject(mapOf("count" to output.json.encodeToJsonElement(ExternalCount.serializer(), value).jsonObject.getValue("value")))
+            }
+            output.encodeJsonElement(element)
+        }
+
+        override fun deserialize(decoder: Decoder): External {
+            val input = decoder as JsonDecoder
+            val element = input.decodeJsonElement()
+            val (key, variant) = element.jsonObject.entries.singleOrNull() ?: throw SerializationException("External must have a single key")
+            return when (key) {
+                "user" -> input.json.decodeFromJsonElement(ExternalUser.serializer(), JsonObject(mapOf("value" to variant)))
+                "count" -> input.json.decodeFromJsonElement(ExternalCount.serializer(), JsonObject(mapOf("value" to variant)))
+                else -> throw SerializationException("Unknown External variant " + key)
+            }
+        }
+    }
+}
+
+@Serializable
+data class ExternalUser(val value: User) : External()
+
+@Serializable
+data class ExternalCount(val value: Long) : External()
+
+@Serializable(with = Internal.Serializer::class)
+sealed class Internal {
+    object Serializer : KSerializer<Internal> {
+        override val descriptor: SerialDescriptor = JsonElement.serializer().descriptor
+
+        override fun serialize(encoder: Encoder, value: Internal) {
+            val output = encoder as JsonEncoder
+            val element = when (value) {
+                is InternalUser -> JsonObject(mapOf<String, JsonElement>("kind" to JsonPrimitive("user")) + output.json.encodeToJsonElement(InternalUser.serializer(), value).jsonObject.getValue("value").jsonObject)
+                is InternalSettings -> JsonObject(mapOf<String, JsonElement>("kind" to JsonPrimitive("settings")) + output.json.encodeToJsonElement(InternalSettings.serializer(), value).jsonObject.getValue("value").jsonObject)
+            }
+            output.encodeJsonElement(element)
+        }
+
+        override fun deserialize(decoder: Decoder): Internal {
+            val input = decoder as JsonDecoder
+            val element = input.decodeJsonElement()
+            val key = element.jsonObject["kind"]?.jsonPrimitive?.content
+            val variant = JsonObject(element.jsonObject - "kind")
+            return when (key) {
+                "user" -> input.json.decodeFromJsonElement(InternalUser.serializer(), JsonObject(mapOf("value" to variant)))
+                "settings" -> input.json.decodeFromJsonElement(InternalSettings.serializer(), JsonObject(mapOf("value" to variant)))
+                else -> throw SerializationException("Unknown Internal variant " + key)
+            }
+        }
+    }
+}
+
+@Serializable
+data class InternalUser(val value: User) : Internal()
+
+@Serializable
+data class InternalSettings(val value: Settings) : Internal()
+
+@Serializable(with = Adjacent.Serializer::class)
+sealed class Adjacent {
+    object Serializer : KSerializer<Adjacent> {
+        override val descriptor: SerialDescriptor = JsonElement.serializer().descriptor
+
+        override fun serialize(encoder: Encoder, value: Adjacent) {
+            val output = encoder as JsonEncoder
+            val element = when (value) {
+                is AdjacentUser -> JsonObject(mapOf<String, JsonElement>("kind" to JsonPrimitive("user"), "data" to output.json.encodeToJsonElement(AdjacentUser.serializer(), value).jsonObject.getValue("value")))
+                is AdjacentCount -> JsonObject(mapOf<String, JsonElement>("kind" to JsonPrimitive("count"), "data" to output.json.encodeToJsonElement(AdjacentCount.serializer(), value).jsonObject.getValue("value")))
+            }
+            output.encodeJsonElement(element)
+        }
+
+        override fun deserialize(decoder: Decoder): Adjacent {
+            val input = decoder as JsonDecoder
+            val element = input.decodeJsonElement()
+            val key = element.jsonObject["kind"]?.jsonPrimitive?.content
+            val variant = element.jsonObject["data"] ?: throw SerializationException("Adjacent is missing data")
+            return when (key) {
+                "user" -> input.json.decodeFromJsonElement(AdjacentUser.serializer(), JsonObject(mapOf("value" to variant)))
+                "count" -> input.json.decodeFromJsonElement(AdjacentCount.serializer(), JsonObject(mapOf("value" to variant)))
+                else -> throw SerializationException("Unknown Adjacent variant " + key)
+            }
+        }
+    }
+}
+
+@Serializable
+data class AdjacentUser(val value: User) : Adjacent()
+
+@Serializable
+data class AdjacentCount(val value: Long) : Adjacent()
+
+@Serializable(with = Untagged.Serializer::class)
+sealed class Untagged {
+    object Serializer : KSerializer<Untagged> {
+        override val descriptor: SerialDescriptor = JsonElement.serializer().descriptor
+
+        override fun serialize(encoder: Encoder, value: Untagged) {
+            val output = encoder as JsonEncoder
+            val element = when (value) {
+                is UntaggedUser -> output.json.encodeToJsonElement(UntaggedUser.serializer(), value).jsonObject.getValue("value")
+                is UntaggedName -> output.json.encodeToJsonElement(UntaggedName.serializer(), value).jsonObject.getValue("value")
+            }
+            output.encodeJsonElement(element)
+        }
+
+        override fun deserialize(decoder: Decoder): Untagged {
+            val input = decoder as JsonDecoder
+            val element = input.decodeJsonElement()
+            runCatching { return input.json.decodeFromJsonElement(UntaggedUser.serializer(), JsonObject(mapOf("value" to element))) }
+            runCatching { return input.json.decodeFromJsonElement(UntaggedName.serializer(), JsonObject(mapOf("value" to element))) }
+            throw SerializationException("Untagged does not match any of its variants")
+        }
+    }
+}
+
+@Serializable
+data class UntaggedUser(val value: User) : Untagged()
+
+@Serializable
+data class UntaggedName(val value: String) : Untagged()
+
+@Serializable(with = Outcome.Serializer::class)
+sealed class Outcome<T> {
+    class Serializer<T>(private val tSerializer: KSerializer<T>) : KSerializer<Outcome<T>> {
+        override val descriptor: SerialDescriptor = JsonElement.serializer().descriptor
+
+        override fun serialize(encoder: Encoder, value: Outcome<T>) {
+            val output = encoder as JsonEncoder
+            val element = when (value) {
+                is OutcomeOk -> JsonObject(mapOf("ok" to output.json.encodeToJsonElement(OutcomeOk.serializer(tSerializer), value).jsonObject.getValue("value")))
+                is OutcomeErr -> JsonObject(mapOf("err" to output.json.encodeToJsonElement(OutcomeErr.serializer(tSerializer), value).jsonObject.getValue("value")))
+            }
+            output.encodeJsonElement(element)
+        }
+
+        override fun deserialize(decoder: Decoder): Outcome<T> {
+            val input = decoder as JsonDecoder
+            val element = input.decodeJsonElement()
+            val (key, variant) = element.jsonObject.entries.singleOrNull() ?: throw SerializationException("Outcome must have a single key")
+            return when (key) {
+                "ok" -> input.json.decodeFromJsonElement(OutcomeOk.serializer(tSerializer), JsonObject(mapOf("value" to variant)))
+                "err" -> input.json.decodeFromJsonElement(OutcomeErr.serializer(tSerializer), JsonObject(mapOf("value" to variant)))
+                else -> throw SerializationException("Unknown Outcome variant " + key)
+            }
+        }
+    }
+}
+
+@Serializable
+data class OutcomeOk<T>(val value: T) : Outcome<T>()
+
+@Serializable
+data class OutcomeErr<T>(val value: String) : Outcome<T>()
+
+@Serializable
+data class Response(
+    val users: Page<User>,
+    val outcome: Outcome<Long>,
+)
+
+/**
+ * A Long which is a string in JSON, as JavaScript numbers cannot hold every
+ * Long.
+ */
+typealias Int64 = @Serializable(with = Int64Serializer::class) Long
+
+object Int64Serializer : KSerializer<Long> {
+    override val descriptor: SerialDescriptor = PrimitiveSerialDescriptor("Int64", PrimitiveKind.STRING)
+
+    override fun serialize(encoder: Encoder, value: Long) = encoder.encodeString(value.toString())
+
+    override fun deserialize(decoder: Decoder): Long = decoder.decodeString().toLong()
+}
@@ -0,0 +1,46 @@
+package com.example.api
+
+import kotlinx.serialization.KSerializer
+import kotlinx.serialization.SerialName
+import kotlinx.serialization.Serializable
+import kotlinx.serialization.SerializationException
+import kotlinx.serialization.descriptors.PrimitiveKind
+import kotlinx.serialization.descriptors.PrimitiveSerialDescriptor
+import kotlinx.serialization.descriptors.SerialDescriptor
+import kotlinx.serialization.encoding.Decoder
+import kotlinx.serialization.encoding.Encoder
+
+@Serializable
+enum class Status {
+    Active,
+    @SerialName("done")
+    Done,
+}
+
+@Serializable(with = Priority.Serializer::class)
+enum class Priority(val value: Long) {
+    Low(1),
+    High(10),
+    ;
+
+    object Serializer : KSerializer<Priority> {
+        override val descriptor: SerialDescriptor = PrimitiveSerialDescriptor("Priority", PrimitiveKind.LONG)
+
+        override fun serialize(encoder: Encoder, value: Priority) = encoder.encodeLong(value.value)
+
+        override fun deserialize(decoder: Decoder): Priority {
+            val value = decoder.decodeLong()
+            return Priority.values().firstOrNull { it.value == value } ?: throw SerializationException("Unknown Priority value " + value)
+        }
+    }
+}
+
+@Serializable
+data class Address(
+    val street: String,
+    val city: String,
+)
+
+@Serializable
+@JvmInline
+value class UserId(val value: String)
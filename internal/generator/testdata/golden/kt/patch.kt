@@ -0,0 +1,55 @@
+package com.example.api
+
+import kotlinx.serialization.KSerializer
+import kotlinx.serialization.Serializable
+import kotlinx.serialization.SerializationException
+import kotlinx.serialization.descriptors.PrimitiveKind
+import kotlinx.serialization.descriptors.PrimitiveSerialDescriptor
+import kotlinx.serialization.descriptors.SerialDescriptor
+import kotlinx.serialization.encoding.Decoder
+import kotlinx.serialization.encoding.Encoder
+
+@Serializable
+data class Patch(
+    val name: String? = null,
+    val bio: Optional<String?> = Optional.Absent,
+    val count: Optional<Long?> = Optional.Absent,
+    val balance: Optional<Int64?> = Optional.Absent,
+)
+
+/**
+ * A Long which is a string in JSON, as JavaScript numbers cannot hold every
+ * Long.
+ */
+typealias Int64 = @Serializable(with = Int64Serializer::class) Long
+
+object Int64Serializer : KSerializer<Long> {
+    override val descriptor: SerialDescriptor = PrimitiveSerialDescriptor("Int64", PrimitiveKind.STRING)
+
+    override fun serialize(encoder: Encoder, value: Long) = encoder.encodeString(value.toString())
+
+    override fun deserialize(decoder: Decoder): Long = decoder.decodeString().toLong()
+}
+
+/**
+ * A property which can be absent, null or have a value. Absent properties are
+ * the default, which is left out when encodeDefaults is false, as it is by
+ * default.
+ */
+@Serializable(with = OptionalSerializer::class)
+sealed interface Optional<out T> {
+    object Absent : Optional<Nothing>
+
+    data class Present<out T>(val value: T) : Optional<T>
+}
+
+class OptionalSerializer<T>(private val valueSerializer: KSerializer<T>) : KSerializer<Optional<T>> {
+    override val descriptor: SerialDescriptor = valueSerializer.descriptor
+
+    override fun serialize(encoder: Encoder, value: Optional<T>) = when (value) {
+        is Optional.Present -> encoder.encodeSerializableValue(valueSerializer, value.value)
+        Optional.Absent -> throw SerializationException("Absent properties are left out by not encoding defaults")
+    }
+
+    override fun deserialize(decoder: Decoder): Optional<T> = Optional.Present(decoder.decodeSerializableValue(valueSerializer))
+}
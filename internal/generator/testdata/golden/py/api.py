@@ -0,0 +1,114 @@
+from __future__ import annotations
+
+from typing import Annotated, Any, Generic, Literal, Optional, TypeVar, Union
+
+from pydantic import BaseModel, ConfigDict, Field, PlainSerializer, SerializationInfo, SerializerFunctionWrapHandler, model_serializer
+from typing_extensions import TypeAliasType
+
+from .common import UserId, Status, Address, Priority
+
+T = TypeVar("T")
+
+# An int which is a string in JSON, as JavaScript numbers cannot hold every
+# 64 bit integer.
+Int64 = Annotated[int, PlainSerializer(str, return_type=str, when_used="json")]
+
+
+class User(BaseModel):
+    """A user of the API"""
+    id: UserId
+    name: str
+    nickname: Optional[str] = None
+    bio: Optional[str]
+    balance: Int64
+    ids: list[Int64]
+    status: Status
+    address: Optional[Address] = None
+
+    @model_serializer(mode="wrap")
+    def _leave_out_absent_fields(self, handler: SerializerFunctionWrapHandler, info: SerializationInfo) -> Any:
+        data = handler(self)
+        for name, alias, nullable in (("nickname", "nickname", False), ("address", "address", False)):
+            if name not in self.model_fields_set or (not nullable and getattr(self, name) is None):
+                data.pop(alias if info.by_alias else name, None)
+        return data
+
+
+class Settings(BaseModel):
+    model_config = ConfigDict(validate_default=True)
+    retries: int = 3
+    status: Status = Status.Active
+    priority: Priority = Priority.High
+    tags: list[str] = Field(default_factory=list)
+    labels: dict[str, str] = Field(default_factory=dict)
+
+
+class Page(BaseModel, Generic[T]):
+    items: list[T]
+    next: Optional[str] = None
+
+    @model_serializer(mode="wrap")
+    def _leave_out_absent_fields(self, handler: SerializerFunctionWrapHandler, info: SerializationInfo) -> Any:
+        data = handler(self)
+        for name, alias, nullable in (("next", "next", False),):
+            if name not in self.model_fields_set or (not nullable and getattr(self, name) is None):
+                data.pop(alias if info.by_alias else name, None)
+        return data
+
+
+class ExternalUser(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+    user: User
+
+
+class ExternalCount(BaseModel):
+    model_config = ConfigDict(extra="forbid")
+    count: int
+
+
+External = Union[ExternalUser, ExternalCount]
+
+
+class InternalUser(User):
+    kind: Literal["user"]
+
+
+class InternalSettings(Settings):
+    kind: Literal["settings"]
+
+
+Internal = Annotated[Union[InternalUser, InternalSettings], Field(discriminator="kind")]
+
+
+class AdjacentUser(BaseModel):
+    kind: Literal["user"]
+    data: User
+
+
+class AdjacentCount(BaseModel):
+    kind: Literal["count"]
+    data: int
+
+
+Adjacent = Annotated[Union[AdjacentUser, AdjacentCount], Field(discriminator="kind")]
+
+
+Untagged = Union[User, str]
+
+
+class OutcomeOk(BaseModel, Generic[T]):
+    model_config = ConfigDict(extra="forbid")
+    ok: T
+
+
+class OutcomeErr(BaseModel, Generic[T]):
+    model_config = ConfigDict(extra="forbid")
+    err: str
+
+
+Outcome = TypeAliasType("Outcome", Union[OutcomeOk[T], OutcomeErr[T]], type_params=(T,))
+
+
+class Response(BaseModel):
+    users: Page[User]
+    outcome: Outcome[int]
@@ -0,0 +1,24 @@
+from __future__ import annotations
+
+from enum import Enum, IntEnum
+from typing import NewType
+
+from pydantic import BaseModel
+
+
+class Status(str, Enum):
+    Active = "Active"
+    Done = "done"
+
+
+class Priority(IntEnum):
+    Low = 1
+    High = 10
+
+
+class Address(BaseModel):
+    street: str
+    city: str
+
+
+UserId = NewType("UserId", str)
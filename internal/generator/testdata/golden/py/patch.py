@@ -0,0 +1,24 @@
+from __future__ import annotations
+
+from typing import Annotated, Any, Optional
+
+from pydantic import BaseModel, PlainSerializer, SerializationInfo, SerializerFunctionWrapHandler, model_serializer
+
+# An int which is a string in JSON, as JavaScript numbers cannot hold every
+# 64 bit integer.
+Int64 = Annotated[int, PlainSerializer(str, return_type=str, when_used="json")]
+
+
+class Patch(BaseModel):
+    name: Optional[str] = None
+    bio: Optional[str] = None
+    count: Optional[int] = None
+    balance: Optional[Int64] = None
+
+    @model_serializer(mode="wrap")
+    def _leave_out_absent_fields(self, handler: SerializerFunctionWrapHandler, info: SerializationInfo) -> Any:
+        data = handler(self)
+        for name, alias, nullable in (("name", "name", False), ("bio", "bio", True), ("count", "count", True), ("balance", "balance", True)):
+            if name not in self.model_fields_set or (not nullable and getattr(self, name) is None):
+                data.pop(alias if info.by_alias else name, None)
+        return data
@@ -0,0 +1,100 @@
+use serde::{Deserialize, Deserializer, Serialize, Serializer};
+use std::collections::HashMap;
+use super::common::{UserId, Status, Address, Priority};
+/// A user of the API
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct User {
+pub id: UserId,
+pub name: String,
+#[serde(skip_serializing_if = "Option::is_none")]
+pub nickname: Option<String>,
+pub bio: Option<String>,
+pub balance: Int64,
+pub ids: Vec<Int64>,
+pub status: Status,
+#[serde(skip_serializing_if = "Option::is_none")]
+pub address: Option<Address>,
+}
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct Settings {
+#[serde(default = "default_settings_retries")]
+pub retries: i64,
+#[serde(default = "default_settings_status")]
+pub status: Status,
+#[serde(default = "default_settings_priority")]
+pub priority: Priority,
+#[serde(default = "default_settings_tags")]
+pub tags: Vec<String>,
+#[serde(default = "default_settings_labels")]
+pub labels: HashMap<String, String>,
+}
+fn default_settings_retries() -> i64 { 3 }
+fn default_settings_status() -> Status { Status::Active }
+fn default_settings_priority() -> Priority { Priority::High }
+fn default_settings_tags() -> Vec<String> { Vec::new() }
+fn default_settings_labels() -> HashMap<String, String> { HashMap::new() }
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct Page<T> {
+pub items: Vec<T>,
+#[serde(skip_serializing_if = "Option::is_none")]
+pub next: Option<String>,
+}
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub enum External {
+#[serde(rename = "user")]
+User(User),
+#[serde(rename = "count")]
+Count(i64),
+}
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+#[serde(tag = "kind")]
+pub enum Internal {
+#[serde(rename = "user")]
+User(User),
+#[serde(rename = "settings")]
+Settings(Settings),
+}
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+#[serde(tag = "kind", content = "data")]
+pub enum Adjacent {
+#[serde(rename = "user")]
+User(User),
+#[serde(rename = "count")]
+Count(i64),
+}
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+#[serde(untagged)]
+pub enum Untagged {
+#[serde(rename = "user")]
+User(User),
+#[serde(rename = "name")]
+Name(String),
+}
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub enum Outcome<T> {
+#[serde(rename = "ok")]
+Ok(T),
+#[serde(rename = "err")]
+Err(String),
+}
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct Response {
+pub users: Page<User>,
+pub outcome: Outcome<i64>,
+}
+
+/// An i64 which is a string in JSON, as JavaScript numbers cannot hold every
+/// i64.
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Hash, PartialOrd, Ord, Default)]
+pub struct Int64(pub i64);
+
+impl Serialize for Int64 {
+fn serialize<S: Serializer>(&self, serializer: S) -> Result<S::Ok, S::Error> { serializer.collect_str(&self.0) }
+}
+
+impl<'de> Deserialize<'de> for Int64 {
+fn deserialize<D: Deserializer<'de>>(deserializer: D) -> Result<Self, D::Error> {
+let s = String::deserialize(deserializer)?;
+s.parse().map(Int64).map_err(serde::de::Error::custom)
+}
+}
@@ -0,0 +1,33 @@
+use serde::{Deserialize, Deserializer, Serialize, Serializer};
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Hash, Serialize, Deserialize)]
+pub enum Status {
+Active,
+#[serde(rename = "done")]
+Done,
+}
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Hash)]
+#[repr(i64)]
+pub enum Priority {
+Low = 1,
+High = 10,
+}
+impl Serialize for Priority {
+fn serialize<S: Serializer>(&self, serializer: S) -> Result<S::Ok, S::Error> { serializer.serialize_i64(*self as i64) }
+}
+impl<'de> Deserialize<'de> for Priority {
+fn deserialize<D: Deserializer<'de>>(deserializer: D) -> Result<Self, D::Error> {
+match i64::deserialize(deserializer)? {
+1 => Ok(Priority::Low),
+10 => Ok(Priority::High),
+value => Err(serde::de::Error::custom(format!("unknown Priority value {}", value))),
+}
+}
+}
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct Address {
+pub street: String,
+pub city: String,
+}
+#[derive(Debug, Clone, PartialEq, Eq, Hash, Serialize, Deserialize)]
+#[serde(transparent)]
+pub struct UserId(pub String);
@@ -0,0 +1,34 @@
+use serde::{Deserialize, Deserializer, Serialize, Serializer};
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct Patch {
+#[serde(skip_serializing_if = "Option::is_none")]
+pub name: Option<String>,
+#[serde(default = "Option::default", skip_serializing_if = "Option::is_none", deserialize_with = "deserialize_nullable")]
+pub bio: Option<Option<String>>,
+#[serde(default = "Option::default", skip_serializing_if = "Option::is_none", deserialize_with = "deserialize_nullable")]
+pub count: Option<Option<i64>>,
+#[serde(default = "Option::default", skip_serializing_if = "Option::is_none", deserialize_with = "deserialize_nullable")]
+pub balance: Option<Option<Int64>>,
+}
+
+/// An i64 which is a string in JSON, as JavaScript numbers cannot hold every
+/// i64.
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Hash, PartialOrd, Ord, Default)]
+pub struct Int64(pub i64);
+
+impl Serialize for Int64 {
+fn serialize<S: Serializer>(&self, serializer: S) -> Result<S::Ok, S::Error> { serializer.collect_str(&self.0) }
+}
+
+impl<'de> Deserialize<'de> for Int64 {
+fn deserialize<D: Deserializer<'de>>(deserializer: D) -> Result<Self, D::Error> {
+let s = String::deserialize(deserializer)?;
+s.parse().map(Int64).map_err(serde::de::Error::custom)
+}
+}
+
+/// Deserializes a field which can be absent, null or have a value, which
+/// serde would otherwise read as absent when null.
+fn deserialize_nullable<'de, T: Deserialize<'de>, D: Deserializer<'de>>(deserializer: D) -> Result<Option<Option<T>>, D::Error> {
+Option::<T>::deserialize(deserializer).map(Some)
+}
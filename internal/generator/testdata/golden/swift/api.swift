@@ -0,0 +1,294 @@
+import Foundation
+
+/// A user of the API
+struct User: Codable {
+    var id: UserId
+    var name: String
+    var nickname: String?
+    var bio: String?
+    var balance: Int64String
+    var ids: [Int64String]
+    var status: Status
+    var address: Address?
+
+    enum CodingKeys: String, CodingKey {
+        case id
+        case name
+        case nickname
+        case bio
+        case balance
+        case ids
+        case status
+        case address
+    }
+}
+
+extension User {
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.container(keyedBy: CodingKeys.self)
+        try container.encode(self.id, forKey: .id)
+        try container.encode(self.name, forKey: .name)
+        try container.encodeIfPresent(self.nickname, forKey: .nickname)
+        try container.encode(self.bio, forKey: .bio)
+        try container.encode(self.balance, forKey: .balance)
+        try container.encode(self.ids, forKey: .ids)
+        try container.encode(self.status, forKey: .status)
+        try container.encodeIfPresent(self.address, forKey: .address)
+    }
+}
+
+struct Settings: Codable {
+    var retries: Int = 3
+    var status: Status = Status.active
+    var priority: Priority = Priority.high
+    var tags: [String] = []
+    var labels: [String: String] = [:]
+
+    enum CodingKeys: String, CodingKey {
+        case retries
+        case status
+        case priority
+        case tags
+        case labels
+    }
+}
+
+extension Settings {
+    init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        self.retries = try container.decodeIfPresent(Int.self, forKey: .retries) ?? 3
+        self.status = try container.decodeIfPresent(Status.self, forKey: .status) ?? Status.active
+        self.priority = try container.decodeIfPresent(Priority.self, forKey: .priority) ?? Priority.high
+        self.tags = try container.decodeIfPresent([String].self, forKey: .tags) ?? []
+        self.labels = try container.decodeIfPresent([String: String].self, forKey: .labels) ?? [:]
+    }
+}
+
+struct Page<T: Codable>: Codable {
+    var items: [T]
+    var next: String?
+}
+
+enum External: Codable {
+    case user(User)
+    case count(Int)
+
+    private enum CodingKeys: String, CodingKey {
+        case user
+        case count
+    }
+
+    private struct AnyKey: CodingKey {
+        var stringValue: String
+        var intValue: Int? { nil }
+
+        init(stringValue: String) {
+            self.stringValue = stringValue
+        }
+
+        init?(intValue: Int) {
+            return nil
+        }
+    }
+
+    init(from decoder: Decoder) throws {
+        let keys = try decoder.container(keyedBy: AnyKey.self).allKeys
+        guard keys.count == 1 else {
+            throw DecodingError.dataCorrupted(DecodingError.Context(codingPath: decoder.codingPath, debugDescription: "External must have a single key"))
+        }
+        guard let key = CodingKeys(stringValue: keys[0].stringValue) else {
+            throw DecodingError.dataCorrupted(DecodingError.Context(codingPath: decoder.codingPath, debugDescription: "Unknown External variant \(keys[0].stringValue)"))
+        }
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        switch key {
+        case .user:
+            self = .user(try container.decode(User.self, forKey: .user))
+        case .count:
+            self = .count(try container.decode(Int.self, forKey: .count))
+        }
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.container(keyedBy: CodingKeys.self)
+        switch self {
+        case .user(let value):
+            try container.encode(value, forKey: .user)
+        case .count(let value):
+            try container.encode(value, forKey: .count)
+        }
+    }
+}
+
+enum Internal: Codable {
+    case user(User)
+    case settings(Settings)
+
+    private enum TagKeys: String, CodingKey {
+        case tag = "kind"
+    }
+
+    init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: TagKeys.self)
+        let tag = try container.decode(String.self, forKey: .tag)
+        switch tag {
+        case "user":
+            self = .user(try User(from: decoder))
+        case "settings":
+            self = .settings(try Settings(from: decoder))
+        default:
+            throw DecodingError.dataCorruptedError(forKey: .tag, in: container, debugDescription: "Unknown Internal variant \(tag)")
+        }
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.container(keyedBy: TagKeys.self)
+        switch self {
+        case .user(let value):
+            try container.encode("user", forKey: .tag)
+            try value.encode(to: encoder)
+        case .settings(let value):
+            try container.encode("settings", forKey: .tag)
+            try value.encode(to: encoder)
+        }
+    }
+}
+
+enum Adjacent: Codable {
+    case user(User)
+    case count(Int)
+
+    private enum TagKeys: String, CodingKey {
+        case tag = "kind"
+        case content = "data"
+    }
+
+    init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: TagKeys.self)
+        let tag = try container.decode(String.self, forKey: .tag)
+        switch tag {
+        case "user":
+            self = .user(try container.decode(User.self, forKey: .content))
+        case "count":
+            self = .count(try container.decode(Int.self, forKey: .content))
+        default:
+            throw DecodingError.dataCorruptedError(forKey: .tag, in: container, debugDescription: "Unknown Adjacent variant \(tag)")
+        }
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.container(keyedBy: TagKeys.self)
+        switch self {
+        case .user(let value):
+            try container.encode("user", forKey: .tag)
+            try container.encode(value, forKey: .content)
+        case .count(let value):
+            try container.encode("count", forKey: .tag)
+            try container.encode(value, forKey: .content)
+        }
+    }
+}
+
+enum Untagged: Codable {
+    case user(User)
+    case name(String)
+
+    init(from decoder: Decoder) throws {
+        let container = try decoder.singleValueContainer()
+        if let value = try? container.decode(User.self) {
+            self = .user(value)
+        } else if let value = try? container.decode(String.self) {
+            self = .name(value)
+        } else {
+            throw DecodingError.dataCorruptedError(in: container, debugDescription: "Untagged does not match any of its variants")
+        }
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.singleValueContainer()
+        switch self {
+        case .user(let value):
+            try container.encode(value)
+        case .name(let value):
+            try container.encode(value)
+        }
+    }
+}
+
+enum Outcome<T: Codable>: Codable {
+    case ok(T)
+    case err(String)
+
+    private enum CodingKeys: String, CodingKey {
+        case ok
+        case err
+    }
+
+    private struct AnyKey: CodingKey {
+        var stringValue: String
+        var intValue: Int? { nil }
+
+        init(stringValue: String) {
+            self.stringValue = stringValue
+        }
+
+        init?(intValue: Int) {
+            return nil
+        }
+    }
+
+    init(from decoder: Decoder) throws {
+        let keys = try decoder.container(keyedBy: AnyKey.self).allKeys
+        guard keys.count == 1 else {
+            throw DecodingError.dataCorrupted(DecodingError.Context(codingPath: decoder.codingPath, debugDescription: "Outcome must have a single key"))
+        }
+        guard let key = CodingKeys(stringValue: keys[0].stringValue) else {
+            throw DecodingError.dataCorrupted(DecodingError.Context(codingPath: decoder.codingPath, debugDescription: "Unknown Outcome variant \(keys[0].stringValue)"))
+        }
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        switch key {
+        case .ok:
+            self = .ok(try container.decode(T.self, forKey: .ok))
+        case .err:
+            self = .err(try container.decode(String.self, forKey: .err))
+        }
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.container(keyedBy: CodingKeys.self)
+        switch self {
+        case .ok(let value):
+            try container.encode(value, forKey: .ok)
+        case .err(let value):
+            try container.encode(value, forKey: .err)
+        }
+    }
+}
+
+struct Response: Codable {
+    var users: Page<User>
+    var outcome: Outcome<Int>
+}
+
+/// An Int64 which is a string in JSON, as JavaScript numbers cannot hold every
+/// Int64.
+struct Int64String: Codable, Hashable {
+    var value: Int64
+
+    init(_ value: Int64) {
+        self.value = value
+    }
+
+    init(from decoder: Decoder) throws {
+        let container = try decoder.singleValueContainer()
+        let string = try container.decode(String.self)
+        guard let value = Int64(string) else {
+            throw DecodingError.dataCorruptedError(in: container, debugDescription: "Invalid Int64 \(string)")
+        }
+        self.value = value
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.singleValueContainer()
+        try container.encode(String(value))
+    }
+}
@@ -0,0 +1,33 @@
+import Foundation
+
+enum Status: String, Codable {
+    case active = "Active"
+    case done
+}
+
+enum Priority: Int, Codable {
+    case low = 1
+    case high = 10
+}
+
+struct Address: Codable {
+    var street: String
+    var city: String
+}
+
+struct UserId: Codable, Hashable {
+    var value: String
+
+    init(_ value: String) {
+        self.value = value
+    }
+
+    init(from decoder: Decoder) throws {
+        self.value = try decoder.singleValueContainer().decode(String.self)
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.singleValueContainer()
+        try container.encode(value)
+    }
+}
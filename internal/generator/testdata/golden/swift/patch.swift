@@ -0,0 +1,63 @@
+import Foundation
+
+struct Patch: Codable {
+    var name: String?
+    var bio: String??
+    var count: Int??
+    var balance: Int64String??
+
+    enum CodingKeys: String, CodingKey {
+        case name
+        case bio
+        case count
+        case balance
+    }
+}
+
+extension Patch {
+    init(from decoder: Decoder) throws {
+        let container = try decoder.container(keyedBy: CodingKeys.self)
+        self.name = try container.decodeIfPresent(String.self, forKey: .name)
+        self.bio = try container.contains(.bio) ? .some(container.decode(String?.self, forKey: .bio)) : nil
+        self.count = try container.contains(.count) ? .some(container.decode(Int?.self, forKey: .count)) : nil
+        self.balance = try container.contains(.balance) ? .some(container.decode(Int64String?.self, forKey: .balance)) : nil
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.container(keyedBy: CodingKeys.self)
+        try container.encodeIfPresent(self.name, forKey: .name)
+        if let value = self.bio {
+            try container.encode(value, forKey: .bio)
+        }
+        if let value = self.count {
+            try container.encode(value, forKey: .count)
+        }
+        if let value = self.balance {
+            try container.encode(value, forKey: .balance)
+        }
+    }
+}
+
+/// An Int64 which is a string in JSON, as JavaScript numbers cannot hold every
+/// Int64.
+struct Int64String: Codable, Hashable {
+    var value: Int64
+
+    init(_ value: Int64) {
+        self.value = value
+    }
+
+    init(from decoder: Decoder) throws {
+        let container = try decoder.singleValueContainer()
+        let string = try container.decode(String.self)
+        guard let value = Int64(string) else {
+            throw DecodingError.dataCorruptedError(in: container, debugDescription: "Invalid Int64 \(string)")
+        }
+        self.value = value
+    }
+
+    func encode(to encoder: Encoder) throws {
+        var container = encoder.singleValueContainer()
+        try container.encode(String(value))
+    }
+}
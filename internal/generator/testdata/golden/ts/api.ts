@@ -0,0 +1,9 @@
+import type { UserId, Status, Address, Priority } from "./common"; 
+/**
+ * A user of the API
+ */
+export interface User { id: UserId; name: string; nickname?: string; bio: string | null; balance: string; ids: string[]; status: Status; address?: Address; }; export interface Settings { retries: number; status: Status; priority: Priority; tags: string[]; labels: Record<string, string>; }; 
+/**
+ * Creates a Settings, using the defaults for the fields which are not given.
+ */
+export function defaultSettings(init: Omit<Settings, "retries" | "status" | "priority" | "tags" | "labels"> & Partial<Pick<Settings, "retries" | "status" | "priority" | "tags" | "labels">>): Settings { return { "retries": 3, "status": "Active", "priority": 10 as Priority, "tags": [], "labels": {}, ...init }; }; export interface Page<T> { items: T[]; next?: string; }; export type External = | {user: User;}| {count: number;}; export type Internal = | ({ "kind": "user" } & User)| ({ "kind": "settings" } & Settings); export type Adjacent = | { "kind": "user"; "data": User }| { "kind": "count"; "data": number }; export type Untagged = | User| string; export type Outcome<T> = | {ok: T;}| {err: string;}; export interface Response { users: Page<User>; outcome: Outcome<number>; }; 
\ No newline at end of file
@@ -0,0 +1 @@
+export type Status = | "Active" | "done" ; export const Priority = { Low: 1, High: 10, } as const; export type Priority = (typeof Priority)[keyof typeof Priority]; export interface Address { street: string; city: string; }; export type UserId = string & { __brand: "UserId" }; 
\ No newline at end of file
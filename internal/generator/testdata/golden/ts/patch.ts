@@ -0,0 +1 @@
+export interface Patch { name?: string; bio?: string | null; count?: number | null; balance?: string | null; }; 
\ No newline at end of file
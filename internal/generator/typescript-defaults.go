@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printTsDefaultFn prints a defaultX function which fills in the defaults of the
+// fields of a product which are not given, or nothing when none of its fields
+// have defaults.
+func printTsDefaultFn(p ast.Product) string {
+	var keys []string
+	var valuesString string
+	for _, f := range p.AllFields() {
+		if f.Default == nil {
+			continue
+		}
+		key := printTsString(fieldJsonName(f))
+		keys = append(keys, key)
+		valuesString += fmt.Sprintf(`%s: %s, `, key, printTsDefault(f))
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+
+	typeParams := printTsTypeParams(p.TypeParams)
+	productType := p.Id + typeParams
+	keysString := strings.Join(keys, " | ")
+	initType := fmt.Sprintf(`Omit<%s, %s> & Partial<Pick<%s, %s>>`, productType, keysString, productType, keysString)
+	docString := printTsDoc([]string{fmt.Sprintf(`Creates a %s, using the defaults for the fields which are not given.`, p.Id)}, nil)
+	return docString + fmt.Sprintf(`export function default%s%s(init: %s): %s { return { %s...init }; }; `, p.Id, typeParams, initType, productType, valuesString)
+}
+
+func printTsDefault(f ast.Field) string {
+	d := *f.Default
+	var value string
+	switch d.Kind {
+	case ast.DefaultString, ast.DefaultSumStr:
+		value = printTsString(d.Value)
+	case ast.DefaultNumber, ast.DefaultBool:
+		value = d.Value
+	case ast.DefaultSumInt:
+		// The const object of the sumint can be imported as a type only, so the
+		// value is cast to the type of the field instead of naming the variant
+		return fmt.Sprintf(`%s as %s`, d.Value, printTsTypeTail(f.Type, true))
+	case ast.DefaultEmptyList:
+		value = "[]"
+	case ast.DefaultEmptyMap:
+		value = "{}"
+	default:
+		panic("Invalid default")
+	}
+	// Newtypes are branded, so values have to be cast to them
+	if ti := f.Type.TypeIdent; ti != nil && ti.Id != d.Enum {
+		if _, ok := TS_PRIMITIVES[ti.Id]; !ok {
+			value = fmt.Sprintf(`%s as %s`, value, printTsTypeTail(f.Type, true))
+		}
+	}
+	return value
+}
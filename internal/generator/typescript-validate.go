@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/brahms116/between/internal/ast"
+)
+
+// printTsValidate prints a validateX function which checks the constraints on
+// the fields of a product, including the ones it inherits, or nothing when none
+// of its fields have constraints.
+func printTsValidate(p ast.Product) string {
+	var checksString string
+	for _, f := range p.AllFields() {
+		for _, c := range f.Constraints {
+			checksString += printTsConstraintCheck(f, c)
+		}
+	}
+	if checksString == "" {
+		return ""
+	}
+
+	typeParams := printTsTypeParams(p.TypeParams)
+	docString := printTsDoc([]string{fmt.Sprintf(`Checks the constraints on the fields of %s, returning every violation.`, p.Id)}, nil)
+	return docString + fmt.Sprintf(`export function validate%s%s(x: %s%s): string[] { const errors: string[] = []; %sreturn errors; }; `, p.Id, typeParams, p.Id, typeParams, checksString)
+}
+
+func printTsConstraintCheck(f ast.Field, c ast.Constraint) string {
+	value := "x." + f.Id
+	if f.JsonName != nil {
+		value = fmt.Sprintf(`x[%s]`, printTsString(*f.JsonName))
+	}
+	var guard string
+	if f.Type.IsOptional() || f.Type.IsNullable() {
+		guard = value + " != null && "
+	}
+
+	var condition string
+	var message string
+	switch c.Name {
+	case ast.ConstraintMin:
+		condition = fmt.Sprintf(`%s < %s`, value, c.Value)
+		message = fmt.Sprintf(`%s must be at least %s`, fieldJsonName(f), c.Value)
+	case ast.ConstraintMax:
+		condition = fmt.Sprintf(`%s > %s`, value, c.Value)
+		message = fmt.Sprintf(`%s must be at most %s`, fieldJsonName(f), c.Value)
+	case ast.ConstraintMinLength:
+		// Spreading counts code points rather than UTF-16 code units
+		condition = fmt.Sprintf(`[...%s].length < %s`, value, c.Value)
+		message = fmt.Sprintf(`%s must be at least %s characters long`, fieldJsonName(f), c.Value)
+	case ast.ConstraintMaxLength:
+		condition = fmt.Sprintf(`[...%s].length > %s`, value, c.Value)
+		message = fmt.Sprintf(`%s must be at most %s characters long`, fieldJsonName(f), c.Value)
+	case ast.ConstraintPattern:
+		condition = fmt.Sprintf(`!new RegExp(%s).test(%s)`, printTsString(c.Value), value)
+		message = fmt.Sprintf(`%s must match the pattern %s`, fieldJsonName(f), c.Value)
+	case ast.ConstraintMinItems:
+		condition = fmt.Sprintf(`%s.length < %s`, value, c.Value)
+		message = fmt.Sprintf(`%s must have at least %s items`, fieldJsonName(f), c.Value)
+	case ast.ConstraintMaxItems:
+		condition = fmt.Sprintf(`%s.length > %s`, value, c.Value)
+		message = fmt.Sprintf(`%s must have at most %s items`, fieldJsonName(f), c.Value)
+	default:
+		panic("Invalid constraint")
+	}
+	return fmt.Sprintf(`if (%s%s) { errors.push(%s); } `, guard, condition, printTsString(message))
+}
+
+// printTsString prints s as a TS string literal.
+func printTsString(s string) string {
+	b, err := json.Marshal(s)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
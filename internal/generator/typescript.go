@@ -2,18 +2,30 @@ package generator
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/brahms116/between/internal/ast"
 )
 
+// TS_PRIMITIVES maps primitives to TypeScript types. Int64s are strings as
+// numbers cannot hold all of them, and bytes are base64 strings.
 var TS_PRIMITIVES map[string]string = map[string]string{
-	"Float":  "number",
-	"Str":    "string",
-	"Bool":   "boolean",
-	"Int":    "number",
-	"Any":    "unknown",
-	"Object": "Record<string, unknown>",
-	"Date":   "string",
+	"Float":    "number",
+	"Float64":  "number",
+	"Str":      "string",
+	"Bool":     "boolean",
+	"Int":      "number",
+	"Int32":    "number",
+	"Int64":    "string",
+	"Any":      "unknown",
+	"Object":   "Record<string, unknown>",
+	"Decimal":  "string",
+	"UUID":     "string",
+	"Bytes":    "string",
+	"Date":     "string",
+	"DateTime": "string",
+	"Duration": "string",
 }
 
 func PrintTsDefinitions(ds []ast.Definition) string {
@@ -24,10 +36,46 @@ func PrintTsDefinitions(ds []ast.Definition) string {
 	return definitionString
 }
 
+// PrintTsFile prints the definitions of a single source file, importing the
+// types it uses from other source files from their sibling modules.
+func PrintTsFile(f ast.File) string {
+	var importsString string
+	for _, imp := range f.Imports {
+		importsString += fmt.Sprintf(`import type { %s } from %s; `, strings.Join(imp.Ids, ", "), printTsString(tsModuleSpecifier(f.Path, imp.Path)))
+	}
+	return importsString + PrintTsDefinitions(f.Definitions)
+}
+
+// tsModuleSpecifier returns the relative module specifier from the module
+// generated for the source file at from, to the one generated for to.
+func tsModuleSpecifier(from string, to string) string {
+	rel, err := filepath.Rel(filepath.Dir(from), to)
+	if err != nil {
+		rel = to
+	}
+	rel = filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+	if !strings.HasPrefix(rel, "../") {
+		rel = "./" + rel
+	}
+	return rel
+}
+
 func printTsDefinition(d ast.Definition) string {
 	if d.SumStr != nil {
 		return printTsSumStr(*d.SumStr)
 	}
+	if d.SumInt != nil {
+		return printTsSumInt(*d.SumInt)
+	}
+	if d.Alias != nil {
+		return printTsDoc(d.Alias.Doc, d.Alias.Attributes) + fmt.Sprintf(`export type %s = %s; `, d.Alias.Id, printTsTypeTail(d.Alias.Type, true))
+	}
+	if d.NewType != nil {
+		return printTsNewType(*d.NewType)
+	}
+	if d.Extern != nil {
+		return printTsDoc(d.Extern.Doc, d.Extern.Attributes) + fmt.Sprintf(`export type %s = %s; `, d.Extern.Id, d.Extern.Targets["ts"])
+	}
 	if d.Sum != nil {
 		return printTsSum(*d.Sum)
 	}
@@ -44,17 +92,51 @@ func printTsSumStr(s ast.SumStr) string {
 		if variant.JsonName != nil {
 			name = *variant.JsonName
 		}
-		variantsString += fmt.Sprintf(`| "%s" `, name)
+		variantsString += fmt.Sprintf(`| %s%s `, printTsDoc(variant.Doc, variant.Attributes), printTsString(name))
 	}
-	return fmt.Sprintf(`export type %s = %s; `, s.Id, variantsString)
+	return printTsDoc(s.Doc, s.Attributes) + fmt.Sprintf(`export type %s = %s; `, s.Id, variantsString)
+}
+
+// printTsSumInt prints a const object naming the values, along with the union
+// of the values as a type of the same name.
+func printTsSumInt(s ast.SumInt) string {
+	var variantsString string
+	for _, variant := range s.Variants {
+		variantsString += printTsDoc(variant.Doc, variant.Attributes) + fmt.Sprintf(`%s: %d, `, variant.Id, variant.Value)
+	}
+	constDec := printTsDoc(s.Doc, s.Attributes) + fmt.Sprintf(`export const %s = { %s} as const; `, s.Id, variantsString)
+	return constDec + fmt.Sprintf(`export type %s = (typeof %s)[keyof typeof %s]; `, s.Id, s.Id, s.Id)
+}
+
+// printTsNewType brands the type with the name of the newtype, so values of
+// other types with the same representation cannot be used in its place.
+func printTsNewType(n ast.NewType) string {
+	return printTsDoc(n.Doc, n.Attributes) + fmt.Sprintf(`export type %s = %s & { __brand: "%s" }; `, n.Id, printTsTypeTail(n.Type, false), n.Id)
 }
 
 func printTsSum(s ast.Sum) string {
 	var variantsString string
 	for _, variant := range s.Variants {
-		variantsString += fmt.Sprintf(`| {%s}`, printTsField(variant))
+		variantsString += fmt.Sprintf(`| %s`, printTsSumVariant(s, variant))
+	}
+	return printTsDoc(s.Doc, s.Attributes) + fmt.Sprintf(`export type %s%s = %s; `, s.Id, printTsTypeParams(s.TypeParams), variantsString)
+}
+
+// printTsSumVariant prints a variant in the shape of the encoding of the sum,
+// tagged variants are discriminated by the literal type of their tag.
+func printTsSumVariant(s ast.Sum, variant ast.Field) string {
+	_, typeString := printTsType(variant.Type)
+	tagString := fmt.Sprintf(`%s: %s`, printTsString(s.Tag), printTsString(fieldJsonName(variant)))
+	switch s.Encoding {
+	case ast.SumEncodingInternal:
+		return fmt.Sprintf(`({ %s } & %s)`, tagString, typeString)
+	case ast.SumEncodingAdjacent:
+		return fmt.Sprintf(`{ %s; %s: %s }`, tagString, printTsString(s.Content), typeString)
+	case ast.SumEncodingUntagged:
+		return typeString
+	default:
+		return fmt.Sprintf(`{%s}`, printTsField(variant))
 	}
-	return fmt.Sprintf(`export type %s = %s; `, s.Id, variantsString)
 }
 
 func printTsProduct(p ast.Product) string {
@@ -62,41 +144,98 @@ func printTsProduct(p ast.Product) string {
 	for _, field := range p.Fields {
 		fieldsString += printTsField(field) + " "
 	}
-	return fmt.Sprintf(`export interface %s { %s}; `, p.Id, fieldsString)
+	var extendsString string
+	if p.Extends != nil {
+		extendsString = " extends " + printTsTypeTail(ast.Type{TypeIdent: p.Extends}, true)
+	}
+	return printTsDoc(p.Doc, p.Attributes) + fmt.Sprintf(`export interface %s%s%s { %s}; `, p.Id, printTsTypeParams(p.TypeParams), extendsString, fieldsString) + printTsDefaultFn(p) + printTsValidate(p)
+}
+
+func printTsTypeParams(params []string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`<%s>`, strings.Join(params, ", "))
 }
 
 func printTsField(f ast.Field) string {
-	nullable, typeString := printTsType(f.Type)
-	var nullableString string
-	if nullable {
-		nullableString = "?"
+	optional, typeString := printTsType(f.Type)
+	var optionalString string
+	if optional {
+		optionalString = "?"
+	}
+	if f.Type.IsNullable() {
+		typeString += " | null"
 	}
 
 	fieldId := f.Id
 	if f.JsonName != nil {
-		fieldId = fmt.Sprintf(`"%s"`, *f.JsonName)
+		fieldId = printTsString(*f.JsonName)
 	}
 
-	return fmt.Sprintf(`%s%s: %s;`, fieldId, nullableString, typeString)
+	return printTsDoc(f.Doc, f.Attributes) + fmt.Sprintf(`%s%s: %s;`, fieldId, optionalString, typeString)
 }
 
 func printTsType(t ast.Type) (bool, string) {
-	return t.IsNullable(), printTsTypeTail(t, true)
+	return t.IsOptional(), printTsTypeTail(t, true)
 }
 
 func printTsTypeTail(t ast.Type, isTopLevel bool) string {
 	if t.List != nil {
-		if t.List.Nullable && !isTopLevel {
+		if t.List.Optional && !isTopLevel {
 			return fmt.Sprintf(`(%s[]|undefined)`, printTsTypeTail(t.List.Type, false))
 		}
 		return fmt.Sprintf(`%s[]`, printTsTypeTail(t.List.Type, false))
 	}
+	if t.Map != nil {
+		mapString := fmt.Sprintf(`Record<%s, %s>`, printTsTypeTail(t.Map.Key, false), printTsTypeTail(t.Map.Value, false))
+		if _, ok := TS_PRIMITIVES[t.Map.Key.TypeIdent.Id]; !ok {
+			// Keys are a sumstr, not every variant has to be present
+			mapString = fmt.Sprintf(`Partial<%s>`, mapString)
+		}
+		if t.Map.Optional && !isTopLevel {
+			return fmt.Sprintf(`(%s|undefined)`, mapString)
+		}
+		return mapString
+	}
 	typeString, ok := TS_PRIMITIVES[t.TypeIdent.Id]
 	if !ok {
 		typeString = t.TypeIdent.Id
 	}
-	if t.TypeIdent.Nullable && !isTopLevel {
+	if len(t.TypeIdent.TypeArgs) > 0 {
+		var args []string
+		for _, arg := range t.TypeIdent.TypeArgs {
+			_, argString := printTsType(arg)
+			if arg.IsOptional() {
+				argString += "|undefined"
+			}
+			args = append(args, argString)
+		}
+		typeString += fmt.Sprintf(`<%s>`, strings.Join(args, ", "))
+	}
+	if t.TypeIdent.Optional && !isTopLevel {
 		return fmt.Sprintf(`(%s|undefined)`, typeString)
 	}
 	return typeString
 }
+
+// printTsDoc prints doc lines as a JSDoc block comment, with a @deprecated tag
+// for @deprecated.
+func printTsDoc(doc []string, attributes ast.Attributes) string {
+	if deprecated, ok := attributes.Get("deprecated"); ok {
+		tag := "@deprecated"
+		if reason, ok := deprecated.Arg("", 0); ok {
+			tag += " " + reason
+		}
+		doc = append(doc[:len(doc):len(doc)], tag)
+	}
+	if len(doc) == 0 {
+		return ""
+	}
+	docString := "\n/**\n"
+	for _, line := range doc {
+		line = strings.ReplaceAll(line, "*/", "*\\/")
+		docString += strings.TrimRight(fmt.Sprintf(" * %s", line), " ") + "\n"
+	}
+	return docString + " */\n"
+}
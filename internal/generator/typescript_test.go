@@ -0,0 +1,12 @@
+package generator
+
+import "testing"
+
+func TestTsGolden(t *testing.T) {
+	for _, entry := range []string{"testdata/golden/api.bt", "testdata/golden/patch.bt"} {
+		files, _ := translateFile(t, entry, "ts")
+		for _, f := range files {
+			assertGolden(t, f.Path, "ts", PrintTsFile(f))
+		}
+	}
+}
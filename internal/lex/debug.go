@@ -3,20 +3,38 @@ package lex
 import "fmt"
 
 var TokenTypeDisplay map[TokenType]string = map[TokenType]string{
-	TOKEN_PRODUCT:   "TOKEN_PRODUCT",
-	TOKEN_SUM:       "TOKEN_SUM",
-	TOKEN_SUM_STR:   "TOKEN_SUM_STR",
-	TOKEN_ID:        "TOKEN_ID",
-	TOKEN_LITERAL:   "TOKEN_LITERAL",
-	TOKEN_LBRACE:    "TOKEN_LBRACE",
-	TOKEN_RBRACE:    "TOKEN_RBRACE",
-	TOKEN_LIST:      "TOKEN_LIST",
-	TOKEN_SEPARATOR: "TOKEN_SEPARATOR",
-	TOKEN_OPTIONAL:  "TOKEN_OPTIONAL",
+	TOKEN_PRODUCT:     "TOKEN_PRODUCT",
+	TOKEN_SUM:         "TOKEN_SUM",
+	TOKEN_SUM_STR:     "TOKEN_SUM_STR",
+	TOKEN_SUM_INT:     "TOKEN_SUM_INT",
+	TOKEN_ALIAS:       "TOKEN_ALIAS",
+	TOKEN_NEWTYPE:     "TOKEN_NEWTYPE",
+	TOKEN_IMPORT:      "TOKEN_IMPORT",
+	TOKEN_EXTENDS:     "TOKEN_EXTENDS",
+	TOKEN_EXTERN:      "TOKEN_EXTERN",
+	TOKEN_NULL:        "TOKEN_NULL",
+	TOKEN_ID:          "TOKEN_ID",
+	TOKEN_LITERAL:     "TOKEN_LITERAL",
+	TOKEN_NUMBER:      "TOKEN_NUMBER",
+	TOKEN_BOOL:        "TOKEN_BOOL",
+	TOKEN_LBRACE:      "TOKEN_LBRACE",
+	TOKEN_RBRACE:      "TOKEN_RBRACE",
+	TOKEN_LIST:        "TOKEN_LIST",
+	TOKEN_SEPARATOR:   "TOKEN_SEPARATOR",
+	TOKEN_OPTIONAL:    "TOKEN_OPTIONAL",
+	TOKEN_DOC_COMMENT: "TOKEN_DOC_COMMENT",
+	TOKEN_LANGLE:      "TOKEN_LANGLE",
+	TOKEN_RANGLE:      "TOKEN_RANGLE",
+	TOKEN_COLON:       "TOKEN_COLON",
+	TOKEN_AT:          "TOKEN_AT",
+	TOKEN_LPAREN:      "TOKEN_LPAREN",
+	TOKEN_RPAREN:      "TOKEN_RPAREN",
+	TOKEN_EQUALS:      "TOKEN_EQUALS",
+	TOKEN_PIPE:        "TOKEN_PIPE",
 }
 
 func (t TokenType) String() string {
-  return TokenTypeDisplay[t]
+	return TokenTypeDisplay[t]
 }
 
 func (t Token) String() string {
@@ -2,6 +2,9 @@ package lex
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
 	"unicode/utf8"
 )
 
@@ -11,13 +14,31 @@ const (
 	TOKEN_PRODUCT TokenType = iota
 	TOKEN_SUM
 	TOKEN_SUM_STR
+	TOKEN_SUM_INT
+	TOKEN_ALIAS
+	TOKEN_NEWTYPE
+	TOKEN_IMPORT
+	TOKEN_EXTENDS
+	TOKEN_EXTERN
+	TOKEN_NULL
 	TOKEN_ID
 	TOKEN_LITERAL
+	TOKEN_NUMBER
+	TOKEN_BOOL
 	TOKEN_LBRACE
 	TOKEN_RBRACE
 	TOKEN_LIST
 	TOKEN_SEPARATOR
 	TOKEN_OPTIONAL
+	TOKEN_DOC_COMMENT
+	TOKEN_LANGLE
+	TOKEN_RANGLE
+	TOKEN_COLON
+	TOKEN_AT
+	TOKEN_LPAREN
+	TOKEN_RPAREN
+	TOKEN_EQUALS
+	TOKEN_PIPE
 	TOKEN_EOF
 )
 
@@ -49,9 +70,16 @@ func newUnexpectedCharError(expected *string, actual string, point Point) Unexpe
 }
 
 var stringToToken map[string]TokenType = map[string]TokenType{
-	"prod":   TOKEN_PRODUCT,
-	"sum":    TOKEN_SUM,
-	"sumstr": TOKEN_SUM_STR,
+	"prod":    TOKEN_PRODUCT,
+	"sum":     TOKEN_SUM,
+	"sumstr":  TOKEN_SUM_STR,
+	"sumint":  TOKEN_SUM_INT,
+	"alias":   TOKEN_ALIAS,
+	"newtype": TOKEN_NEWTYPE,
+	"import":  TOKEN_IMPORT,
+	"extends": TOKEN_EXTENDS,
+	"extern":  TOKEN_EXTERN,
+	"null":    TOKEN_NULL,
 }
 
 type Location struct {
@@ -129,6 +157,30 @@ func (l *lexer) Lex() {
 		case '?':
 			l.acceptToken(TOKEN_OPTIONAL)
 			continue
+		case '<':
+			l.acceptToken(TOKEN_LANGLE)
+			continue
+		case '>':
+			l.acceptToken(TOKEN_RANGLE)
+			continue
+		case ':':
+			l.acceptToken(TOKEN_COLON)
+			continue
+		case '@':
+			l.acceptToken(TOKEN_AT)
+			continue
+		case '(':
+			l.acceptToken(TOKEN_LPAREN)
+			continue
+		case ')':
+			l.acceptToken(TOKEN_RPAREN)
+			continue
+		case '=':
+			l.acceptToken(TOKEN_EQUALS)
+			continue
+		case '|':
+			l.acceptToken(TOKEN_PIPE)
+			continue
 		case '[':
 			{
 				currChar = l.next()
@@ -148,6 +200,9 @@ func (l *lexer) Lex() {
 		case '"':
 			l.lexLiteral()
 			continue
+		case '/':
+			l.lexComment()
+			continue
 		default:
 		}
 
@@ -155,23 +210,200 @@ func (l *lexer) Lex() {
 			l.lexAlphaNum()
 			continue
 		}
+		if isNum(*currChar) || *currChar == '-' {
+			l.lexNumber()
+			continue
+		}
 		l.err(newUnexpectedCharError(nil, string(*currChar), l.currPt))
 	}
 
 	l.acceptToken(TOKEN_EOF)
 }
 
+// lexLiteral lexes a string literal starting at the opening '"'. Its value is
+// the string between the quotes with its escapes, the ones of JSON strings,
+// resolved.
 func (l *lexer) lexLiteral() {
-	l.eatWhile(func(b rune) bool {
-		return b != '"'
-	})
+	var value strings.Builder
+	for {
+		next := l.next()
+		if next == nil {
+			expected := "\""
+			l.err(newUnexpectedCharError(&expected, "EOF", l.currPt))
+			break
+		}
+		if *next == '"' {
+			break
+		}
+		if *next != '\\' {
+			value.WriteRune(*next)
+			continue
+		}
+		if r, ok := l.lexEscape(); ok {
+			value.WriteRune(r)
+		}
+	}
+	l.acceptTokenWithValue(TOKEN_LITERAL, value.String())
+}
+
+var escapes = map[rune]rune{
+	'"':  '"',
+	'\\': '\\',
+	'/':  '/',
+	'b':  '\b',
+	'f':  '\f',
+	'n':  '\n',
+	'r':  '\r',
+	't':  '\t',
+}
+
+// lexEscape lexes an escape after its '\\', returning the rune it stands for.
+// Surrogate pairs are given as two \uXXXX escapes, as in JSON.
+func (l *lexer) lexEscape() (rune, bool) {
 	next := l.next()
 	if next == nil {
-		expected := "\""
+		expected := "escape"
 		l.err(newUnexpectedCharError(&expected, "EOF", l.currPt))
+		return 0, false
+	}
+	if r, ok := escapes[*next]; ok {
+		return r, true
+	}
+	if *next != 'u' {
+		expected := `one of " \ / b f n r t u`
+		l.err(newUnexpectedCharError(&expected, string(*next), l.currPt))
+		return 0, false
+	}
+	r, ok := l.lexHex4()
+	if !ok {
+		return 0, false
+	}
+	if !utf16.IsSurrogate(r) {
+		return r, true
+	}
+	if r < 0xdc00 && l.accept('\\') && l.accept('u') {
+		low, ok := l.lexHex4()
+		if !ok {
+			return 0, false
+		}
+		if pair := utf16.DecodeRune(r, low); pair != utf8.RuneError {
+			return pair, true
+		}
+	}
+	expected := "surrogate pair"
+	l.err(newUnexpectedCharError(&expected, fmt.Sprintf("\\u%04x", r), l.currPt))
+	return 0, false
+}
+
+// lexHex4 lexes the four hex digits of a \uXXXX escape.
+func (l *lexer) lexHex4() (rune, bool) {
+	var r rune
+	for i := 0; i < 4; i++ {
+		next := l.next()
+		if next == nil {
+			expected := "hex digit"
+			l.err(newUnexpectedCharError(&expected, "EOF", l.currPt))
+			return 0, false
+		}
+		digit, err := strconv.ParseUint(string(*next), 16, 8)
+		if err != nil {
+			expected := "hex digit"
+			l.err(newUnexpectedCharError(&expected, string(*next), l.currPt))
+			return 0, false
+		}
+		r = r*16 + rune(digit)
+	}
+	return r, true
+}
+
+// accept consumes the next rune if it is r.
+func (l *lexer) accept(r rune) bool {
+	next, _ := utf8.DecodeRuneInString(l.input[l.currPos:])
+	if l.currPos >= len(l.input) || next != r {
+		return false
+	}
+	l.next()
+	return true
+}
+
+// lexComment lexes a comment starting at the first '/'. Comments starting
+// with "///" are doc comments and are kept as tokens so they can be attached to
+// the definition, field or variant that follows them, plain "//" comments are
+// discarded.
+func (l *lexer) lexComment() {
+	next := l.next()
+	if next == nil {
+		expected := "/"
+		l.err(newUnexpectedCharError(&expected, "EOF", l.currPt))
+		return
+	}
+	if *next != '/' {
+		expected := "/"
+		l.err(newUnexpectedCharError(&expected, string(*next), l.currPt))
+		l.updateStart()
+		return
+	}
+
+	isDoc := false
+	next = l.next()
+	if next != nil {
+		if *next == '/' {
+			isDoc = true
+		} else {
+			l.backup()
+		}
+	}
+
+	l.eatWhile(func(r rune) bool {
+		return !isNewLine(r)
+	})
+
+	if !isDoc {
+		l.updateStart()
+		return
 	}
 	str := l.currString()
-	l.acceptTokenWithValue(TOKEN_LITERAL, str[1:len(str)-1])
+	l.acceptTokenWithValue(TOKEN_DOC_COMMENT, str[3:])
+}
+
+// lexNumber lexes an integer or decimal number, with an optional leading minus
+// sign which has already been consumed along with the first rune.
+func (l *lexer) lexNumber() {
+	if l.currString() == "-" {
+		next := l.next()
+		if next == nil {
+			expected := "digit"
+			l.err(newUnexpectedCharError(&expected, "EOF", l.currPt))
+			return
+		}
+		if !isNum(*next) {
+			expected := "digit"
+			l.err(newUnexpectedCharError(&expected, string(*next), l.currPt))
+			l.updateStart()
+			return
+		}
+	}
+	l.eatWhile(isNum)
+
+	next := l.next()
+	if next != nil && *next == '.' {
+		next = l.next()
+		if next == nil || !isNum(*next) {
+			expected := "digit"
+			actual := "EOF"
+			if next != nil {
+				actual = string(*next)
+			}
+			l.err(newUnexpectedCharError(&expected, actual, l.currPt))
+			l.updateStart()
+			return
+		}
+		l.eatWhile(isNum)
+	} else if next != nil {
+		l.backup()
+	}
+
+	l.acceptTokenWithValue(TOKEN_NUMBER, l.currString())
 }
 
 func (l *lexer) lexWhitespace() {
@@ -187,6 +419,10 @@ func (l *lexer) lexAlphaNum() {
 		l.acceptToken(token)
 		return
 	}
+	if str == "true" || str == "false" {
+		l.acceptTokenWithValue(TOKEN_BOOL, str)
+		return
+	}
 	l.acceptTokenWithValue(TOKEN_ID, str)
 }
 
@@ -88,6 +88,42 @@ var cases []testCase = []testCase{
 	},
 }
 
+func TestLexComments(t *testing.T) {
+	result, errs := Lex("// ignored\n/// A user\nprod")
+	assert.Nil(t, errs)
+	assert.Equal(t, []TokenType{TOKEN_DOC_COMMENT, TOKEN_PRODUCT, TOKEN_EOF}, tokenTypes(result))
+	assert.Equal(t, " A user", result[0].Value)
+	assert.Equal(t, Point{Row: 1, Col: 0}, result[0].Loc.Start)
+	assert.Equal(t, Point{Row: 1, Col: 10}, result[0].Loc.End)
+}
+
+func TestLexNumbers(t *testing.T) {
+	result, errs := Lex("404 -1 2.5")
+	assert.Nil(t, errs)
+	assert.Equal(t, []TokenType{TOKEN_NUMBER, TOKEN_NUMBER, TOKEN_NUMBER, TOKEN_EOF}, tokenTypes(result))
+	assert.Equal(t, "404", result[0].Value)
+	assert.Equal(t, "-1", result[1].Value)
+	assert.Equal(t, "2.5", result[2].Value)
+
+	_, errs = Lex("- 1.")
+	assert.Equal(t, 2, len(errs))
+}
+
+func TestLexDefaults(t *testing.T) {
+	result, errs := Lex("retries Int = 3, on Bool = true")
+	assert.Nil(t, errs)
+	assert.Equal(t, []TokenType{TOKEN_ID, TOKEN_ID, TOKEN_EQUALS, TOKEN_NUMBER, TOKEN_SEPARATOR, TOKEN_ID, TOKEN_ID, TOKEN_EQUALS, TOKEN_BOOL, TOKEN_EOF}, tokenTypes(result))
+	assert.Equal(t, "true", result[8].Value)
+}
+
+func tokenTypes(tokens []Token) []TokenType {
+	var types []TokenType
+	for _, token := range tokens {
+		types = append(types, token.Type)
+	}
+	return types
+}
+
 func TestLex(t *testing.T) {
 	for _, testCase := range cases {
 		result, err := Lex(testCase.input)
@@ -109,3 +145,15 @@ func TestSmokeLex(t *testing.T) {
 		log.Print(string(jsonStr))
 	}
 }
+
+func TestLexEscapes(t *testing.T) {
+	result, errs := Lex(`"a\"b\\c\/\n\té😀"`)
+	assert.Nil(t, errs)
+	assert.Equal(t, []TokenType{TOKEN_LITERAL, TOKEN_EOF}, tokenTypes(result))
+	assert.Equal(t, "a\"b\\c/\n\té😀", result[0].Value)
+	assert.Equal(t, 21, result[0].Loc.ByteEnd)
+
+	_, errs = Lex(`"\q" "\u12g4" "\ud83d" "\ude00"`)
+	assert.Equal(t, 4, len(errs))
+	assert.Equal(t, "Unexpected char q, expected one of \" \\ / b f n r t u at (Row 1, Col 4)", errs[0].Error())
+}
@@ -2,6 +2,8 @@ package parser
 
 import (
 	"fmt"
+	"path/filepath"
+
 	"github.com/brahms116/between/internal/lex"
 	"github.com/brahms116/between/internal/st"
 )
@@ -54,6 +56,21 @@ func (e UnexpectedTokenError) LspMessage() string {
 	return fmt.Sprintf("Expected %s got %s", expectedStr, e.Actual.String())
 }
 
+// FileError wraps an error with the path of the file it occurred in, see
+// LexAndParseFiles.
+type FileError struct {
+	Path string
+	Err  error
+}
+
+func (e FileError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err.Error())
+}
+
+func (e FileError) Unwrap() error {
+	return e.Err
+}
+
 func LexAndParse(input string) ([]st.Definition, []error) {
 	tokens, lexErrs := lex.Lex(input)
 	d, parseErrs := Parse(tokens)
@@ -63,6 +80,50 @@ func LexAndParse(input string) ([]st.Definition, []error) {
 	return d, errs
 }
 
+// LexAndParseFiles lexes and parses the file at entry and every file it
+// transitively imports. The definitions are keyed by the cleaned path of each
+// file, imports that cannot be read are left for the translator to report.
+func LexAndParseFiles(entry string, readFile func(string) ([]byte, error)) (map[string][]st.Definition, []error) {
+	files := make(map[string][]st.Definition)
+	var errs []error
+
+	entry = filepath.Clean(entry)
+	input, err := readFile(entry)
+	if err != nil {
+		return files, []error{err}
+	}
+
+	queue := []string{entry}
+	inputs := map[string]string{entry: string(input)}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+
+		definitions, parseErrs := LexAndParse(inputs[path])
+		files[path] = definitions
+		for _, err := range parseErrs {
+			errs = append(errs, FileError{Path: path, Err: err})
+		}
+
+		for _, d := range definitions {
+			if d.Import == nil || d.Import.Path.IsErr {
+				continue
+			}
+			importPath := d.Import.ResolvePath(path)
+			if _, ok := inputs[importPath]; ok {
+				continue
+			}
+			input, err := readFile(importPath)
+			if err != nil {
+				continue
+			}
+			inputs[importPath] = string(input)
+			queue = append(queue, importPath)
+		}
+	}
+	return files, errs
+}
+
 func Parse(input []lex.Token) ([]st.Definition, []error) {
 	p := &parser{input: input}
 	definitions := p.parseDefinitions()
@@ -124,9 +185,15 @@ func (p *parser) parseDefinitions() []st.Definition {
 }
 
 var definitionFirsts = []lex.TokenType{
+	docCommentsFirst,
+	importFirst,
 	productFirst,
 	sumFirst,
-  sumStrFirst,
+	sumStrFirst,
+	sumIntFirst,
+	aliasFirst,
+	newTypeFirst,
+	externFirst,
 }
 
 var definitionFollows = append(definitionFirsts, []lex.TokenType{
@@ -134,28 +201,64 @@ var definitionFollows = append(definitionFirsts, []lex.TokenType{
 }...)
 
 func (p *parser) parseDefinition() st.Definition {
+	doc := p.parseDocComments()
 	switch p.currToken().Type {
+	case lex.TOKEN_IMPORT:
+		imp := p.parseImport()
+		return st.Definition{Import: &imp}
 	case lex.TOKEN_PRODUCT:
 		prod := p.parseProduct()
+		prod.Doc = doc
 		return st.Definition{Product: &prod}
 	case lex.TOKEN_SUM:
 		sum := p.parseSum()
+		sum.Doc = doc
 		return st.Definition{Sum: &sum}
 	case lex.TOKEN_SUM_STR:
 		sumStr := p.parseSumStr()
+		sumStr.Doc = doc
 		return st.Definition{SumStr: &sumStr}
+	case lex.TOKEN_SUM_INT:
+		sumInt := p.parseSumInt()
+		sumInt.Doc = doc
+		return st.Definition{SumInt: &sumInt}
+	case lex.TOKEN_ALIAS:
+		alias := p.parseAlias()
+		alias.Doc = doc
+		return st.Definition{Alias: &alias}
+	case lex.TOKEN_NEWTYPE:
+		newType := p.parseNewType()
+		newType.Doc = doc
+		return st.Definition{NewType: &newType}
+	case lex.TOKEN_EXTERN:
+		extern := p.parseExtern()
+		extern.Doc = doc
+		return st.Definition{Extern: &extern}
 	default:
 		p.errorUntil(definitionFirsts, definitionFollows)
 		return st.Definition{}
 	}
 }
 
+var importFirst = lex.TOKEN_IMPORT
+var importFollows = definitionFollows
+
+func (p *parser) parseImport() st.Import {
+	keyword := p.expect(lex.TOKEN_IMPORT, []lex.TokenType{lex.TOKEN_LITERAL})
+	path := p.expect(lex.TOKEN_LITERAL, importFollows)
+	return st.Import{
+		Keyword: keyword,
+		Path:    path,
+	}
+}
+
 var sumStrFirst = lex.TOKEN_SUM_STR
 var sumStrFollows = definitionFollows
 
 func (p *parser) parseSumStr() st.SumStr {
 	keyword := p.expect(lex.TOKEN_SUM_STR, []lex.TokenType{lex.TOKEN_ID})
-	id := p.expect(lex.TOKEN_ID, []lex.TokenType{lex.TOKEN_LBRACE})
+	id := p.expect(lex.TOKEN_ID, []lex.TokenType{attributesFirst, lex.TOKEN_LBRACE})
+	attributes := p.parseAttributes()
 	lBrace := p.expect(lex.TOKEN_LBRACE, []lex.TokenType{sumStrVariantsFirst, sumStrVariantsFollow})
 	variants := p.parseSumStrVariants()
 	rBrace := p.expect(lex.TOKEN_RBRACE, sumStrFollows)
@@ -163,6 +266,7 @@ func (p *parser) parseSumStr() st.SumStr {
 	return st.SumStr{
 		Keyword:    keyword,
 		Id:         id,
+		Attributes: attributes,
 		LeftBrace:  lBrace,
 		Variants:   variants,
 		RightBrace: rBrace,
@@ -176,25 +280,165 @@ var sumStrVariantFirst = lex.TOKEN_ID
 var sumStrVariantFollows = []lex.TokenType{
 	sumStrVariantsFollow,
 	sumStrVariantFirst,
+	docCommentsFirst,
 }
 
 func (p *parser) parseSumStrVariants() []st.SumStrVariant {
 	variants := []st.SumStrVariant{}
 	for {
 		switch p.currToken().Type {
-		case lex.TOKEN_ID:
-			id := p.expect(lex.TOKEN_ID, []lex.TokenType{lex.TOKEN_SEPARATOR, lex.TOKEN_SEPARATOR})
+		case lex.TOKEN_ID, docCommentsFirst:
+			doc := p.parseDocComments()
+			id := p.expect(lex.TOKEN_ID, []lex.TokenType{lex.TOKEN_LITERAL, attributesFirst, lex.TOKEN_SEPARATOR})
 			jsonName := p.parseJsonRename()
+			attributes := p.parseAttributes()
 			separator := p.expect(lex.TOKEN_SEPARATOR, sumStrVariantFollows)
 			variants = append(variants, st.SumStrVariant{
-				Id:        id,
-				JsonName:  jsonName,
-				Separator: separator,
+				Doc:        doc,
+				Id:         id,
+				JsonName:   jsonName,
+				Attributes: attributes,
+				Separator:  separator,
 			})
 		case sumStrVariantsFollow:
 			return variants
 		default:
-			p.errorUntil([]lex.TokenType{sumStrVariantsFirst, sumStrVariantsFollow}, []lex.TokenType{sumStrVariantsFollow})
+			p.errorUntil([]lex.TokenType{sumStrVariantsFirst, docCommentsFirst, sumStrVariantsFollow}, []lex.TokenType{sumStrVariantsFollow})
+			return variants
+		}
+	}
+}
+
+var aliasFirst = lex.TOKEN_ALIAS
+
+func (p *parser) parseAlias() st.Alias {
+	keyword := p.expect(lex.TOKEN_ALIAS, []lex.TokenType{lex.TOKEN_ID})
+	id := p.expect(lex.TOKEN_ID, typeFirsts)
+	aliasType := p.parseType()
+	attributes := p.parseAttributes()
+	return st.Alias{
+		Keyword:    keyword,
+		Id:         id,
+		Type:       aliasType,
+		Attributes: attributes,
+	}
+}
+
+var newTypeFirst = lex.TOKEN_NEWTYPE
+
+func (p *parser) parseNewType() st.NewType {
+	keyword := p.expect(lex.TOKEN_NEWTYPE, []lex.TokenType{lex.TOKEN_ID})
+	id := p.expect(lex.TOKEN_ID, typeFirsts)
+	newType := p.parseType()
+	attributes := p.parseAttributes()
+	return st.NewType{
+		Keyword:    keyword,
+		Id:         id,
+		Type:       newType,
+		Attributes: attributes,
+	}
+}
+
+var externFirst = lex.TOKEN_EXTERN
+var externFollows = definitionFollows
+
+func (p *parser) parseExtern() st.Extern {
+	keyword := p.expect(lex.TOKEN_EXTERN, []lex.TokenType{lex.TOKEN_ID})
+	id := p.expect(lex.TOKEN_ID, []lex.TokenType{attributesFirst, lex.TOKEN_LBRACE})
+	attributes := p.parseAttributes()
+	lBrace := p.expect(lex.TOKEN_LBRACE, []lex.TokenType{externMappingFirst, lex.TOKEN_RBRACE})
+	mappings := p.parseExternMappings()
+	rBrace := p.expect(lex.TOKEN_RBRACE, externFollows)
+	return st.Extern{
+		Keyword:    keyword,
+		Id:         id,
+		Attributes: attributes,
+		LeftBrace:  lBrace,
+		Mappings:   mappings,
+		RightBrace: rBrace,
+	}
+}
+
+var externMappingFirst = lex.TOKEN_ID
+
+// parseExternMappings parses the mappings of an extern, the separator after
+// the last one is optional.
+func (p *parser) parseExternMappings() []st.ExternMapping {
+	var mappings []st.ExternMapping
+	for p.currToken().Type == externMappingFirst {
+		target := p.expect(lex.TOKEN_ID, []lex.TokenType{lex.TOKEN_LITERAL})
+		mappingType := p.expect(lex.TOKEN_LITERAL, []lex.TokenType{lex.TOKEN_SEPARATOR, lex.TOKEN_RBRACE})
+		mapping := st.ExternMapping{
+			Target: target,
+			Type:   mappingType,
+		}
+		separator, ok := p.optionalNextToken(lex.TOKEN_SEPARATOR)
+		if ok {
+			mapping.Separator = &separator
+		}
+		mappings = append(mappings, mapping)
+		if !ok {
+			break
+		}
+	}
+	return mappings
+}
+
+var sumIntFirst = lex.TOKEN_SUM_INT
+var sumIntFollows = definitionFollows
+
+func (p *parser) parseSumInt() st.SumInt {
+	keyword := p.expect(lex.TOKEN_SUM_INT, []lex.TokenType{lex.TOKEN_ID})
+	id := p.expect(lex.TOKEN_ID, []lex.TokenType{attributesFirst, lex.TOKEN_LBRACE})
+	attributes := p.parseAttributes()
+	lBrace := p.expect(lex.TOKEN_LBRACE, []lex.TokenType{sumIntVariantsFirst, sumIntVariantsFollow})
+	variants := p.parseSumIntVariants()
+	rBrace := p.expect(lex.TOKEN_RBRACE, sumIntFollows)
+
+	return st.SumInt{
+		Keyword:    keyword,
+		Id:         id,
+		Attributes: attributes,
+		LeftBrace:  lBrace,
+		Variants:   variants,
+		RightBrace: rBrace,
+	}
+}
+
+var sumIntVariantsFirst = sumIntVariantFirst
+var sumIntVariantsFollow = lex.TOKEN_RBRACE
+
+var sumIntVariantFirst = lex.TOKEN_ID
+var sumIntVariantFollows = []lex.TokenType{
+	sumIntVariantsFollow,
+	sumIntVariantFirst,
+	docCommentsFirst,
+}
+
+func (p *parser) parseSumIntVariants() []st.SumIntVariant {
+	variants := []st.SumIntVariant{}
+	for {
+		switch p.currToken().Type {
+		case lex.TOKEN_ID, docCommentsFirst:
+			doc := p.parseDocComments()
+			id := p.expect(lex.TOKEN_ID, []lex.TokenType{lex.TOKEN_NUMBER, attributesFirst, lex.TOKEN_SEPARATOR})
+			var value *lex.Token
+			if number, ok := p.optionalNextToken(lex.TOKEN_NUMBER); ok {
+				value = &number
+			}
+			attributes := p.parseAttributes()
+			separator := p.expect(lex.TOKEN_SEPARATOR, sumIntVariantFollows)
+			variants = append(variants, st.SumIntVariant{
+				Doc:        doc,
+				Id:         id,
+				Value:      value,
+				Attributes: attributes,
+				Separator:  separator,
+			})
+		case sumIntVariantsFollow:
+			return variants
+		default:
+			p.errorUntil([]lex.TokenType{sumIntVariantsFirst, docCommentsFirst, sumIntVariantsFollow}, []lex.TokenType{sumIntVariantsFollow})
 			return variants
 		}
 	}
@@ -205,13 +449,17 @@ var sumFollows = definitionFollows
 
 func (p *parser) parseSum() st.Sum {
 	keyword := p.expect(lex.TOKEN_SUM, []lex.TokenType{lex.TOKEN_ID})
-	id := p.expect(lex.TOKEN_ID, []lex.TokenType{lex.TOKEN_LBRACE})
+	id := p.expect(lex.TOKEN_ID, []lex.TokenType{typeParamsFirst, attributesFirst, lex.TOKEN_LBRACE})
+	typeParams := p.parseTypeParams()
+	attributes := p.parseAttributes()
 	lBrace := p.expect(lex.TOKEN_LBRACE, []lex.TokenType{fieldsFirst, lex.TOKEN_RBRACE})
 	fields := p.parseFields()
 	rBrace := p.expect(lex.TOKEN_RBRACE, sumFollows)
 	return st.Sum{
 		Keyword:    keyword,
 		Id:         id,
+		TypeParams: typeParams,
+		Attributes: attributes,
 		LeftBrace:  lBrace,
 		Variants:   fields,
 		RightBrace: rBrace,
@@ -223,7 +471,10 @@ var productFollows = definitionFollows
 
 func (p *parser) parseProduct() st.Product {
 	keyword := p.expect(lex.TOKEN_PRODUCT, []lex.TokenType{lex.TOKEN_ID})
-	id := p.expect(lex.TOKEN_ID, []lex.TokenType{lex.TOKEN_LBRACE})
+	id := p.expect(lex.TOKEN_ID, []lex.TokenType{typeParamsFirst, extendsFirst, attributesFirst, lex.TOKEN_LBRACE})
+	typeParams := p.parseTypeParams()
+	extends := p.parseExtends()
+	attributes := p.parseAttributes()
 	lBrace := p.expect(lex.TOKEN_LBRACE, []lex.TokenType{lex.TOKEN_ID})
 	fields := p.parseFields()
 	rBrace := p.expect(lex.TOKEN_RBRACE, definitionFollows)
@@ -231,11 +482,50 @@ func (p *parser) parseProduct() st.Product {
 		Keyword:    keyword,
 		LeftBrace:  lBrace,
 		Id:         id,
+		TypeParams: typeParams,
+		Extends:    extends,
+		Attributes: attributes,
 		Fields:     fields,
 		RightBrace: rBrace,
 	}
 }
 
+var extendsFirst = lex.TOKEN_EXTENDS
+
+func (p *parser) parseExtends() *st.Extends {
+	keyword, ok := p.optionalNextToken(lex.TOKEN_EXTENDS)
+	if !ok {
+		return nil
+	}
+	return &st.Extends{
+		Keyword: keyword,
+		Type:    p.parseTypeIdent(),
+	}
+}
+
+var typeParamsFirst = lex.TOKEN_LANGLE
+
+func (p *parser) parseTypeParams() *st.TypeParams {
+	lAngle, ok := p.optionalNextToken(lex.TOKEN_LANGLE)
+	if !ok {
+		return nil
+	}
+	var params []lex.Token
+	for {
+		param := p.expect(lex.TOKEN_ID, []lex.TokenType{lex.TOKEN_SEPARATOR, lex.TOKEN_RANGLE, lex.TOKEN_LBRACE})
+		params = append(params, param)
+		if _, ok := p.optionalNextToken(lex.TOKEN_SEPARATOR); !ok {
+			break
+		}
+	}
+	rAngle := p.expect(lex.TOKEN_RANGLE, []lex.TokenType{extendsFirst, lex.TOKEN_LBRACE})
+	return &st.TypeParams{
+		LeftAngle:  lAngle,
+		Params:     params,
+		RightAngle: rAngle,
+	}
+}
+
 var fieldsFirst = fieldFirst
 var fieldsFollow = lex.TOKEN_RBRACE
 
@@ -243,12 +533,12 @@ func (p *parser) parseFields() []st.Field {
 	fields := []st.Field{}
 	for {
 		switch p.currToken().Type {
-		case fieldsFirst:
+		case fieldsFirst, docCommentsFirst:
 			fields = append(fields, p.parseField())
 		case fieldsFollow:
 			return fields
 		default:
-			p.errorUntil([]lex.TokenType{fieldsFirst, fieldsFollow}, []lex.TokenType{fieldsFollow})
+			p.errorUntil([]lex.TokenType{fieldsFirst, docCommentsFirst, fieldsFollow}, []lex.TokenType{fieldsFollow})
 			return fields
 		}
 	}
@@ -258,47 +548,71 @@ var fieldFirst = lex.TOKEN_ID
 var fieldFollows = []lex.TokenType{
 	fieldsFollow,
 	fieldFirst,
+	docCommentsFirst,
 }
 
 func (p *parser) parseField() st.Field {
+	doc := p.parseDocComments()
 	id := p.expect(lex.TOKEN_ID, []lex.TokenType{
 		lex.TOKEN_ID,
 		lex.TOKEN_LIST,
+		lex.TOKEN_LBRACE,
 		lex.TOKEN_LITERAL,
 		lex.TOKEN_OPTIONAL,
+		nullFirst,
+		defaultFirst,
+		attributesFirst,
 		lex.TOKEN_SEPARATOR,
 	})
+	fieldOptional := p.parseOptional()
 
 	currToken := p.currToken()
-	if currToken.Type == lex.TOKEN_ID || currToken.Type == lex.TOKEN_LIST || currToken.Type == lex.TOKEN_LITERAL {
+	if currToken.Type == lex.TOKEN_ID || currToken.Type == lex.TOKEN_LIST || currToken.Type == lex.TOKEN_LBRACE || currToken.Type == lex.TOKEN_LITERAL {
 		jsonName := p.parseJsonRename()
 		fieldType := p.parseType()
+		fieldNull := p.parseNull()
+		fieldDefault := p.parseDefault()
+		attributes := p.parseAttributes()
 		separator := p.expect(lex.TOKEN_SEPARATOR, fieldFollows)
 		return st.Field{
 			FieldFull: &st.FieldFull{
-				Id:        id,
-				JsonName:  jsonName,
-				Type:      fieldType,
-				Separator: separator,
+				Doc:        doc,
+				Id:         id,
+				Optional:   fieldOptional,
+				JsonName:   jsonName,
+				Type:       fieldType,
+				Null:       fieldNull,
+				Default:    fieldDefault,
+				Attributes: attributes,
+				Separator:  separator,
 			},
 		}
 	}
-	if currToken.Type == lex.TOKEN_OPTIONAL || currToken.Type == lex.TOKEN_SEPARATOR {
-		fieldNullable := p.parseNullability()
+	if currToken.Type == nullFirst || currToken.Type == defaultFirst || currToken.Type == attributesFirst || currToken.Type == lex.TOKEN_SEPARATOR {
+		fieldNull := p.parseNull()
+		fieldDefault := p.parseDefault()
+		attributes := p.parseAttributes()
 		separator := p.expect(lex.TOKEN_SEPARATOR, fieldFollows)
 		return st.Field{
 			FieldShort: &st.FieldShort{
-				Id:        id,
-				Nullable:  fieldNullable,
-				Separator: separator,
+				Doc:        doc,
+				Id:         id,
+				Optional:   fieldOptional,
+				Null:       fieldNull,
+				Default:    fieldDefault,
+				Attributes: attributes,
+				Separator:  separator,
 			},
 		}
 	}
 	p.errorUntil([]lex.TokenType{
 		lex.TOKEN_ID,
 		lex.TOKEN_LIST,
+		lex.TOKEN_LBRACE,
 		lex.TOKEN_LITERAL,
-		lex.TOKEN_OPTIONAL,
+		nullFirst,
+		defaultFirst,
+		attributesFirst,
 		lex.TOKEN_SEPARATOR,
 	}, fieldFollows)
 	return st.Field{}
@@ -310,12 +624,37 @@ var typeIdentFollow = typeFollow
 func (p *parser) parseTypeIdent() st.TypeIdent {
 	id := p.expect(lex.TOKEN_ID, []lex.TokenType{
 		typeIdentFollow,
+		typeArgsFirst,
 		lex.TOKEN_OPTIONAL,
 	})
-	nullable := p.parseNullability()
+	typeArgs := p.parseTypeArgs()
+	optional := p.parseOptional()
 	return st.TypeIdent{
 		Id:       id,
-		Nullable: nullable,
+		TypeArgs: typeArgs,
+		Optional: optional,
+	}
+}
+
+var typeArgsFirst = lex.TOKEN_LANGLE
+
+func (p *parser) parseTypeArgs() *st.TypeArgs {
+	lAngle, ok := p.optionalNextToken(lex.TOKEN_LANGLE)
+	if !ok {
+		return nil
+	}
+	var args []st.Type
+	for {
+		args = append(args, p.parseType())
+		if _, ok := p.optionalNextToken(lex.TOKEN_SEPARATOR); !ok {
+			break
+		}
+	}
+	rAngle := p.expect(lex.TOKEN_RANGLE, []lex.TokenType{typeIdentFollow, lex.TOKEN_OPTIONAL})
+	return &st.TypeArgs{
+		LeftAngle:  lAngle,
+		Args:       args,
+		RightAngle: rAngle,
 	}
 }
 
@@ -324,18 +663,39 @@ var listFollow = typeFollow
 
 func (p *parser) parseList() st.List {
 	brackets := p.expect(lex.TOKEN_LIST, append(typeFirsts, lex.TOKEN_OPTIONAL))
-	nullable := p.parseNullability()
+	optional := p.parseOptional()
 	listType := p.parseType()
 	return st.List{
 		Brackets: brackets,
-		Nullable: nullable,
+		Optional: optional,
 		Type:     listType,
 	}
 }
 
+var mapFirst = lex.TOKEN_LBRACE
+var mapFollow = typeFollow
+
+func (p *parser) parseMap() st.Map {
+	lBrace := p.expect(lex.TOKEN_LBRACE, typeFirsts)
+	key := p.parseType()
+	colon := p.expect(lex.TOKEN_COLON, typeFirsts)
+	value := p.parseType()
+	rBrace := p.expect(lex.TOKEN_RBRACE, []lex.TokenType{mapFollow, lex.TOKEN_OPTIONAL})
+	optional := p.parseOptional()
+	return st.Map{
+		LeftBrace:  lBrace,
+		Key:        key,
+		Colon:      colon,
+		Value:      value,
+		RightBrace: rBrace,
+		Optional:   optional,
+	}
+}
+
 var typeFirsts = []lex.TokenType{
 	typeIdentFirst,
 	listFirst,
+	mapFirst,
 }
 var typeFollow = lex.TOKEN_SEPARATOR
 
@@ -351,22 +711,140 @@ func (p *parser) parseType() st.Type {
 		return st.Type{
 			List: &typeList,
 		}
+	case lex.TOKEN_LBRACE:
+		typeMap := p.parseMap()
+		return st.Type{
+			Map: &typeMap,
+		}
 	default:
 		p.errorUntil(typeFirsts, []lex.TokenType{typeFollow})
 		return st.Type{}
 	}
 }
 
-var nullabilityFirst = lex.TOKEN_OPTIONAL
+var optionalFirst = lex.TOKEN_OPTIONAL
 
-func (p *parser) parseNullability() *lex.Token {
-	nullable, ok := p.optionalNextToken(lex.TOKEN_OPTIONAL)
+func (p *parser) parseOptional() *lex.Token {
+	optional, ok := p.optionalNextToken(lex.TOKEN_OPTIONAL)
 	if ok {
-		return &nullable
+		return &optional
 	}
 	return nil
 }
 
+var nullFirst = lex.TOKEN_PIPE
+
+func (p *parser) parseNull() *st.Null {
+	pipe, ok := p.optionalNextToken(lex.TOKEN_PIPE)
+	if !ok {
+		return nil
+	}
+	null := p.expect(lex.TOKEN_NULL, []lex.TokenType{defaultFirst, attributesFirst, lex.TOKEN_SEPARATOR})
+	return &st.Null{
+		Pipe: pipe,
+		Null: null,
+	}
+}
+
+var defaultFirst = lex.TOKEN_EQUALS
+
+var defaultValueFirsts = []lex.TokenType{
+	lex.TOKEN_LITERAL,
+	lex.TOKEN_NUMBER,
+	lex.TOKEN_BOOL,
+	lex.TOKEN_ID,
+	lex.TOKEN_LIST,
+	lex.TOKEN_LBRACE,
+}
+
+func (p *parser) parseDefault() *st.Default {
+	equals, ok := p.optionalNextToken(lex.TOKEN_EQUALS)
+	if !ok {
+		return nil
+	}
+	fieldDefault := st.Default{Equals: equals}
+	currToken := p.currToken()
+	switch currToken.Type {
+	case lex.TOKEN_LITERAL, lex.TOKEN_NUMBER, lex.TOKEN_BOOL, lex.TOKEN_ID, lex.TOKEN_LIST:
+		p.pos++
+		fieldDefault.Value = currToken
+	case lex.TOKEN_LBRACE:
+		p.pos++
+		fieldDefault.Value = currToken
+		rBrace := p.expect(lex.TOKEN_RBRACE, []lex.TokenType{attributesFirst, lex.TOKEN_SEPARATOR})
+		fieldDefault.RightBrace = &rBrace
+	default:
+		p.errorUntil(defaultValueFirsts, []lex.TokenType{attributesFirst, lex.TOKEN_SEPARATOR})
+		fieldDefault.Value = lex.Token{IsErr: true}
+	}
+	return &fieldDefault
+}
+
+var attributesFirst = lex.TOKEN_AT
+
+func (p *parser) parseAttributes() []st.Attribute {
+	var attributes []st.Attribute
+	for p.currToken().Type == attributesFirst {
+		attributes = append(attributes, p.parseAttribute())
+	}
+	return attributes
+}
+
+var attributeArgFirsts = []lex.TokenType{
+	lex.TOKEN_LITERAL,
+	lex.TOKEN_NUMBER,
+}
+
+func (p *parser) parseAttribute() st.Attribute {
+	at := p.expect(lex.TOKEN_AT, []lex.TokenType{lex.TOKEN_ID})
+	name := p.expect(lex.TOKEN_ID, []lex.TokenType{lex.TOKEN_LPAREN, attributesFirst, lex.TOKEN_SEPARATOR})
+	attribute := st.Attribute{
+		At:   at,
+		Name: name,
+	}
+
+	lParen, ok := p.optionalNextToken(lex.TOKEN_LPAREN)
+	if !ok {
+		return attribute
+	}
+	attribute.LeftParen = &lParen
+	for p.currToken().Type != lex.TOKEN_RPAREN {
+		var arg st.AttributeArg
+		if name, ok := p.optionalNextToken(lex.TOKEN_ID); ok {
+			equals := p.expect(lex.TOKEN_EQUALS, attributeArgFirsts)
+			arg.Name = &name
+			arg.Equals = &equals
+		}
+		currToken := p.currToken()
+		if currToken.Type != lex.TOKEN_LITERAL && currToken.Type != lex.TOKEN_NUMBER {
+			p.errorUntil(attributeArgFirsts, []lex.TokenType{lex.TOKEN_RPAREN, lex.TOKEN_SEPARATOR})
+			break
+		}
+		p.pos++
+		arg.Value = currToken
+		attribute.Args = append(attribute.Args, arg)
+		if _, ok := p.optionalNextToken(lex.TOKEN_SEPARATOR); !ok {
+			break
+		}
+	}
+	rParen := p.expect(lex.TOKEN_RPAREN, []lex.TokenType{attributesFirst, lex.TOKEN_SEPARATOR})
+	attribute.RightParen = &rParen
+	return attribute
+}
+
+var docCommentsFirst = lex.TOKEN_DOC_COMMENT
+
+func (p *parser) parseDocComments() []lex.Token {
+	var docs []lex.Token
+	for {
+		doc, ok := p.optionalNextToken(lex.TOKEN_DOC_COMMENT)
+		if !ok {
+			return docs
+		}
+		docs = append(docs, doc)
+	}
+}
+
 var jsonRenameFirst = lex.TOKEN_LITERAL
 
 func (p *parser) parseJsonRename() *lex.Token {
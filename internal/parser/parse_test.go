@@ -18,3 +18,24 @@ func TestParseSmoke(t *testing.T) {
 	bytes, err := json.MarshalIndent(defintions, "", "  ")
 	println(len(bytes))
 }
+
+func TestLexAndParseFiles(t *testing.T) {
+	sources := map[string]string{
+		"api.bt":       "import \"common.bt\"\nprod Order { shipTo Address, }",
+		"common.bt":    "import \"sub/money.bt\"\nprod Address { street Str, }",
+		"sub/money.bt": "import \"../common.bt\"\nprod Money { cents Int, }",
+	}
+	readFile := func(path string) ([]byte, error) {
+		source, ok := sources[path]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(source), nil
+	}
+
+	files, errs := LexAndParseFiles("./api.bt", readFile)
+	assert.Equal(t, 0, len(errs))
+	assert.Equal(t, 3, len(files))
+	assert.Equal(t, "common.bt", files["api.bt"][0].Import.Path.Value)
+	assert.Equal(t, "Money", files["sub/money.bt"][1].Product.Id.Value)
+}
@@ -1,6 +1,10 @@
 package st
 
-import "github.com/brahms116/between/internal/lex"
+import (
+	"path/filepath"
+
+	"github.com/brahms116/between/internal/lex"
+)
 
 func (f Field) Id() lex.Token {
   if f.FieldFull != nil {
@@ -10,3 +14,35 @@ func (f Field) Id() lex.Token {
   }
   panic("unreachable")
 }
+
+func (f Field) Attributes() []Attribute {
+	if f.FieldFull != nil {
+		return f.FieldFull.Attributes
+	} else if f.FieldShort != nil {
+		return f.FieldShort.Attributes
+	}
+	return nil
+}
+
+// ResolvePath resolves the imported path relative to the directory of the file
+// containing the import.
+func (i Import) ResolvePath(from string) string {
+	if filepath.IsAbs(i.Path.Value) {
+		return filepath.Clean(i.Path.Value)
+	}
+	return filepath.Join(filepath.Dir(from), i.Path.Value)
+}
+
+// Loc returns the location of the token a type starts with.
+func (t Type) Loc() lex.Location {
+	if t.List != nil {
+		return t.List.Brackets.Loc
+	}
+	if t.Map != nil {
+		return t.Map.LeftBrace.Loc
+	}
+	if t.TypeIdent != nil {
+		return t.TypeIdent.Id.Loc
+	}
+	return lex.Location{}
+}
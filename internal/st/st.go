@@ -4,70 +4,216 @@ import "github.com/brahms116/between/internal/lex"
 
 type Type struct {
 	List      *List
+	Map       *Map
 	TypeIdent *TypeIdent
 }
 
 type TypeIdent struct {
 	Id       lex.Token
-	Nullable *lex.Token
+	TypeArgs *TypeArgs
+	Optional *lex.Token
+}
+
+type TypeArgs struct {
+	LeftAngle  lex.Token
+	Args       []Type
+	RightAngle lex.Token
+}
+
+type TypeParams struct {
+	LeftAngle  lex.Token
+	Params     []lex.Token
+	RightAngle lex.Token
 }
 
 type List struct {
 	Brackets lex.Token
-	Nullable *lex.Token
+	Optional *lex.Token
 	Type     Type
 }
 
+type Map struct {
+	LeftBrace  lex.Token
+	Key        Type
+	Colon      lex.Token
+	Value      Type
+	RightBrace lex.Token
+	Optional   *lex.Token
+}
+
 type Field struct {
 	FieldFull  *FieldFull
 	FieldShort *FieldShort
 }
 
 type FieldFull struct {
-	Id        lex.Token
-	JsonName  *lex.Token
-	Type      Type
-	Separator lex.Token
+	Doc        []lex.Token
+	Id         lex.Token
+	Optional   *lex.Token
+	JsonName   *lex.Token
+	Type       Type
+	Null       *Null
+	Default    *Default
+	Attributes []Attribute
+	Separator  lex.Token
+}
+
+// Null makes the type of a field nullable, `| null`
+type Null struct {
+	Pipe lex.Token
+	Null lex.Token
+}
+
+// Default is the value a field takes when it is not given, `= value`
+type Default struct {
+	Equals lex.Token
+	// a LITERAL, NUMBER, BOOL or the ID of a variant, or the LIST or LBRACE
+	// starting an empty list or map
+	Value lex.Token
+	// closes an empty map
+	RightBrace *lex.Token
+}
+
+// Attribute is an annotation such as @min(0) or @pattern("^a"), the
+// parentheses are optional when there are no arguments.
+type Attribute struct {
+	At         lex.Token
+	Name       lex.Token
+	LeftParen  *lex.Token
+	Args       []AttributeArg
+	RightParen *lex.Token
+}
+
+// AttributeArg is an argument of an attribute, which is named when written as
+// `name=value`
+type AttributeArg struct {
+	Name   *lex.Token
+	Equals *lex.Token
+	Value  lex.Token
 }
 
 type FieldShort struct {
-	Id        lex.Token
-	Nullable  *lex.Token
-	Separator lex.Token
+	Doc        []lex.Token
+	Id         lex.Token
+	Optional   *lex.Token
+	Null       *Null
+	Default    *Default
+	Attributes []Attribute
+	Separator  lex.Token
 }
 
 type Product struct {
+	Doc        []lex.Token
 	Keyword    lex.Token
 	Id         lex.Token
+	TypeParams *TypeParams
+	Extends    *Extends
+	Attributes []Attribute
 	LeftBrace  lex.Token
 	Fields     []Field
 	RightBrace lex.Token
 }
 
+// Extends names the product whose fields a product also has
+type Extends struct {
+	Keyword lex.Token
+	Type    TypeIdent
+}
+
 type Sum struct {
+	Doc        []lex.Token
 	Keyword    lex.Token
 	Id         lex.Token
+	TypeParams *TypeParams
+	Attributes []Attribute
 	LeftBrace  lex.Token
 	Variants   []Field
 	RightBrace lex.Token
 }
 
 type SumStr struct {
+	Doc        []lex.Token
 	Keyword    lex.Token
 	Id         lex.Token
+	Attributes []Attribute
 	LeftBrace  lex.Token
 	Variants   []SumStrVariant
 	RightBrace lex.Token
 }
 
 type SumStrVariant struct {
-	Id        lex.Token
-	JsonName  *lex.Token
-	Separator lex.Token
+	Doc        []lex.Token
+	Id         lex.Token
+	JsonName   *lex.Token
+	Attributes []Attribute
+	Separator  lex.Token
+}
+
+type SumInt struct {
+	Doc        []lex.Token
+	Keyword    lex.Token
+	Id         lex.Token
+	Attributes []Attribute
+	LeftBrace  lex.Token
+	Variants   []SumIntVariant
+	RightBrace lex.Token
+}
+
+type SumIntVariant struct {
+	Doc        []lex.Token
+	Id         lex.Token
+	Value      *lex.Token
+	Attributes []Attribute
+	Separator  lex.Token
+}
+
+type Alias struct {
+	Doc        []lex.Token
+	Keyword    lex.Token
+	Id         lex.Token
+	Type       Type
+	Attributes []Attribute
+}
+
+type NewType struct {
+	Doc        []lex.Token
+	Keyword    lex.Token
+	Id         lex.Token
+	Type       Type
+	Attributes []Attribute
+}
+
+// Extern is a type defined outside of bt, with the type it is in each target,
+// `extern Money { go "github.com/shopspring/decimal.Decimal", ts "string" }`
+type Extern struct {
+	Doc        []lex.Token
+	Keyword    lex.Token
+	Id         lex.Token
+	Attributes []Attribute
+	LeftBrace  lex.Token
+	Mappings   []ExternMapping
+	RightBrace lex.Token
+}
+
+// ExternMapping is the type of an extern in one target, `go "time.Time"`
+type ExternMapping struct {
+	Target    lex.Token
+	Type      lex.Token
+	Separator *lex.Token
+}
+
+type Import struct {
+	Keyword lex.Token
+	Path    lex.Token
 }
 
 type Definition struct {
+	Import  *Import
 	Product *Product
 	Sum     *Sum
 	SumStr  *SumStr
+	SumInt  *SumInt
+	Alias   *Alias
+	NewType *NewType
+	Extern  *Extern
 }
@@ -0,0 +1,214 @@
+package translate
+
+import (
+	"fmt"
+
+	"github.com/brahms116/between/internal/ast"
+	"github.com/brahms116/between/internal/lex"
+	"github.com/brahms116/between/internal/st"
+)
+
+// attributePlacement is a kind of node attributes can be written on, as flags
+// so an attribute can allow several.
+type attributePlacement int
+
+const (
+	placementProduct attributePlacement = 1 << iota
+	placementSum
+	placementSumStr
+	placementSumInt
+	placementAlias
+	placementNewType
+	placementField
+	placementSumVariant
+	placementSumStrVariant
+	placementSumIntVariant
+	placementExtern
+)
+
+const placementAnywhere = placementProduct | placementSum | placementSumStr | placementSumInt |
+	placementAlias | placementNewType | placementField | placementSumVariant |
+	placementSumStrVariant | placementSumIntVariant | placementExtern
+
+var placementNames = map[attributePlacement]string{
+	placementProduct:       "prods",
+	placementSum:           "sums",
+	placementSumStr:        "sumstrs",
+	placementSumInt:        "sumints",
+	placementAlias:         "aliases",
+	placementNewType:       "newtypes",
+	placementField:         "prod fields",
+	placementSumVariant:    "sum variants",
+	placementSumStrVariant: "sumstr variants",
+	placementSumIntVariant: "sumint variants",
+	placementExtern:        "externs",
+}
+
+// attributeArgKind is the kind of token an argument has to be, worded for
+// error messages.
+type attributeArgKind string
+
+const (
+	attributeArgString attributeArgKind = "a string"
+	attributeArgNumber attributeArgKind = "a number"
+)
+
+type attributeSpec struct {
+	placements attributePlacement
+	// kinds of the positional arguments
+	args []attributeArgKind
+	// number of positional arguments which have to be given
+	required int
+	// kinds of the named arguments, which are optional
+	named map[string]attributeArgKind
+}
+
+var numberConstraintAttribute = attributeSpec{
+	placements: placementField,
+	args:       []attributeArgKind{attributeArgNumber},
+	required:   1,
+}
+
+var attributeSpecs = map[string]attributeSpec{
+	string(ast.ConstraintMin):       numberConstraintAttribute,
+	string(ast.ConstraintMax):       numberConstraintAttribute,
+	string(ast.ConstraintMinLength): numberConstraintAttribute,
+	string(ast.ConstraintMaxLength): numberConstraintAttribute,
+	string(ast.ConstraintMinItems):  numberConstraintAttribute,
+	string(ast.ConstraintMaxItems):  numberConstraintAttribute,
+	string(ast.ConstraintPattern): {
+		placements: placementField,
+		args:       []attributeArgKind{attributeArgString},
+		required:   1,
+	},
+	"tag": {
+		placements: placementSum,
+		args:       []attributeArgKind{attributeArgString},
+		required:   1,
+		named:      map[string]attributeArgKind{"content": attributeArgString},
+	},
+	"untagged": {
+		placements: placementSum,
+	},
+	"deprecated": {
+		placements: placementAnywhere,
+		args:       []attributeArgKind{attributeArgString},
+	},
+}
+
+// translateAttributes checks attributes against the known attributes, leaving
+// out the ones with errors.
+func (t *translate) translateAttributes(attributes []st.Attribute, placement attributePlacement) ast.Attributes {
+	var res ast.Attributes
+	existing := make(map[string]struct{})
+	for _, attribute := range attributes {
+		name := attribute.Name.Value
+		spec, ok := attributeSpecs[name]
+		if !ok {
+			t.addError(fmt.Sprintf("Unknown attribute @%s", name), attribute.Name.Loc)
+			continue
+		}
+		if spec.placements&placement == 0 {
+			t.addError(fmt.Sprintf("@%s cannot be used on %s", name, placementNames[placement]), attribute.Name.Loc)
+			continue
+		}
+		if _, ok := existing[name]; ok {
+			t.addError(fmt.Sprintf("Duplicated attribute @%s", name), attribute.Name.Loc)
+			continue
+		}
+		existing[name] = struct{}{}
+
+		args, ok := t.translateAttributeArgs(attribute, spec)
+		if !ok {
+			continue
+		}
+		res = append(res, ast.Attribute{
+			Name:     name,
+			Args:     args,
+			Location: attribute.Name.Loc,
+		})
+	}
+	return res
+}
+
+func (t *translate) translateAttributeArgs(attribute st.Attribute, spec attributeSpec) ([]ast.AttributeArg, bool) {
+	name := attribute.Name.Value
+	var args []ast.AttributeArg
+	ok := true
+	positional := 0
+	named := make(map[string]struct{})
+	for _, arg := range attribute.Args {
+		var kind attributeArgKind
+		var argName string
+		if arg.Name != nil {
+			argName = arg.Name.Value
+			var known bool
+			kind, known = spec.named[argName]
+			if !known {
+				t.addError(fmt.Sprintf("@%s does not take an argument named %s", name, argName), arg.Name.Loc)
+				ok = false
+				continue
+			}
+			if _, dup := named[argName]; dup {
+				t.addError(fmt.Sprintf("Duplicated argument %s of @%s", argName, name), arg.Name.Loc)
+				ok = false
+				continue
+			}
+			named[argName] = struct{}{}
+		} else {
+			positional++
+			if positional > len(spec.args) {
+				// Reported with the number of arguments below
+				continue
+			}
+			kind = spec.args[positional-1]
+		}
+
+		if !argIsKind(arg.Value, kind) {
+			if argName != "" {
+				t.addError(fmt.Sprintf("The argument %s of @%s must be %s", argName, name, kind), arg.Value.Loc)
+			} else {
+				t.addError(fmt.Sprintf("The argument of @%s must be %s", name, kind), arg.Value.Loc)
+			}
+			ok = false
+			continue
+		}
+		args = append(args, ast.AttributeArg{
+			Name:     argName,
+			Value:    arg.Value.Value,
+			Location: arg.Value.Loc,
+		})
+	}
+
+	if positional < spec.required || positional > len(spec.args) {
+		var msg string
+		switch {
+		case len(spec.args) == 0:
+			msg = fmt.Sprintf("@%s does not take any arguments", name)
+		case spec.required == len(spec.args):
+			msg = fmt.Sprintf("@%s takes exactly %s", name, countArguments(len(spec.args)))
+		default:
+			msg = fmt.Sprintf("@%s takes at most %s", name, countArguments(len(spec.args)))
+		}
+		t.addError(msg, attribute.Name.Loc)
+		ok = false
+	}
+	return args, ok
+}
+
+func argIsKind(token lex.Token, kind attributeArgKind) bool {
+	switch kind {
+	case attributeArgString:
+		return token.Type == lex.TOKEN_LITERAL
+	case attributeArgNumber:
+		return token.Type == lex.TOKEN_NUMBER
+	}
+	panic("unreachable")
+}
+
+func countArguments(n int) string {
+	if n == 1 {
+		return "one argument"
+	}
+	return fmt.Sprintf("%d arguments", n)
+}
@@ -0,0 +1,180 @@
+package translate
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/brahms116/between/internal/ast"
+	"github.com/brahms116/between/internal/st"
+)
+
+// Int64 is a string in TypeScript, so is left out of the integers
+var integerPrimitives = map[string]struct{}{
+	"Int":   {},
+	"Int32": {},
+}
+
+var floatPrimitives = map[string]struct{}{
+	"Float":   {},
+	"Float64": {},
+}
+
+var stringPrimitives = map[string]struct{}{
+	"Str":     {},
+	"Decimal": {},
+	"UUID":    {},
+	"Date":    {},
+}
+
+var boolPrimitives = map[string]struct{}{
+	"Bool": {},
+}
+
+type valueKind int
+
+const (
+	valueKindOther valueKind = iota
+	valueKindInteger
+	valueKindFloat
+	valueKindString
+	valueKindList
+	valueKindBool
+	valueKindMap
+)
+
+type constraintArg int
+
+const (
+	constraintArgNumber constraintArg = iota
+	constraintArgCount
+	constraintArgPattern
+)
+
+type constraintSpec struct {
+	kinds []valueKind
+	// describes the kinds in error messages
+	kindsName string
+	arg       constraintArg
+}
+
+var numberConstraint = constraintSpec{
+	kinds:     []valueKind{valueKindInteger, valueKindFloat},
+	kindsName: "numbers",
+	arg:       constraintArgNumber,
+}
+
+var lengthConstraint = constraintSpec{
+	kinds:     []valueKind{valueKindString},
+	kindsName: "strings",
+	arg:       constraintArgCount,
+}
+
+var itemsConstraint = constraintSpec{
+	kinds:     []valueKind{valueKindList},
+	kindsName: "lists",
+	arg:       constraintArgCount,
+}
+
+var constraintSpecs = map[ast.ConstraintName]constraintSpec{
+	ast.ConstraintMin:       numberConstraint,
+	ast.ConstraintMax:       numberConstraint,
+	ast.ConstraintMinLength: lengthConstraint,
+	ast.ConstraintMaxLength: lengthConstraint,
+	ast.ConstraintPattern: {
+		kinds:     []valueKind{valueKindString},
+		kindsName: "strings",
+		arg:       constraintArgPattern,
+	},
+	ast.ConstraintMinItems: itemsConstraint,
+	ast.ConstraintMaxItems: itemsConstraint,
+}
+
+// kindOf finds the kind of values of a type, looking through aliases and
+// newtypes.
+func (t *translate) kindOf(ty st.Type, seen map[string]struct{}) valueKind {
+	if ty.List != nil {
+		return valueKindList
+	}
+	if ty.Map != nil {
+		return valueKindMap
+	}
+	if ty.TypeIdent == nil {
+		return valueKindOther
+	}
+	id := ty.TypeIdent.Id.Value
+	if _, ok := t.typeParams[id]; ok {
+		return valueKindOther
+	}
+	if _, ok := integerPrimitives[id]; ok {
+		return valueKindInteger
+	}
+	if _, ok := floatPrimitives[id]; ok {
+		return valueKindFloat
+	}
+	if _, ok := stringPrimitives[id]; ok {
+		return valueKindString
+	}
+	if _, ok := boolPrimitives[id]; ok {
+		return valueKindBool
+	}
+	sym, ok := t.symbols.getSymbol(id)
+	if !ok || sym.target == nil {
+		return valueKindOther
+	}
+	if _, ok := seen[id]; ok {
+		return valueKindOther
+	}
+	seen[id] = struct{}{}
+	return t.kindOf(*sym.target, seen)
+}
+
+// translateConstraints reads the constraints from the attributes of a field,
+// which have already been checked against the known attributes.
+func (t *translate) translateConstraints(ty st.Type, attributes ast.Attributes) []ast.Constraint {
+	var constraints []ast.Constraint
+	kind := t.kindOf(ty, make(map[string]struct{}))
+	for _, attribute := range attributes {
+		name := ast.ConstraintName(attribute.Name)
+		spec, ok := constraintSpecs[name]
+		if !ok {
+			continue
+		}
+		if !slices.Contains(spec.kinds, kind) {
+			t.addError(fmt.Sprintf("@%s can only be used on %s", name, spec.kindsName), attribute.Location)
+			continue
+		}
+		arg := attribute.Args[0]
+		if !t.checkConstraintArg(name, spec.arg, kind, arg) {
+			continue
+		}
+		constraints = append(constraints, ast.Constraint{
+			Name:  name,
+			Value: arg.Value,
+		})
+	}
+	return constraints
+}
+
+func (t *translate) checkConstraintArg(name ast.ConstraintName, constraintArg constraintArg, kind valueKind, arg ast.AttributeArg) bool {
+	switch constraintArg {
+	case constraintArgNumber:
+		if _, err := strconv.ParseInt(arg.Value, 10, 64); err != nil && kind == valueKindInteger {
+			t.addError(fmt.Sprintf("The argument of @%s must be an integer, as the field is an integer", name), arg.Location)
+			return false
+		}
+	case constraintArgCount:
+		count, err := strconv.ParseInt(arg.Value, 10, 64)
+		if err != nil || count < 0 {
+			t.addError(fmt.Sprintf("The argument of @%s must be a non negative integer", name), arg.Location)
+			return false
+		}
+	case constraintArgPattern:
+		if _, err := regexp.Compile(arg.Value); err != nil {
+			t.addError(fmt.Sprintf("Invalid pattern: %s", err.Error()), arg.Location)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package translate
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/brahms116/between/internal/ast"
+	"github.com/brahms116/between/internal/lex"
+	"github.com/brahms116/between/internal/st"
+)
+
+// valueKindDefaults describes the defaults fields of each kind can have, for
+// error messages.
+var valueKindDefaults = map[valueKind]string{
+	valueKindInteger: "an integer",
+	valueKindFloat:   "a number",
+	valueKindString:  "a string",
+	valueKindBool:    "true or false",
+	valueKindList:    "an empty list []",
+	valueKindMap:     "an empty map {}",
+}
+
+// translateDefault checks the default of a field against the type of the
+// field. Sumstr and sumint fields take the name of one of their variants.
+func (t *translate) translateDefault(d *st.Default, fieldId string, ty st.Type, fieldType ast.Type) *ast.Default {
+	if d == nil || d.Value.IsErr {
+		return nil
+	}
+	loc := d.Value.Loc
+	if fieldType.IsOptional() {
+		t.addError(fmt.Sprintf("The optional field %s cannot have a default", fieldId), loc)
+		return nil
+	}
+	if fieldType.IsNullable() {
+		t.addError(fmt.Sprintf("The nullable field %s cannot have a default", fieldId), loc)
+		return nil
+	}
+
+	if enumId, sym, ok := t.resolveEnum(ty); ok {
+		if d.Value.Type != lex.TOKEN_ID {
+			t.addError(fmt.Sprintf("The default of %s must be a variant of %s", fieldId, enumId), loc)
+			return nil
+		}
+		return t.translateVariantDefault(d.Value, enumId, sym)
+	}
+
+	kind := t.kindOf(ty, make(map[string]struct{}))
+	expected, ok := valueKindDefaults[kind]
+	if !ok {
+		t.addError(fmt.Sprintf("The field %s cannot have a default, only numbers, strings, bools, sumstrs, sumints, lists and maps can", fieldId), loc)
+		return nil
+	}
+
+	var res *ast.Default
+	switch d.Value.Type {
+	case lex.TOKEN_NUMBER:
+		_, err := strconv.ParseInt(d.Value.Value, 10, 64)
+		if kind == valueKindFloat || (kind == valueKindInteger && err == nil) {
+			res = &ast.Default{Kind: ast.DefaultNumber, Value: d.Value.Value}
+		}
+	case lex.TOKEN_LITERAL:
+		if kind == valueKindString {
+			res = &ast.Default{Kind: ast.DefaultString, Value: d.Value.Value}
+		}
+	case lex.TOKEN_BOOL:
+		if kind == valueKindBool {
+			res = &ast.Default{Kind: ast.DefaultBool, Value: d.Value.Value}
+		}
+	case lex.TOKEN_LIST:
+		if kind == valueKindList {
+			res = &ast.Default{Kind: ast.DefaultEmptyList}
+		}
+	case lex.TOKEN_LBRACE:
+		if kind == valueKindMap {
+			res = &ast.Default{Kind: ast.DefaultEmptyMap}
+		}
+	}
+	if res == nil {
+		t.addError(fmt.Sprintf("The default of %s must be %s", fieldId, expected), loc)
+	}
+	return res
+}
+
+func (t *translate) translateVariantDefault(value lex.Token, enumId string, sym symbol) *ast.Default {
+	if sym.sumStr != nil {
+		for _, v := range sym.sumStr.Variants {
+			if v.Id.Value != value.Value {
+				continue
+			}
+			jsonValue := v.Id.Value
+			if v.JsonName != nil {
+				jsonValue = v.JsonName.Value
+			}
+			return &ast.Default{Kind: ast.DefaultSumStr, Value: jsonValue, Enum: enumId, Variant: v.Id.Value}
+		}
+	}
+	if sym.sumInt != nil {
+		// Variants without a value take the one after the previous variant
+		var next int64
+		for _, v := range sym.sumInt.Variants {
+			n := next
+			if v.Value != nil {
+				n, _ = strconv.ParseInt(v.Value.Value, 10, 64)
+			}
+			next = n + 1
+			if v.Id.Value == value.Value {
+				return &ast.Default{Kind: ast.DefaultSumInt, Value: strconv.FormatInt(n, 10), Enum: enumId, Variant: v.Id.Value}
+			}
+		}
+	}
+	t.addError(fmt.Sprintf("%s is not a variant of %s", value.Value, enumId), value.Loc)
+	return nil
+}
+
+// resolveEnum finds the sumstr or sumint a type is, following aliases and
+// newtypes.
+func (t *translate) resolveEnum(ty st.Type) (string, symbol, bool) {
+	seen := make(map[string]struct{})
+	for ty.TypeIdent != nil {
+		id := ty.TypeIdent.Id.Value
+		if _, ok := t.typeParams[id]; ok {
+			break
+		}
+		if _, ok := seen[id]; ok {
+			break
+		}
+		seen[id] = struct{}{}
+		sym, ok := t.symbols.getSymbol(id)
+		if !ok {
+			break
+		}
+		if sym.sumStr != nil || sym.sumInt != nil {
+			return id, sym, true
+		}
+		if sym.target == nil {
+			break
+		}
+		ty = *sym.target
+	}
+	return "", symbol{}, false
+}
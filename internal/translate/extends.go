@@ -0,0 +1,191 @@
+package translate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+	"github.com/brahms116/between/internal/st"
+)
+
+// translateExtends checks the product a product extends, and adds the names of
+// the fields it inherits to fieldNames so they are reported if redeclared.
+func (t *translate) translateExtends(p st.Product, fieldNames map[string]struct{}) *ast.TypeIdent {
+	loc := p.Extends.Type.Id.Loc
+	extends := t.translateTypeIdent(p.Extends.Type)
+	t.checkTypeReference(extends.Id, len(extends.TypeArgs), loc)
+	if extends.Optional {
+		t.addError(fmt.Sprintf("Prod %s cannot extend an optional type", p.Id.Value), loc)
+		return nil
+	}
+	if _, ok := t.typeParams[extends.Id]; ok {
+		t.addError(fmt.Sprintf("Prod %s can only extend a prod, %s is a type parameter", p.Id.Value, extends.Id), loc)
+		return nil
+	}
+	sym, ok := t.symbols.getSymbol(extends.Id)
+	if !ok {
+		// Unknown types are already reported
+		return nil
+	}
+	if sym.typ != symbolTypeProduct {
+		t.addError(fmt.Sprintf("Prod %s can only extend a prod, %s is not a prod", p.Id.Value, extends.Id), loc)
+		return nil
+	}
+
+	chain := []string{p.Id.Value}
+	for ancestor := sym.product; ancestor != nil; ancestor = t.extendedProduct(*ancestor) {
+		if ancestor.Id.Value == p.Id.Value {
+			chain = append(chain, ancestor.Id.Value)
+			t.addError(fmt.Sprintf("Extends cycle: %s", strings.Join(chain, " -> ")), loc)
+			return nil
+		}
+		if slices.Contains(chain, ancestor.Id.Value) {
+			// A cycle further up, which is reported on the products in it
+			break
+		}
+		chain = append(chain, ancestor.Id.Value)
+		for _, f := range ancestor.Fields {
+			if name := fieldName(f); name != "" {
+				fieldNames[name] = struct{}{}
+			}
+		}
+	}
+	return &extends
+}
+
+// extendedProduct returns the definition of the product p extends, or nil if
+// it does not extend a product.
+func (t *translate) extendedProduct(p st.Product) *st.Product {
+	if p.Extends == nil {
+		return nil
+	}
+	sym, ok := t.symbols.getSymbol(p.Extends.Type.Id.Value)
+	if !ok {
+		return nil
+	}
+	return sym.product
+}
+
+// fieldName returns the name a field is translated to, or nothing if the field
+// could not be parsed.
+func fieldName(f st.Field) string {
+	if f.FieldFull != nil {
+		return f.FieldFull.Id.Value
+	}
+	if f.FieldShort != nil {
+		return lowerCaseFirstLetter(f.FieldShort.Id.Value)
+	}
+	return ""
+}
+
+// fillInheritedFields copies the fields of the products each product extends
+// into it, which can only be done once every file has been translated.
+func fillInheritedFields(files []ast.File) {
+	products := make(map[string]*ast.Product)
+	for _, f := range files {
+		for _, d := range f.Definitions {
+			if d.Product != nil {
+				products[d.Product.Id] = d.Product
+			}
+		}
+	}
+
+	filled := make(map[string]struct{})
+	var fill func(p *ast.Product)
+	fill = func(p *ast.Product) {
+		if _, ok := filled[p.Id]; ok || p.Extends == nil {
+			return
+		}
+		// Marked before recursing so cycles, which are reported, terminate
+		filled[p.Id] = struct{}{}
+		parent, ok := products[p.Extends.Id]
+		if !ok {
+			return
+		}
+		fill(parent)
+
+		args := make(map[string]ast.Type)
+		for i, param := range parent.TypeParams {
+			if i < len(p.Extends.TypeArgs) {
+				args[param] = p.Extends.TypeArgs[i]
+			}
+		}
+		for _, f := range parent.AllFields() {
+			f.Type = substituteTypeParams(f.Type, args)
+			p.InheritedFields = append(p.InheritedFields, f)
+		}
+	}
+	for _, p := range products {
+		fill(p)
+	}
+}
+
+// substituteTypeParams replaces the type parameters in ty with their
+// arguments, a parameter used as optional or nullable makes its argument
+// optional or nullable.
+func substituteTypeParams(ty ast.Type, args map[string]ast.Type) ast.Type {
+	if len(args) == 0 {
+		return ty
+	}
+	if ty.List != nil {
+		list := *ty.List
+		list.Type = substituteTypeParams(list.Type, args)
+		return ast.Type{List: &list}
+	}
+	if ty.Map != nil {
+		m := *ty.Map
+		m.Key = substituteTypeParams(m.Key, args)
+		m.Value = substituteTypeParams(m.Value, args)
+		return ast.Type{Map: &m}
+	}
+
+	ti := *ty.TypeIdent
+	if arg, ok := args[ti.Id]; ok && len(ti.TypeArgs) == 0 {
+		if ti.Optional {
+			arg = optionalType(arg)
+		}
+		if ti.Nullable {
+			arg = nullableType(arg)
+		}
+		return arg
+	}
+	var typeArgs []ast.Type
+	for _, arg := range ti.TypeArgs {
+		typeArgs = append(typeArgs, substituteTypeParams(arg, args))
+	}
+	ti.TypeArgs = typeArgs
+	return ast.Type{TypeIdent: &ti}
+}
+
+func optionalType(ty ast.Type) ast.Type {
+	if ty.List != nil {
+		list := *ty.List
+		list.Optional = true
+		return ast.Type{List: &list}
+	}
+	if ty.Map != nil {
+		m := *ty.Map
+		m.Optional = true
+		return ast.Type{Map: &m}
+	}
+	ti := *ty.TypeIdent
+	ti.Optional = true
+	return ast.Type{TypeIdent: &ti}
+}
+
+func nullableType(ty ast.Type) ast.Type {
+	if ty.List != nil {
+		list := *ty.List
+		list.Nullable = true
+		return ast.Type{List: &list}
+	}
+	if ty.Map != nil {
+		m := *ty.Map
+		m.Nullable = true
+		return ast.Type{Map: &m}
+	}
+	ti := *ty.TypeIdent
+	ti.Nullable = true
+	return ast.Type{TypeIdent: &ti}
+}
@@ -0,0 +1,71 @@
+package translate
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+
+	"github.com/brahms116/between/internal/ast"
+	"github.com/brahms116/between/internal/st"
+)
+
+// Targets are the targets the types of externs can be given for.
+var Targets = []string{"go", "ts", "rs", "py", "kt", "java", "swift", "cs"}
+
+// translateExtern checks the mappings of an extern, it needs one for each of
+// the targets being generated.
+func (t *translate) translateExtern(e st.Extern) ast.Extern {
+	targets := make(map[string]string)
+	for _, m := range e.Mappings {
+		if m.Target.IsErr || m.Type.IsErr {
+			continue
+		}
+		target := m.Target.Value
+		if !slices.Contains(Targets, target) {
+			t.addError(fmt.Sprintf("Unknown target %s, expected one of %s", target, strings.Join(Targets, ", ")), m.Target.Loc)
+			continue
+		}
+		if _, ok := targets[target]; ok {
+			t.addError(fmt.Sprintf("Duplicated mapping for %s", target), m.Target.Loc)
+			continue
+		}
+		if m.Type.Value == "" {
+			t.addError(fmt.Sprintf("The %s type of %s cannot be empty", target, e.Id.Value), m.Type.Loc)
+			continue
+		}
+		if target == "go" && !isGoExternType(m.Type.Value) {
+			t.addError(fmt.Sprintf("The go type of %s must be a type, qualified by the import path of its package when it is not predeclared, such as github.com/shopspring/decimal.Decimal", e.Id.Value), m.Type.Loc)
+		}
+		targets[target] = m.Type.Value
+	}
+	for _, target := range t.options.Targets {
+		if _, ok := targets[target]; !ok {
+			t.addError(fmt.Sprintf("Extern %s has no mapping for %s", e.Id.Value, target), e.Id.Loc)
+		}
+	}
+	return ast.Extern{
+		Doc:        translateDoc(e.Doc),
+		Id:         e.Id.Value,
+		Targets:    targets,
+		Attributes: t.translateAttributes(e.Attributes, placementExtern),
+	}
+}
+
+// isGoExternType reports whether a Go type of an extern is a name, such as
+// int64, or the import path of a package followed by the name of an exported
+// type, such as gopkg.in/yaml.v3.Node, after pointer and slice modifiers.
+func isGoExternType(s string) bool {
+	typeString := strings.TrimLeft(s, "*[]")
+	importPath, name := "", typeString
+	if dot := strings.LastIndex(typeString, "."); dot != -1 {
+		importPath, name = typeString[:dot], typeString[dot+1:]
+	}
+	if !token.IsIdentifier(name) {
+		return false
+	}
+	if importPath == "" {
+		return true
+	}
+	return token.IsExported(name) && !strings.HasSuffix(importPath, "/")
+}
@@ -0,0 +1,352 @@
+package translate
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/brahms116/between/internal/ast"
+	"github.com/brahms116/between/internal/lex"
+	"github.com/brahms116/between/internal/st"
+)
+
+// generatedName is an identifier the code generated for a target declares,
+// derived from a name in the schema.
+type generatedName struct {
+	name string
+	// what the name is derived from, such as "variant which of Event"
+	from string
+	path string
+	loc  lex.Location
+}
+
+func (g generatedName) describe(path string) string {
+	s := g.from + " at " + g.loc.Start.String()
+	if g.path != path {
+		s += " in " + g.path
+	}
+	return s
+}
+
+// nameScope is a scope of the generated code, such as the package or the
+// members of a struct, in which names have to be unique and cannot be the
+// names the generated code declares there itself.
+type nameScope struct {
+	names    map[string]generatedName
+	reserved map[string]struct{}
+}
+
+func newNameScope(reserved ...string) *nameScope {
+	return &nameScope{names: make(map[string]generatedName), reserved: setOf(reserved...)}
+}
+
+// inheritedField is a field of a product or of one of the products it extends.
+type inheritedField struct {
+	field st.Field
+	owner string
+	path  string
+}
+
+// checkGeneratedNames reports the names the generated code of each target
+// would declare twice, as names in the schema which differ become the same once
+// capitalised, snake_cased or prefixed, and the names it already declares
+// itself, such as the Which method of a Go sum which a variant named which
+// would clash with.
+func (t *translate) checkGeneratedNames(files []ast.File) {
+	for _, target := range t.options.Targets {
+		g := generatedNamesCheck{t: t, target: target, reported: make(map[string]struct{})}
+		g.check(files)
+	}
+}
+
+type generatedNamesCheck struct {
+	t      *translate
+	target string
+	// the pairs of names already reported, so names derived from the same two
+	// names are reported once
+	reported map[string]struct{}
+}
+
+func (g *generatedNamesCheck) check(files []ast.File) {
+	// Types are declared first, so the names derived from them are reported
+	// rather than the types. Types named alike are already reported as
+	// duplicated.
+	topLevel := newNameScope()
+	for _, path := range g.t.order {
+		for _, d := range g.t.files[path] {
+			if d.Import != nil {
+				continue
+			}
+			id := definitionId(d)
+			if _, ok := topLevel.names[id.Value]; !ok {
+				topLevel.names[id.Value] = generatedName{name: id.Value, from: definitionKind(d) + " " + id.Value, path: path, loc: id.Loc}
+			}
+		}
+	}
+
+	for _, f := range files {
+		var definitions []st.Definition
+		for _, d := range g.t.files[f.Path] {
+			if d.Import == nil {
+				definitions = append(definitions, d)
+			}
+		}
+		for i, d := range definitions {
+			switch {
+			case d.Product != nil && f.Definitions[i].Product != nil:
+				g.checkProduct(topLevel, *d.Product, *f.Definitions[i].Product, f.Path)
+			case d.Sum != nil && f.Definitions[i].Sum != nil:
+				g.checkSum(topLevel, *d.Sum, *f.Definitions[i].Sum, f.Path)
+			case d.SumStr != nil:
+				var variants []lex.Token
+				for _, v := range d.SumStr.Variants {
+					variants = append(variants, v.Id)
+				}
+				g.checkEnum(topLevel, d.SumStr.Id.Value, variants, f.Path)
+			case d.SumInt != nil:
+				var variants []lex.Token
+				for _, v := range d.SumInt.Variants {
+					variants = append(variants, v.Id)
+				}
+				g.checkEnum(topLevel, d.SumInt.Id.Value, variants, f.Path)
+			}
+		}
+	}
+}
+
+func (g *generatedNamesCheck) checkProduct(topLevel *nameScope, p st.Product, translated ast.Product, path string) {
+	astFields := make(map[string]ast.Field)
+	hasDefaults := false
+	hasConstraints := false
+	for _, f := range translated.AllFields() {
+		astFields[f.Id] = f
+		hasDefaults = hasDefaults || f.Default != nil
+		hasConstraints = hasConstraints || len(f.Constraints) > 0
+	}
+	fields := g.t.inheritedFields(p, path)
+	from := "prod " + p.Id.Value
+
+	var members *nameScope
+	switch g.target {
+	case "go":
+		members = newNameScope("MarshalJSON", "UnmarshalJSON")
+		if hasConstraints {
+			members.reserved["Validate"] = struct{}{}
+		}
+		if p.Extends != nil {
+			// The extended product is embedded, which declares a field named
+			// like it
+			extended := p.Extends.Type.Id
+			g.declare(members, generatedName{name: extended.Value, from: "extended prod " + extended.Value, path: path, loc: extended.Loc})
+		}
+		if hasDefaults {
+			g.declare(topLevel, generatedName{name: "New" + p.Id.Value, from: from, path: path, loc: p.Id.Loc})
+		}
+	case "ts":
+		if hasDefaults {
+			g.declare(topLevel, generatedName{name: "default" + p.Id.Value, from: from, path: path, loc: p.Id.Loc})
+		}
+		if hasConstraints {
+			g.declare(topLevel, generatedName{name: "validate" + p.Id.Value, from: from, path: path, loc: p.Id.Loc})
+		}
+	case "rs", "py", "cs":
+		members = newNameScope()
+	}
+
+	for _, f := range fields {
+		id := fieldName(f.field)
+		if id == "" {
+			continue
+		}
+		field := generatedName{from: fmt.Sprintf("field %s of %s", id, f.owner), path: f.path, loc: f.field.Id().Loc}
+		astField := astFields[id]
+		switch g.target {
+		case "go", "cs":
+			field.name = capitalizeFirstLetter(id)
+		case "rs", "py":
+			field.name = snakeCase(id)
+		}
+		if members != nil {
+			g.declare(members, field)
+		}
+
+		switch {
+		case g.target == "go" && hasPatternConstraint(astField):
+			field.name = lowerCaseFirstLetter(p.Id.Value) + capitalizeFirstLetter(id) + "Pattern"
+			g.declare(topLevel, field)
+		case g.target == "rs" && astField.Default != nil:
+			field.name = fmt.Sprintf("default_%s_%s", snakeCase(p.Id.Value), snakeCase(id))
+			g.declare(topLevel, field)
+		}
+	}
+}
+
+func (g *generatedNamesCheck) checkSum(topLevel *nameScope, s st.Sum, translated ast.Sum, path string) {
+	from := "sum " + s.Id.Value
+	var members *nameScope
+	switch g.target {
+	case "go":
+		members = newNameScope("Which", "MarshalJSON", "UnmarshalJSON")
+		g.declare(topLevel, generatedName{name: s.Id.Value + "Variant", from: from, path: path, loc: s.Id.Loc})
+	case "rs", "swift":
+		members = newNameScope()
+	case "cs":
+		g.declare(topLevel, generatedName{name: s.Id.Value + "Converter", from: from, path: path, loc: s.Id.Loc})
+		if s.TypeParams != nil {
+			g.declare(topLevel, generatedName{name: s.Id.Value + "ConverterFactory", from: from, path: path, loc: s.Id.Loc})
+		}
+	}
+
+	for _, v := range s.Variants {
+		id := fieldName(v)
+		if id == "" {
+			continue
+		}
+		variant := generatedName{from: fmt.Sprintf("variant %s of %s", id, s.Id.Value), path: path, loc: v.Id().Loc}
+		switch g.target {
+		case "go":
+			variant.name = capitalizeFirstLetter(id)
+			g.declare(members, variant)
+			variant.name = s.Id.Value + "Variant_" + capitalizeFirstLetter(id)
+			g.declare(topLevel, variant)
+			variant.name = "New" + s.Id.Value + capitalizeFirstLetter(id)
+			g.declare(topLevel, variant)
+		case "rs":
+			variant.name = capitalizeFirstLetter(id)
+			g.declare(members, variant)
+		case "swift":
+			variant.name = lowerCaseFirstLetter(id)
+			g.declare(members, variant)
+		case "py":
+			// Untagged sums are a union of their variants
+			if translated.Encoding != ast.SumEncodingUntagged {
+				variant.name = s.Id.Value + capitalizeFirstLetter(id)
+				g.declare(topLevel, variant)
+			}
+		case "kt", "java", "cs":
+			variant.name = s.Id.Value + capitalizeFirstLetter(id)
+			g.declare(topLevel, variant)
+		}
+	}
+}
+
+// checkEnum checks the names of the variants of a sumstr or sumint.
+func (g *generatedNamesCheck) checkEnum(topLevel *nameScope, id string, variants []lex.Token, path string) {
+	members := newNameScope()
+	for _, v := range variants {
+		variant := generatedName{from: fmt.Sprintf("variant %s of %s", v.Value, id), path: path, loc: v.Loc}
+		switch g.target {
+		case "go":
+			variant.name = id + "_" + v.Value
+			g.declare(topLevel, variant)
+		case "swift":
+			variant.name = lowerCaseFirstLetter(v.Value)
+			g.declare(members, variant)
+		case "cs":
+			variant.name = capitalizeFirstLetter(v.Value)
+			g.declare(members, variant)
+		}
+	}
+}
+
+// declare adds a name to a scope, reporting it when the scope already has it.
+func (g *generatedNamesCheck) declare(scope *nameScope, name generatedName) {
+	t := g.t
+	if _, ok := scope.reserved[name.name]; ok {
+		t.path = name.path
+		t.addError(fmt.Sprintf("The name %s of %s is reserved in %s", name.name, name.from, targetNames[g.target]), name.loc)
+		return
+	}
+	other, ok := scope.names[name.name]
+	if !ok {
+		scope.names[name.name] = name
+		return
+	}
+	// The same name declared twice is already reported as duplicated
+	if other.from == name.from {
+		return
+	}
+	pair := other.describe("") + "|" + name.describe("")
+	if _, ok := g.reported[pair]; ok {
+		return
+	}
+	g.reported[pair] = struct{}{}
+	t.path = name.path
+	t.addError(fmt.Sprintf("The name %s of %s in %s collides with %s", name.name, name.from, targetNames[g.target], other.describe(name.path)), name.loc)
+}
+
+// inheritedFields are the fields of a product, the fields it inherits first.
+func (t *translate) inheritedFields(p st.Product, path string) []inheritedField {
+	var ancestors []st.Product
+	seen := map[string]struct{}{p.Id.Value: {}}
+	for ancestor := t.extendedProduct(p); ancestor != nil; ancestor = t.extendedProduct(*ancestor) {
+		if _, ok := seen[ancestor.Id.Value]; ok {
+			// Extends cycles are reported
+			break
+		}
+		seen[ancestor.Id.Value] = struct{}{}
+		ancestors = append(ancestors, *ancestor)
+	}
+
+	var fields []inheritedField
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		sym, _ := t.symbols.getSymbol(ancestors[i].Id.Value)
+		for _, f := range ancestors[i].Fields {
+			fields = append(fields, inheritedField{field: f, owner: ancestors[i].Id.Value, path: sym.path})
+		}
+	}
+	for _, f := range p.Fields {
+		fields = append(fields, inheritedField{field: f, owner: p.Id.Value, path: path})
+	}
+	return fields
+}
+
+func hasPatternConstraint(f ast.Field) bool {
+	for _, c := range f.Constraints {
+		if c.Name == ast.ConstraintPattern {
+			return true
+		}
+	}
+	return false
+}
+
+// definitionKind is the keyword a definition is declared with.
+func definitionKind(d st.Definition) string {
+	switch {
+	case d.Product != nil:
+		return "prod"
+	case d.Sum != nil:
+		return "sum"
+	case d.SumStr != nil:
+		return "sumstr"
+	case d.SumInt != nil:
+		return "sumint"
+	case d.Alias != nil:
+		return "alias"
+	case d.NewType != nil:
+		return "newtype"
+	case d.Extern != nil:
+		return "extern"
+	}
+	return "type"
+}
+
+// snakeCase converts a name to snake case as the Rust and Python generators
+// do, runs of capitals, as in userID, being a single word.
+func snakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			previousUpper := i > 0 && unicode.IsUpper(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if i > 0 && (!previousUpper || nextLower) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
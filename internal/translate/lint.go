@@ -0,0 +1,63 @@
+package translate
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/brahms116/between/internal/lex"
+)
+
+// Identifiers are letters and digits starting with a letter, so their case is
+// the case of their first letter.
+
+func (t *translate) checkTypeNameCase(id lex.Token) {
+	if !startsUpper(id.Value) {
+		t.addWarning(fmt.Sprintf("Type name %s should be PascalCase, %s", id.Value, capitalizeFirstLetter(id.Value)), id.Loc)
+	}
+}
+
+func (t *translate) checkVariantNameCase(id lex.Token) {
+	if !startsUpper(id.Value) {
+		t.addWarning(fmt.Sprintf("Variant name %s should be PascalCase, %s", id.Value, capitalizeFirstLetter(id.Value)), id.Loc)
+	}
+}
+
+func (t *translate) checkFieldNameCase(id lex.Token) {
+	if startsUpper(id.Value) {
+		t.addWarning(fmt.Sprintf("Field name %s should be camelCase, %s", id.Value, lowerCaseFirstLetter(id.Value)), id.Loc)
+	}
+}
+
+// checkJsonNameCase reports fields whose JSON names differ only in case, as Go
+// matches JSON names case insensitively when decoding, so either field could
+// receive the value of the other.
+func (t *translate) checkJsonNameCase(names []wireName) {
+	existing := make(map[string]wireName)
+	for _, w := range names {
+		folded := strings.ToLower(w.name)
+		other, ok := existing[folded]
+		if !ok {
+			existing[folded] = w
+			continue
+		}
+		if other.name != w.name && w.owner == "" {
+			msg := fmt.Sprintf("The JSON name %s of %s differs only in case from %s of %s, Go cannot tell them apart when decoding", w.name, w.id, other.name, other.describe(t.path))
+			t.addWarning(msg, w.loc)
+		}
+	}
+}
+
+func startsUpper(s string) bool {
+	for _, r := range s {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
+
+func capitalizeFirstLetter(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	return strings.ToUpper(string(s[0])) + s[1:]
+}
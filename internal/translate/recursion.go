@@ -0,0 +1,255 @@
+package translate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/brahms116/between/internal/lex"
+	"github.com/brahms116/between/internal/st"
+)
+
+// valueEdge is a type which a definition contains by value. Optional and
+// nullable fields, lists, maps and the variants of sums are pointers or
+// references in the generated code, so types reached through them are not
+// contained by value.
+type valueEdge struct {
+	to string
+	// the generic prods the edge goes through, such as Box for a field of
+	// type Box<Node> where Box contains its type parameter
+	via []string
+	// the reference to the first type on the edge
+	loc     lex.Location
+	extends bool
+}
+
+type visitState int
+
+const (
+	visitStateVisiting visitState = iota + 1
+	visitStateDone
+)
+
+// checkRecursiveTypes reports types which contain themselves by value, which
+// would be infinitely large, and aliases which refer to themselves, as Go
+// aliases cannot be recursive.
+func (t *translate) checkRecursiveTypes() {
+	var ids []string
+	for _, path := range t.order {
+		for _, d := range t.files[path] {
+			switch {
+			case d.Product != nil:
+				ids = append(ids, d.Product.Id.Value)
+			case d.Alias != nil:
+				ids = append(ids, d.Alias.Id.Value)
+			case d.NewType != nil:
+				ids = append(ids, d.NewType.Id.Value)
+			}
+		}
+	}
+
+	t.findCycles(ids, t.aliasEdges, func(cycle []string, edges []valueEdge) {
+		path := append(cycle, cycle[0])
+		t.addCycleError(fmt.Sprintf("Alias cycle: %s", strings.Join(path, " -> ")), cycle[0], edges[0].loc)
+	})
+	t.findCycles(ids, t.valueEdges, func(cycle []string, edges []valueEdge) {
+		allExtends := true
+		allAliases := true
+		for i, e := range edges {
+			allExtends = allExtends && e.extends
+			sym, _ := t.symbols.getSymbol(cycle[i])
+			allAliases = allAliases && sym.typ == symbolTypeAlias
+		}
+		if allExtends || allAliases {
+			// Already reported as an extends or alias cycle
+			return
+		}
+		path := []string{cycle[0]}
+		for _, e := range edges {
+			path = append(path, e.via...)
+			path = append(path, e.to)
+		}
+		msg := fmt.Sprintf("Recursive type %s contains itself: %s, make a field on the cycle optional, nullable, a list or a map", cycle[0], strings.Join(path, " -> "))
+		t.addCycleError(msg, cycle[0], edges[0].loc)
+	})
+}
+
+func (t *translate) addCycleError(msg string, id string, loc lex.Location) {
+	sym, _ := t.symbols.getSymbol(id)
+	t.path = sym.path
+	t.addError(msg, loc)
+}
+
+// findCycles walks the graph given by edges from each of the ids, calling
+// report with the types on each cycle found and the edges between them.
+func (t *translate) findCycles(ids []string, edges func(string) []valueEdge, report func([]string, []valueEdge)) {
+	states := make(map[string]visitState)
+	var visit func(id string, stack []string, stackEdges []valueEdge)
+	visit = func(id string, stack []string, stackEdges []valueEdge) {
+		states[id] = visitStateVisiting
+		stack = append(stack, id)
+		for _, e := range edges(id) {
+			switch states[e.to] {
+			case visitStateVisiting:
+				start := slices.Index(stack, e.to)
+				cycleEdges := append(slices.Clone(stackEdges[start:]), e)
+				report(slices.Clone(stack[start:]), cycleEdges)
+			case visitStateDone:
+			default:
+				visit(e.to, stack, append(stackEdges, e))
+			}
+		}
+		states[id] = visitStateDone
+	}
+	for _, id := range ids {
+		if states[id] == 0 {
+			visit(id, nil, nil)
+		}
+	}
+}
+
+// aliasEdges are the aliases an alias refers to anywhere in its type.
+func (t *translate) aliasEdges(id string) []valueEdge {
+	sym, ok := t.symbols.getSymbol(id)
+	if !ok || sym.typ != symbolTypeAlias {
+		return nil
+	}
+	var edges []valueEdge
+	var walk func(ty st.Type)
+	walk = func(ty st.Type) {
+		switch {
+		case ty.List != nil:
+			walk(ty.List.Type)
+		case ty.Map != nil:
+			walk(ty.Map.Key)
+			walk(ty.Map.Value)
+		case ty.TypeIdent != nil:
+			if target, ok := t.symbols.getSymbol(ty.TypeIdent.Id.Value); ok && target.typ == symbolTypeAlias {
+				edges = append(edges, valueEdge{to: ty.TypeIdent.Id.Value, loc: ty.TypeIdent.Id.Loc})
+			}
+			if ty.TypeIdent.TypeArgs != nil {
+				for _, arg := range ty.TypeIdent.TypeArgs.Args {
+					walk(arg)
+				}
+			}
+		}
+	}
+	walk(*sym.target)
+	return edges
+}
+
+// valueEdges are the types a definition contains by value, including the type
+// arguments of generic prods which contain their type parameters.
+func (t *translate) valueEdges(id string) []valueEdge {
+	sym, ok := t.symbols.getSymbol(id)
+	if !ok {
+		return nil
+	}
+	var edges []valueEdge
+	for _, ref := range t.valueRefs(sym) {
+		edges = append(edges, t.typeIdentValueEdges(ref.typeIdent, ref.extends, nil, sym.params)...)
+	}
+	return edges
+}
+
+func (t *translate) typeIdentValueEdges(ti st.TypeIdent, extends bool, via []string, params []string) []valueEdge {
+	id := ti.Id.Value
+	if slices.Contains(params, id) {
+		// Checked where the type argument is given
+		return nil
+	}
+	sym, ok := t.symbols.getSymbol(id)
+	if !ok {
+		return nil
+	}
+	edges := []valueEdge{{to: id, via: via, loc: ti.Id.Loc, extends: extends}}
+	if ti.TypeArgs == nil {
+		return edges
+	}
+	for i, arg := range ti.TypeArgs.Args {
+		if !isValueType(arg) || i >= len(sym.params) || !t.containsParam(id, i, make(map[string]struct{})) {
+			continue
+		}
+		argEdges := t.typeIdentValueEdges(*arg.TypeIdent, false, append(slices.Clone(via), id), params)
+		for _, e := range argEdges {
+			// Located at the reference to the generic prod
+			e.loc = ti.Id.Loc
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// containsParam reports whether the generic prod id contains its type
+// parameter at index by value.
+func (t *translate) containsParam(id string, index int, visiting map[string]struct{}) bool {
+	key := fmt.Sprintf("%s/%d", id, index)
+	if _, ok := visiting[key]; ok {
+		return false
+	}
+	visiting[key] = struct{}{}
+	defer delete(visiting, key)
+	sym, ok := t.symbols.getSymbol(id)
+	if !ok || index >= len(sym.params) {
+		return false
+	}
+	param := sym.params[index]
+	var refContains func(ti st.TypeIdent) bool
+	refContains = func(ti st.TypeIdent) bool {
+		if ti.Id.Value == param {
+			return true
+		}
+		if ti.TypeArgs == nil {
+			return false
+		}
+		for j, arg := range ti.TypeArgs.Args {
+			if isValueType(arg) && refContains(*arg.TypeIdent) && t.containsParam(ti.Id.Value, j, visiting) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, ref := range t.valueRefs(sym) {
+		if refContains(ref.typeIdent) {
+			return true
+		}
+	}
+	return false
+}
+
+type valueRef struct {
+	typeIdent st.TypeIdent
+	extends   bool
+}
+
+// valueRefs are the types written in a definition which it contains by value,
+// the product it extends, its fields which are not optional, nullable, lists or
+// maps, or the type of an alias or newtype.
+func (t *translate) valueRefs(sym symbol) []valueRef {
+	var refs []valueRef
+	switch sym.typ {
+	case symbolTypeProduct:
+		if sym.product.Extends != nil {
+			refs = append(refs, valueRef{typeIdent: sym.product.Extends.Type, extends: true})
+		}
+		for _, f := range sym.product.Fields {
+			if f.FieldFull != nil && f.FieldFull.Optional == nil && f.FieldFull.Null == nil && isValueType(f.FieldFull.Type) {
+				refs = append(refs, valueRef{typeIdent: *f.FieldFull.Type.TypeIdent})
+			}
+			if f.FieldShort != nil && f.FieldShort.Optional == nil && f.FieldShort.Null == nil {
+				refs = append(refs, valueRef{typeIdent: st.TypeIdent{Id: f.FieldShort.Id}})
+			}
+		}
+	case symbolTypeAlias, symbolTypeNewType:
+		if isValueType(*sym.target) {
+			refs = append(refs, valueRef{typeIdent: *sym.target.TypeIdent})
+		}
+	}
+	return refs
+}
+
+// isValueType reports whether a type is contained by value, rather than being
+// optional, a list or a map.
+func isValueType(ty st.Type) bool {
+	return ty.TypeIdent != nil && ty.TypeIdent.Optional == nil && !ty.TypeIdent.Id.IsErr
+}
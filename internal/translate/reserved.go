@@ -0,0 +1,182 @@
+package translate
+
+import (
+	"fmt"
+
+	"github.com/brahms116/between/internal/lex"
+)
+
+// targetNames are the names of the targets in error messages.
+var targetNames = map[string]string{
+	"go":    "Go",
+	"ts":    "TypeScript",
+	"rs":    "Rust",
+	"py":    "Python",
+	"kt":    "Kotlin",
+	"java":  "Java",
+	"swift": "Swift",
+	"cs":    "C#",
+}
+
+// reservedNames are the names types and type parameters cannot have in each
+// target. They are its keywords and the names the generated code relies on,
+// which a type of the same name would shadow. Keywords used as field names are
+// escaped by the generators, the names generated from fields and variants are
+// checked by checkGeneratedNames.
+var reservedNames = map[string]map[string]struct{}{
+	"go": setOf(
+		// keywords
+		"break", "case", "chan", "const", "continue", "default", "defer", "else",
+		"fallthrough", "for", "func", "go", "goto", "if", "import", "interface",
+		"map", "package", "range", "return", "select", "struct", "switch", "type",
+		"var",
+		// predeclared identifiers
+		"any", "bool", "byte", "comparable", "complex64", "complex128", "error",
+		"false", "float32", "float64", "int", "int8", "int16", "int32", "int64",
+		"iota", "nil", "rune", "string", "true", "uint", "uint8", "uint16",
+		"uint32", "uint64", "uintptr", "append", "cap", "clear", "close",
+		"complex", "copy", "delete", "imag", "len", "make", "max", "min", "new",
+		"panic", "print", "println", "real", "recover",
+		// imported packages and helper types
+		"bytes", "errors", "fmt", "json", "regexp", "strconv", "time", "utf8",
+		"Nullable", "NewNullable", "NewNull", "jsonField", "marshalJSONObject", "unmarshalJSONObject",
+	),
+	"ts": setOf(
+		// keywords, including those reserved in strict mode
+		"break", "case", "catch", "class", "const", "continue", "debugger",
+		"default", "delete", "do", "else", "enum", "export", "extends", "false",
+		"finally", "for", "function", "if", "import", "in", "instanceof", "new",
+		"null", "return", "super", "switch", "this", "throw", "true", "try",
+		"typeof", "var", "void", "while", "with", "implements", "interface", "let",
+		"package", "private", "protected", "public", "static", "yield",
+		// predefined types
+		"any", "unknown", "never", "string", "number", "boolean", "symbol",
+		"object", "bigint", "undefined",
+		// globals used by the generated code
+		"Record", "Partial", "Omit", "Pick", "RegExp",
+	),
+	"rs": setOf(
+		// keywords, including reserved ones
+		"as", "break", "const", "continue", "crate", "else", "enum", "extern",
+		"false", "fn", "for", "if", "impl", "in", "let", "loop", "match", "mod",
+		"move", "mut", "pub", "ref", "return", "self", "Self", "static", "struct",
+		"super", "trait", "true", "type", "unsafe", "use", "where", "while",
+		"async", "await", "dyn", "abstract", "become", "box", "do", "final",
+		"macro", "override", "priv", "typeof", "unsized", "virtual", "yield",
+		"try", "gen",
+		// primitive types, and the types and traits the generated code uses
+		"bool", "char", "str", "i8", "i16", "i32", "i64", "i128", "isize", "u8",
+		"u16", "u32", "u64", "u128", "usize", "f32", "f64", "String", "Vec",
+		"Option", "Some", "None", "Result", "Ok", "Err", "Box", "HashMap",
+		"Serialize", "Deserialize", "Serializer", "Deserializer", "serde",
+		"serde_json", "std",
+	),
+	"py": setOf(
+		// keywords
+		"False", "None", "True", "and", "as", "assert", "async", "await",
+		"break", "class", "continue", "def", "del", "elif", "else", "except",
+		"finally", "for", "from", "global", "if", "import", "in", "is", "lambda",
+		"nonlocal", "not", "or", "pass", "raise", "return", "try", "while",
+		"with", "yield",
+		// builtins and the names the generated code imports
+		"str", "int", "float", "bool", "list", "dict", "date", "datetime",
+		"Decimal", "UUID", "Enum", "IntEnum", "Annotated", "Any", "Generic",
+		"Literal", "NewType", "Optional", "TypeVar", "Union", "TypeAliasType",
+		"BaseModel", "ConfigDict", "Field", "PlainSerializer",
+	),
+	"kt": setOf(
+		// hard keywords
+		"as", "break", "class", "continue", "do", "else", "false", "for", "fun",
+		"if", "in", "interface", "is", "null", "object", "package", "return",
+		"super", "this", "throw", "true", "try", "typealias", "typeof", "val",
+		"var", "when", "while",
+		// builtins and the names the generated code imports
+		"String", "Long", "Int", "Boolean", "Float", "Double", "List", "Map",
+		"Any", "Unit", "Nothing", "Deprecated", "JvmInline", "Serializable",
+		"SerialName", "SerializationException", "KSerializer", "SerialDescriptor",
+		"PrimitiveSerialDescriptor", "PrimitiveKind", "Encoder", "Decoder",
+		"JsonElement", "JsonObject", "JsonPrimitive", "JsonEncoder", "JsonDecoder",
+		"Int64", "Int64Serializer", "Optional", "OptionalSerializer",
+	),
+	"java": setOf(
+		// keywords, including contextual ones which cannot name types
+		"abstract", "assert", "boolean", "break", "byte", "case", "catch", "char",
+		"class", "const", "continue", "default", "do", "double", "else", "enum",
+		"extends", "final", "finally", "float", "for", "goto", "if", "implements",
+		"import", "instanceof", "int", "interface", "long", "native", "new",
+		"package", "private", "protected", "public", "return", "short", "static",
+		"strictfp", "super", "switch", "synchronized", "this", "throw", "throws",
+		"transient", "try", "void", "volatile", "while", "true", "false", "null",
+		"var", "yield", "record", "sealed", "permits",
+		// java.lang classes and the names the generated code imports
+		"String", "Long", "Integer", "Boolean", "Float", "Double", "Object",
+		"Override", "Deprecated", "IllegalArgumentException", "List", "Map",
+		"IOException", "JsonCreator", "JsonFormat", "JsonInclude", "JsonProperty",
+		"JsonValue", "JsonGenerator", "JsonParser", "BeanProperty",
+		"DeserializationContext", "JavaType", "JsonDeserializer",
+		"JsonMappingException", "JsonNode", "ObjectNode", "SerializerProvider",
+		"JsonDeserialize", "JsonSerialize", "ContextualDeserializer",
+		"StdDeserializer", "StdSerializer", "ToStringSerializer", "TypeFactory",
+		"NameTransformer", "Serializer", "Deserializer",
+	),
+	"swift": setOf(
+		// keywords
+		"associatedtype", "class", "deinit", "enum", "extension", "fileprivate",
+		"func", "import", "init", "inout", "internal", "let", "open", "operator",
+		"private", "precedencegroup", "protocol", "public", "rethrows", "static",
+		"struct", "subscript", "typealias", "var", "break", "case", "catch",
+		"continue", "default", "defer", "do", "else", "fallthrough", "for",
+		"guard", "if", "in", "repeat", "return", "throw", "switch", "where",
+		"while", "Any", "as", "await", "false", "is", "nil", "self", "Self",
+		"super", "throws", "true", "try", "Type", "Protocol",
+		// standard library types and the names the generated code relies on
+		"String", "Int", "Int32", "Int64", "Float", "Double", "Bool", "Optional",
+		"Codable", "Hashable", "CodingKey", "CodingKeys", "CodingKeyRepresentable",
+		"Decoder", "Encoder", "DecodingError", "Int64String", "JSONValue", "Box",
+		"TagKeys", "AnyKey",
+	),
+	"cs": setOf(
+		// keywords
+		"abstract", "as", "base", "bool", "break", "byte", "case", "catch",
+		"char", "checked", "class", "const", "continue", "decimal", "default",
+		"delegate", "do", "double", "else", "enum", "event", "explicit",
+		"extern", "false", "finally", "fixed", "float", "for", "foreach", "goto",
+		"if", "implicit", "in", "int", "interface", "internal", "is", "lock",
+		"long", "namespace", "new", "null", "object", "operator", "out",
+		"override", "params", "private", "protected", "public", "readonly", "ref",
+		"return", "sbyte", "sealed", "short", "sizeof", "stackalloc", "static",
+		"string", "struct", "switch", "this", "throw", "true", "try", "typeof",
+		"uint", "ulong", "unchecked", "unsafe", "ushort", "using", "virtual",
+		"void", "volatile", "while", "record", "var", "dynamic",
+		// the types the generated code uses
+		"System", "Type", "Exception", "Activator", "Obsolete", "List",
+		"Dictionary", "CultureInfo", "InvalidOperationException",
+		"FormatException", "JsonElement", "JsonDocument", "JsonValueKind",
+		"JsonTokenType", "JsonSerializer", "JsonSerializerOptions",
+		"JsonException", "JsonConverter", "JsonConverterFactory",
+		"Utf8JsonReader", "Utf8JsonWriter", "JsonPropertyName", "JsonIgnore",
+		"JsonIgnoreCondition", "JsonNumberHandling", "JsonStringEnumConverter",
+		"JsonStringEnumMemberName", "Converter", "Tuple",
+		// helper types
+		"Optional", "OptionalConverter", "OptionalConverterFactory",
+		"OptionalInt64Converter",
+	),
+}
+
+func setOf(names ...string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	return set
+}
+
+// checkReservedName reports names of types and type parameters which are
+// reserved in any of the targets being generated.
+func (t *translate) checkReservedName(name lex.Token) {
+	for _, target := range t.options.Targets {
+		if _, ok := reservedNames[target][name.Value]; ok {
+			t.addError(fmt.Sprintf("The name %s is reserved in %s", name.Value, targetNames[target]), name.Loc)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package translate
+
+import (
+	"fmt"
+
+	"github.com/brahms116/between/internal/ast"
+	"github.com/brahms116/between/internal/lex"
+	"github.com/brahms116/between/internal/st"
+)
+
+// translateSumEncoding reads the encoding of a sum from its @tag or @untagged
+// attribute, returning the encoding along with its tag and content keys.
+func (t *translate) translateSumEncoding(attributes ast.Attributes) (ast.SumEncoding, string, string) {
+	tagAttribute, isTagged := attributes.Get("tag")
+	untaggedAttribute, isUntagged := attributes.Get("untagged")
+	if isTagged && isUntagged {
+		t.addError("A sum can only have one of @tag and @untagged", untaggedAttribute.Location)
+		return ast.SumEncodingExternal, "", ""
+	}
+	if isUntagged {
+		return ast.SumEncodingUntagged, "", ""
+	}
+	if !isTagged {
+		return ast.SumEncodingExternal, "", ""
+	}
+
+	tag, _ := tagAttribute.Arg("", 0)
+	content, ok := tagAttribute.Arg("content", 0)
+	if !ok {
+		return ast.SumEncodingInternal, tag, ""
+	}
+	if content == tag {
+		t.addError("The tag and content keys of @tag must be different", tagAttribute.Location)
+		return ast.SumEncodingInternal, tag, ""
+	}
+	return ast.SumEncodingAdjacent, tag, content
+}
+
+// checkInternallyTaggedVariant reports variants of internally tagged sums which
+// are not prods, or are prods with a field sharing its key with the tag, as the
+// tag is added to the fields of the variant.
+func (t *translate) checkInternallyTaggedVariant(sumId string, tag string, variant ast.Field, location lex.Location) {
+	p := t.resolveProduct(variant.Type)
+	if p == nil {
+		t.addError(fmt.Sprintf("Variant %s of %s must be a prod, as %s is internally tagged", variant.Id, sumId, sumId), location)
+		return
+	}
+	seen := make(map[string]struct{})
+	for ancestor := p; ancestor != nil; ancestor = t.extendedProduct(*ancestor) {
+		if _, ok := seen[ancestor.Id.Value]; ok {
+			return
+		}
+		seen[ancestor.Id.Value] = struct{}{}
+		for _, f := range ancestor.Fields {
+			if fieldWireName(f) == tag {
+				t.addError(fmt.Sprintf("The field %s of %s has the same key as the tag of %s", fieldName(f), ancestor.Id.Value, sumId), location)
+				return
+			}
+		}
+	}
+}
+
+// resolveProduct returns the definition of the product a type is, following
+// aliases and newtypes, or nil if it is not a product.
+func (t *translate) resolveProduct(ty ast.Type) *st.Product {
+	if ty.TypeIdent == nil {
+		return nil
+	}
+	id := ty.TypeIdent.Id
+	seen := make(map[string]struct{})
+	for {
+		if _, ok := t.typeParams[id]; ok {
+			return nil
+		}
+		if _, ok := seen[id]; ok {
+			return nil
+		}
+		seen[id] = struct{}{}
+		sym, ok := t.symbols.getSymbol(id)
+		if !ok {
+			return nil
+		}
+		if sym.product != nil {
+			return sym.product
+		}
+		if sym.target == nil || sym.target.TypeIdent == nil {
+			return nil
+		}
+		id = sym.target.TypeIdent.Id.Value
+	}
+}
+
+// fieldWireName returns the key of a field in JSON.
+func fieldWireName(f st.Field) string {
+	if f.FieldFull != nil && f.FieldFull.JsonName != nil {
+		return f.FieldFull.JsonName.Value
+	}
+	return fieldName(f)
+}
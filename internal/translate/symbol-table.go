@@ -1,5 +1,7 @@
 package translate
 
+import "github.com/brahms116/between/internal/st"
+
 type symbolType string
 
 const (
@@ -7,28 +9,47 @@ const (
 	symbolTypeProduct   symbolType = "product"
 	symbolTypeSum       symbolType = "sum"
 	symbolTypeSumString symbolType = "sum_string"
+	symbolTypeSumInt    symbolType = "sum_int"
+	symbolTypeAlias     symbolType = "alias"
+	symbolTypeNewType   symbolType = "new_type"
+	symbolTypeExtern    symbolType = "extern"
 )
 
-type symbolTable map[string]symbolType
+type symbol struct {
+	typ symbolType
+	// path of the file the symbol is defined in, empty for primitives
+	path string
+	// names of the type parameters of generic types
+	params []string
+	// the type aliases and newtypes are defined as
+	target *st.Type
+	// the definition of products, to find the fields they extend
+	product *st.Product
+	// the definitions of sumstrs and sumints, to find their variants
+	sumStr *st.SumStr
+	sumInt *st.SumInt
+}
+
+type symbolTable map[string]symbol
 
 func newSymbolTable() symbolTable {
 	st := make(symbolTable)
 	for t, _ := range PrimitiveTypes {
-		st[t] = symbolTypePrimitive
+		st[t] = symbol{typ: symbolTypePrimitive}
 	}
 	return st
 }
 
-func (st symbolTable) addSymbol(name string, typ symbolType) bool {
+func (st symbolTable) addSymbol(name string, sym symbol) bool {
   _, ok := st[name]
   if ok {
     return false
   }
-	st[name] = typ
+	st[name] = sym
   return true
 }
 
-func (st symbolTable) getSymbol(name string) (symbolType, bool) {
-	typ, ok := st[name]
-	return typ, ok
+func (st symbolTable) getSymbol(name string) (symbol, bool) {
+	sym, ok := st[name]
+	return sym, ok
 }
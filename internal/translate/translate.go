@@ -2,6 +2,8 @@ package translate
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/brahms116/between/internal/ast"
@@ -13,29 +15,74 @@ import (
 Errors:
 
 Unknown type
+Wrong number of type arguments
+Type arguments on a type parameter
+Duplicated type parameter
+Type parameter shadowing a type
+Map key which is not a Str or sumstr
+Optional alias or newtype
+Default value which does not match the type of its field, or on an optional or nullable field
+Unknown, duplicated or misplaced attribute, or one with invalid arguments
+Field constraint on the wrong kind of field, or with an invalid value
+Type from a file which is not imported
+Missing imported file
+Import cycle
 Duplicated type definition
 Duplicated field
 Duplicated sumstr variant
-Sum variants cannot be optional
+Fields, sum variants or sumstr variants with the same JSON name
+Duplicated sumint variant or value
+Non integer sumint value
+Sum variants cannot be optional or nullable
+Extending something other than a prod
+Extends cycle
+Type which contains itself by value, or alias cycle
+Type or type parameter named with a word reserved in a target being generated
+Names generated from fields, variants or types which collide, or are reserved, in a target being generated
+Field which is optional and nullable when generating Java
+Extern with an unknown or duplicated target, or without a mapping for a target being generated
+Invalid sum encoding, or internally tagged sum variant which is not a prod or collides with the tag
 
 Warnings:
 non-camelCase fieldNames
-non-PascalCase typeNames
+non-PascalCase typeNames and variantNames
+JSON names of fields or variants which differ only in case
 */
 
 var PrimitiveTypes = map[string]struct{}{
-	"Float":  {},
-	"Str":    {},
-	"Bool":   {},
-	"Int":    {},
-	"Any":    {},
-	"Object": {},
-	"Date":   {},
+	"Float":    {},
+	"Float64":  {},
+	"Str":      {},
+	"Bool":     {},
+	"Int":      {},
+	"Int32":    {},
+	"Int64":    {},
+	"Any":      {},
+	"Object":   {},
+	"Decimal":  {},
+	"UUID":     {},
+	"Bytes":    {},
+	"Date":     {},
+	"DateTime": {},
+	"Duration": {},
 }
 
+// Severity is how serious a TypeError is, code is still generated when there
+// are only warnings.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
 type TypeError struct {
 	Message  string
 	Location lex.Location
+	// Path of the file the error occurred in, empty when translating a single
+	// file with Translate.
+	Path     string
+	Severity Severity
 }
 
 func (e TypeError) LspMessage() string {
@@ -43,56 +90,167 @@ func (e TypeError) LspMessage() string {
 }
 
 func (e TypeError) Error() string {
-	return fmt.Sprintf("Type error at %s: %s", e.Location.Start.String(), e.Message)
+	kind := "Type error"
+	if e.Severity == SeverityWarning {
+		kind = "Warning"
+	}
+	if e.Path != "" {
+		return fmt.Sprintf("%s in %s at %s: %s", kind, e.Path, e.Location.Start.String(), e.Message)
+	}
+	return fmt.Sprintf("%s at %s: %s", kind, e.Location.Start.String(), e.Message)
 }
 
-func newTypeError(message string, loc lex.Location) TypeError {
+func newTypeError(message string, loc lex.Location, path string, severity Severity) TypeError {
 	return TypeError{
 		Message:  message,
 		Location: loc,
+		Path:     path,
+		Severity: severity,
 	}
 }
 
+// IsWarning reports whether err is a warning, rather than an error which stops
+// code from being generated.
+func IsWarning(err error) bool {
+	e, ok := err.(TypeError)
+	return ok && e.Severity == SeverityWarning
+}
+
+type importState int
+
+const (
+	importStateVisiting importState = iota + 1
+	importStateDone
+)
+
 type translate struct {
-	st                 []st.Definition
+	files map[string][]st.Definition
+	entry string
+	// files reachable from the entry, each file comes after the files it imports
+	order []string
+	// resolved paths of the files directly imported by each file
+	imports map[string][]string
+
+	// path of the file currently being translated
+	path string
+	// type parameters in scope of the definition currently being translated
+	typeParams         map[string]struct{}
 	usedPrimitiveTypes map[string]struct{}
-	errors             []error
-	symbols            symbolTable
+	// identifiers used from each imported file, in order of first use
+	usedImports map[string][]string
+
+	allUsedPrimitiveTypes map[string]struct{}
+	errors                []error
+	symbols               symbolTable
+	options               Options
 }
 
-func newTranslate(st []st.Definition) *translate {
+// Options configures a translation.
+type Options struct {
+	// Targets being generated, externs need a mapping for each of them
+	Targets []string
+}
+
+func newTranslate(entry string, files map[string][]st.Definition, options Options) *translate {
 	return &translate{
-		st:                 st,
-		usedPrimitiveTypes: make(map[string]struct{}),
-		symbols:            newSymbolTable(),
+		files:                 files,
+		entry:                 entry,
+		options:               options,
+		imports:               make(map[string][]string),
+		allUsedPrimitiveTypes: make(map[string]struct{}),
+		symbols:               newSymbolTable(),
 	}
 }
 
-func Translate(st []st.Definition) ([]ast.Definition, map[string]struct{}, []error) {
-	t := newTranslate(st)
+func Translate(definitions []st.Definition) ([]ast.Definition, map[string]struct{}, []error) {
+	files, usedPrimitiveTypes, errs := TranslateFiles("", map[string][]st.Definition{"": definitions}, Options{})
+	return ast.Definitions(files), usedPrimitiveTypes, errs
+}
+
+// TranslateFiles translates the file at entry and every file it transitively
+// imports, files are keyed by their cleaned path as returned by
+// parser.LexAndParseFiles. The translated files are returned in dependency
+// order, each file comes after the files it imports.
+func TranslateFiles(entry string, files map[string][]st.Definition, options Options) ([]ast.File, map[string]struct{}, []error) {
+	t := newTranslate(entry, files, options)
 	return t.translate()
 }
 
+func (t *translate) resolveImports(path string, states map[string]importState, stack []string) {
+	states[path] = importStateVisiting
+	stack = append(stack, path)
+	for _, d := range t.files[path] {
+		if d.Import == nil {
+			continue
+		}
+		t.path = path
+		importPath := d.Import.ResolvePath(path)
+		if _, ok := t.files[importPath]; !ok {
+			t.addError(fmt.Sprintf("Cannot find imported file %s", d.Import.Path.Value), d.Import.Path.Loc)
+			continue
+		}
+		if slices.Contains(t.imports[path], importPath) {
+			continue
+		}
+		t.imports[path] = append(t.imports[path], importPath)
+
+		switch states[importPath] {
+		case importStateVisiting:
+			cycle := append(slices.Clone(stack[slices.Index(stack, importPath):]), importPath)
+			t.addError(fmt.Sprintf("Import cycle: %s", strings.Join(cycle, " -> ")), d.Import.Path.Loc)
+		case importStateDone:
+		default:
+			t.resolveImports(importPath, states, stack)
+		}
+	}
+	states[path] = importStateDone
+	t.order = append(t.order, path)
+}
+
 func (t *translate) fillSymbolTable() {
-	for _, d := range t.st {
-		switch {
-		case d.Product != nil:
-			ok := t.symbols.addSymbol(d.Product.Id.Value, symbolTypeProduct)
-			if !ok {
-				t.duplicatedIdentifier(d.Product.Id.Value, d.Product.Id.Loc)
-			}
-		case d.Sum != nil:
-			ok := t.symbols.addSymbol(d.Sum.Id.Value, symbolTypeSum)
-			if !ok {
-				t.duplicatedIdentifier(d.Sum.Id.Value, d.Sum.Id.Loc)
+	for _, path := range t.order {
+		t.path = path
+		for _, d := range t.files[path] {
+			switch {
+			case d.Import != nil:
+			case d.Product != nil:
+				ok := t.symbols.addSymbol(d.Product.Id.Value, symbol{typ: symbolTypeProduct, path: path, params: typeParamNames(d.Product.TypeParams), product: d.Product})
+				if !ok {
+					t.duplicatedIdentifier(d.Product.Id.Value, d.Product.Id.Loc)
+				}
+			case d.Sum != nil:
+				ok := t.symbols.addSymbol(d.Sum.Id.Value, symbol{typ: symbolTypeSum, path: path, params: typeParamNames(d.Sum.TypeParams)})
+				if !ok {
+					t.duplicatedIdentifier(d.Sum.Id.Value, d.Sum.Id.Loc)
+				}
+			case d.SumStr != nil:
+				ok := t.symbols.addSymbol(d.SumStr.Id.Value, symbol{typ: symbolTypeSumString, path: path, sumStr: d.SumStr})
+				if !ok {
+					t.duplicatedIdentifier(d.SumStr.Id.Value, d.SumStr.Id.Loc)
+				}
+			case d.SumInt != nil:
+				ok := t.symbols.addSymbol(d.SumInt.Id.Value, symbol{typ: symbolTypeSumInt, path: path, sumInt: d.SumInt})
+				if !ok {
+					t.duplicatedIdentifier(d.SumInt.Id.Value, d.SumInt.Id.Loc)
+				}
+			case d.Alias != nil:
+				ok := t.symbols.addSymbol(d.Alias.Id.Value, symbol{typ: symbolTypeAlias, path: path, target: &d.Alias.Type})
+				if !ok {
+					t.duplicatedIdentifier(d.Alias.Id.Value, d.Alias.Id.Loc)
+				}
+			case d.NewType != nil:
+				ok := t.symbols.addSymbol(d.NewType.Id.Value, symbol{typ: symbolTypeNewType, path: path, target: &d.NewType.Type})
+				if !ok {
+					t.duplicatedIdentifier(d.NewType.Id.Value, d.NewType.Id.Loc)
+				}
+			case d.Extern != nil:
+				ok := t.symbols.addSymbol(d.Extern.Id.Value, symbol{typ: symbolTypeExtern, path: path})
+				if !ok {
+					t.duplicatedIdentifier(d.Extern.Id.Value, d.Extern.Id.Loc)
+				}
+			default:
+				panic("unreachable")
 			}
-		case d.SumStr != nil:
-			ok := t.symbols.addSymbol(d.SumStr.Id.Value, symbolTypeSumString)
-			if !ok {
-				t.duplicatedIdentifier(d.SumStr.Id.Value, d.SumStr.Id.Loc)
-			}
-		default:
-			panic("unreachable")
 		}
 	}
 }
@@ -110,29 +268,119 @@ func (t *translate) duplicatedSumStrVariant(variantName string, location lex.Loc
 	t.addError(msg, location)
 }
 
+func (t *translate) duplicatedSumIntVariant(variantName string, location lex.Location) {
+	msg := fmt.Sprintf("Duplicated sumint variant: %s", variantName)
+	t.addError(msg, location)
+}
+
+func (t *translate) duplicatedSumIntValue(value int64, variantName string, location lex.Location) {
+	msg := fmt.Sprintf("Duplicated sumint value: %d, already used by %s", value, variantName)
+	t.addError(msg, location)
+}
+
 func (t *translate) duplicatedIdentifier(identifier string, location lex.Location) {
 	msg := fmt.Sprintf("Duplicated identifier: %s", identifier)
 	if _, ok := PrimitiveTypes[identifier]; ok {
 		msg = fmt.Sprintf("Cannot redefine primitive type: %s", identifier)
+	} else if sym, ok := t.symbols.getSymbol(identifier); ok && sym.path != t.path {
+		msg = fmt.Sprintf("Duplicated identifier: %s, already defined in %s", identifier, sym.path)
 	}
 	t.addError(msg, location)
 }
 
 func (t *translate) addError(message string, location lex.Location) {
-	t.errors = append(t.errors, newTypeError(message, location))
+	t.errors = append(t.errors, newTypeError(message, location, t.path, SeverityError))
+}
+
+func (t *translate) addWarning(message string, location lex.Location) {
+	t.errors = append(t.errors, newTypeError(message, location, t.path, SeverityWarning))
 }
 
-func (t *translate) translate() ([]ast.Definition, map[string]struct{}, []error) {
-	var res []ast.Definition
+// checkTypeReference reports references to unknown types, to types defined in
+// files which are not imported and with the wrong number of type arguments,
+// and records the identifiers used from imported files.
+func (t *translate) checkTypeReference(id string, typeArgCount int, location lex.Location) {
+	if _, ok := t.typeParams[id]; ok {
+		if typeArgCount > 0 {
+			t.addError(fmt.Sprintf("Type parameter %s cannot take type arguments", id), location)
+		}
+		return
+	}
+	sym, ok := t.symbols.getSymbol(id)
+	if !ok {
+		t.addError(fmt.Sprintf("Unknown type %s", id), location)
+		return
+	}
+	if typeArgCount != len(sym.params) {
+		t.addError(fmt.Sprintf("Type %s expects %d type arguments, got %d", id, len(sym.params), typeArgCount), location)
+	}
+	if sym.typ == symbolTypePrimitive || sym.path == t.path {
+		return
+	}
+	if !slices.Contains(t.imports[t.path], sym.path) {
+		t.addError(fmt.Sprintf("Type %s is defined in %s, which is not imported", id, sym.path), location)
+		return
+	}
+	if !slices.Contains(t.usedImports[sym.path], id) {
+		t.usedImports[sym.path] = append(t.usedImports[sym.path], id)
+	}
+}
+
+func (t *translate) translate() ([]ast.File, map[string]struct{}, []error) {
+	t.resolveImports(t.entry, make(map[string]importState), nil)
 	t.fillSymbolTable()
-	for _, d := range t.st {
-		def := t.translateDefinition(d)
-		res = append(res, def)
+
+	var res []ast.File
+	for _, path := range t.order {
+		res = append(res, t.translateFile(path))
+	}
+	t.checkRecursiveTypes()
+	fillInheritedFields(res)
+	t.checkGeneratedNames(res)
+	return res, t.allUsedPrimitiveTypes, t.errors
+}
+
+func (t *translate) translateFile(path string) ast.File {
+	t.path = path
+	t.usedPrimitiveTypes = make(map[string]struct{})
+	t.usedImports = make(map[string][]string)
+
+	var definitions []ast.Definition
+	for _, d := range t.files[path] {
+		if d.Import != nil {
+			continue
+		}
+		definitions = append(definitions, t.translateDefinition(d))
+	}
+
+	var imports []ast.Import
+	for _, importPath := range t.imports[path] {
+		ids, ok := t.usedImports[importPath]
+		if !ok {
+			continue
+		}
+		imports = append(imports, ast.Import{
+			Path: importPath,
+			Ids:  ids,
+		})
+	}
+
+	for p := range t.usedPrimitiveTypes {
+		t.allUsedPrimitiveTypes[p] = struct{}{}
+	}
+
+	return ast.File{
+		Path:               path,
+		Imports:            imports,
+		Definitions:        definitions,
+		UsedPrimitiveTypes: t.usedPrimitiveTypes,
 	}
-	return res, t.usedPrimitiveTypes, t.errors
 }
 
 func (t *translate) translateDefinition(d st.Definition) ast.Definition {
+	t.typeParams = nil
+	t.checkReservedName(definitionId(d))
+	t.checkTypeNameCase(definitionId(d))
 	if d.Product != nil {
 		prod := t.translateProduct(*d.Product)
 		return ast.Definition{Product: &prod}
@@ -145,6 +393,42 @@ func (t *translate) translateDefinition(d st.Definition) ast.Definition {
 		sumStr := t.translateSumStr(*d.SumStr)
 		return ast.Definition{SumStr: &sumStr}
 	}
+	if d.SumInt != nil {
+		sumInt := t.translateSumInt(*d.SumInt)
+		return ast.Definition{SumInt: &sumInt}
+	}
+	if d.Alias != nil {
+		alias := t.translateAlias(*d.Alias)
+		return ast.Definition{Alias: &alias}
+	}
+	if d.NewType != nil {
+		newType := t.translateNewType(*d.NewType)
+		return ast.Definition{NewType: &newType}
+	}
+	if d.Extern != nil {
+		extern := t.translateExtern(*d.Extern)
+		return ast.Definition{Extern: &extern}
+	}
+	panic("unreachable")
+}
+
+func definitionId(d st.Definition) lex.Token {
+	switch {
+	case d.Product != nil:
+		return d.Product.Id
+	case d.Sum != nil:
+		return d.Sum.Id
+	case d.SumStr != nil:
+		return d.SumStr.Id
+	case d.SumInt != nil:
+		return d.SumInt.Id
+	case d.Alias != nil:
+		return d.Alias.Id
+	case d.NewType != nil:
+		return d.NewType.Id
+	case d.Extern != nil:
+		return d.Extern.Id
+	}
 	panic("unreachable")
 }
 
@@ -153,12 +437,13 @@ func (t *translate) translateType(ty st.Type) ast.Type {
 		list := t.translateList(*ty.List)
 		return ast.Type{List: &list}
 	}
+	if ty.Map != nil {
+		m := t.translateMap(*ty.Map)
+		return ast.Type{Map: &m}
+	}
 	if ty.TypeIdent != nil {
 		ti := t.translateTypeIdent(*ty.TypeIdent)
-
-		if _, ok := t.symbols.getSymbol(ti.Id); !ok {
-			t.addError(fmt.Sprintf("Unknown type %s", ti.Id), ty.TypeIdent.Id.Loc)
-		}
+		t.checkTypeReference(ti.Id, len(ti.TypeArgs), ty.TypeIdent.Id.Loc)
 
 		return ast.Type{TypeIdent: &ti}
 	}
@@ -170,26 +455,80 @@ func (t *translate) translateTypeIdent(ti st.TypeIdent) ast.TypeIdent {
 		t.usedPrimitiveTypes[ti.Id.Value] = struct{}{}
 	}
 
+	var typeArgs []ast.Type
+	if ti.TypeArgs != nil {
+		for _, arg := range ti.TypeArgs.Args {
+			typeArgs = append(typeArgs, t.translateType(arg))
+			t.checkInt64TypeArg(arg)
+		}
+	}
+
 	return ast.TypeIdent{
 		Id:       ti.Id.Value,
-		Nullable: ti.Nullable != nil,
+		TypeArgs: typeArgs,
+		Optional: ti.Optional != nil,
 	}
 }
 
 func (t *translate) translateList(l st.List) ast.List {
 	ty := t.translateType(l.Type)
 	return ast.List{
-		Nullable: l.Nullable != nil,
+		Optional: l.Optional != nil,
 		Type:     ty,
 	}
 }
 
-func (t *translate) translateField(f st.Field, existingFields map[string]struct{}) ast.Field {
+func (t *translate) translateMap(m st.Map) ast.Map {
+	key := t.translateType(m.Key)
+	value := t.translateType(m.Value)
+	if !t.isValidMapKey(key) {
+		t.addError("Map keys must be a Str or a sumstr, and cannot be optional", m.Key.Loc())
+	}
+	return ast.Map{
+		Optional: m.Optional != nil,
+		Key:      key,
+		Value:    value,
+	}
+}
+
+// isValidMapKey reports whether values of the type are always strings, which is
+// required for the keys of JSON objects.
+func (t *translate) isValidMapKey(key ast.Type) bool {
+	if key.TypeIdent == nil || key.IsOptional() {
+		return false
+	}
+	if _, ok := t.typeParams[key.TypeIdent.Id]; ok {
+		return false
+	}
+	return t.isStringType(key.TypeIdent.Id, make(map[string]struct{}))
+}
+
+// isStringType reports whether the named type is a Str or a sumstr, or is an
+// alias or newtype of one.
+func (t *translate) isStringType(id string, seen map[string]struct{}) bool {
+	sym, ok := t.symbols.getSymbol(id)
+	if !ok {
+		// Unknown types are already reported
+		return true
+	}
+	if sym.target != nil {
+		if _, ok := seen[id]; ok {
+			return false
+		}
+		seen[id] = struct{}{}
+		target := sym.target.TypeIdent
+		return target != nil && target.Optional == nil && target.TypeArgs == nil && t.isStringType(target.Id.Value, seen)
+	}
+	return id == "Str" || sym.typ == symbolTypeSumString
+}
+
+func (t *translate) translateField(f st.Field, existingFields map[string]struct{}, placement attributePlacement) ast.Field {
 	if f.FieldFull != nil {
 		if _, ok := existingFields[f.FieldFull.Id.Value]; ok {
 			t.duplicatedField(f.FieldFull.Id.Value, true, f.FieldFull.Id.Loc)
 		}
 		existingFields[f.FieldFull.Id.Value] = struct{}{}
+		t.checkFieldNameCase(f.FieldFull.Id)
 
 		var jsonName *string
 		if f.FieldFull.JsonName != nil {
@@ -197,11 +536,25 @@ func (t *translate) translateField(f st.Field, existingFields map[string]struct{
 		}
 
 		ty := t.translateType(f.FieldFull.Type)
+		if f.FieldFull.Optional != nil {
+			ty = optionalType(ty)
+		}
+		if f.FieldFull.Null != nil {
+			ty = nullableType(ty)
+		}
+		t.checkOptionalNullable(ty, f.FieldFull.Id.Loc)
+		attributes := t.translateAttributes(f.FieldFull.Attributes, placement)
+		constraints := t.translateConstraints(f.FieldFull.Type, attributes)
+		fieldDefault := t.translateDefault(f.FieldFull.Default, f.FieldFull.Id.Value, f.FieldFull.Type, ty)
 
 		return ast.Field{
-			Id:       f.FieldFull.Id.Value,
-			JsonName: jsonName,
-			Type:     ty,
+			Doc:         translateDoc(f.FieldFull.Doc),
+			Id:          f.FieldFull.Id.Value,
+			JsonName:    jsonName,
+			Type:        ty,
+			Constraints: constraints,
+			Default:     fieldDefault,
+			Attributes:  attributes,
 		}
 	}
 	if f.FieldShort != nil {
@@ -212,64 +565,193 @@ func (t *translate) translateField(f st.Field, existingFields map[string]struct{
 		}
 		existingFields[id] = struct{}{}
 
-		if _, ok := t.symbols.getSymbol(f.FieldShort.Id.Value); !ok {
-			t.addError(fmt.Sprintf("Unknown type %s", f.FieldShort.Id.Value), f.FieldShort.Id.Loc)
-		}
+		t.checkTypeReference(f.FieldShort.Id.Value, 0, f.FieldShort.Id.Loc)
 
 		ty := ast.Type{
 			TypeIdent: &ast.TypeIdent{
 				Id:       f.FieldShort.Id.Value,
-				Nullable: f.FieldShort.Nullable != nil,
+				Optional: f.FieldShort.Optional != nil,
+				Nullable: f.FieldShort.Null != nil,
+			},
+		}
+		t.checkOptionalNullable(ty, f.FieldShort.Id.Loc)
+		stType := st.Type{
+			TypeIdent: &st.TypeIdent{
+				Id:       f.FieldShort.Id,
+				Optional: f.FieldShort.Optional,
 			},
 		}
 		return ast.Field{
-			Id:       id,
-			JsonName: nil,
-			Type:     ty,
+			Doc:        translateDoc(f.FieldShort.Doc),
+			Id:         id,
+			JsonName:   nil,
+			Type:       ty,
+			Default:    t.translateDefault(f.FieldShort.Default, id, stType, ty),
+			Attributes: t.translateAttributes(f.FieldShort.Attributes, placement),
 		}
 	}
 	panic("unreachable")
 }
 
+// checkOptionalNullable reports fields which are optional and nullable when
+// generating Java, as its records cannot tell an absent field from a null one.
+func (t *translate) checkOptionalNullable(ty ast.Type, loc lex.Location) {
+	if ty.IsOptional() && ty.IsNullable() && slices.Contains(t.options.Targets, "java") {
+		t.addError("Fields cannot be both optional and nullable in Java, which cannot tell an absent field from a null one", loc)
+	}
+}
+
+// checkInt64TypeArg reports Int64s given as type arguments when generating Java
+// or C#, which write Int64s as strings through annotations on the fields
+// holding them, so the Int64s of fields of a type parameter would be numbers.
+func (t *translate) checkInt64TypeArg(arg st.Type) {
+	if !t.holdsInt64(arg, make(map[string]struct{})) {
+		return
+	}
+	for _, target := range []string{"java", "cs"} {
+		if slices.Contains(t.options.Targets, target) {
+			t.addError(fmt.Sprintf("Type arguments cannot be or hold an Int64 in %s, which would write it as a number", targetNames[target]), arg.Loc())
+		}
+	}
+}
+
+// holdsInt64 reports whether a type is an Int64, or a list, map or alias of
+// one. Newtypes of Int64s write them as strings themselves.
+func (t *translate) holdsInt64(ty st.Type, seen map[string]struct{}) bool {
+	if ty.List != nil {
+		return t.holdsInt64(ty.List.Type, seen)
+	}
+	if ty.Map != nil {
+		return t.holdsInt64(ty.Map.Value, seen)
+	}
+	if ty.TypeIdent == nil {
+		return false
+	}
+	id := ty.TypeIdent.Id.Value
+	if _, ok := t.typeParams[id]; ok {
+		return false
+	}
+	if id == "Int64" {
+		return true
+	}
+	if _, ok := seen[id]; ok {
+		return false
+	}
+	seen[id] = struct{}{}
+	sym, ok := t.symbols.getSymbol(id)
+	if !ok || sym.typ != symbolTypeAlias || sym.target == nil {
+		return false
+	}
+	return t.holdsInt64(*sym.target, seen)
+}
+
+func typeParamNames(tp *st.TypeParams) []string {
+	if tp == nil {
+		return nil
+	}
+	var names []string
+	for _, param := range tp.Params {
+		names = append(names, param.Value)
+	}
+	return names
+}
+
+// translateTypeParams brings the type parameters of a definition into scope.
+func (t *translate) translateTypeParams(tp *st.TypeParams) []string {
+	t.typeParams = make(map[string]struct{})
+	if tp == nil {
+		return nil
+	}
+	var params []string
+	for _, param := range tp.Params {
+		if _, ok := t.typeParams[param.Value]; ok {
+			t.addError(fmt.Sprintf("Duplicated type parameter: %s", param.Value), param.Loc)
+			continue
+		}
+		if _, ok := t.symbols.getSymbol(param.Value); ok {
+			t.addError(fmt.Sprintf("Type parameter %s shadows the type %s", param.Value, param.Value), param.Loc)
+		}
+		t.checkReservedName(param)
+		t.typeParams[param.Value] = struct{}{}
+		params = append(params, param.Value)
+	}
+	return params
+}
+
 func (t *translate) translateProduct(p st.Product) ast.Product {
+	typeParams := t.translateTypeParams(p.TypeParams)
 	var fields []ast.Field
 	fieldNames := make(map[string]struct{})
+	var extends *ast.TypeIdent
+	if p.Extends != nil {
+		extends = t.translateExtends(p, fieldNames)
+	}
 	for _, f := range p.Fields {
-		field := t.translateField(f, fieldNames)
+		field := t.translateField(f, fieldNames, placementField)
 		fields = append(fields, field)
 	}
+	wireNames := t.productWireNames(p)
+	t.checkWireNames(wireNames, "name")
+	t.checkJsonNameCase(wireNames)
 	return ast.Product{
-		Id:     p.Id.Value,
-		Fields: fields,
+		Doc:        translateDoc(p.Doc),
+		Id:         p.Id.Value,
+		TypeParams: typeParams,
+		Extends:    extends,
+		Fields:     fields,
+		Attributes: t.translateAttributes(p.Attributes, placementProduct),
 	}
 }
 
 func (t *translate) translateSum(s st.Sum) ast.Sum {
+	typeParams := t.translateTypeParams(s.TypeParams)
+	attributes := t.translateAttributes(s.Attributes, placementSum)
+	encoding, tag, content := t.translateSumEncoding(attributes)
 	var variants []ast.Field
 	existingFieldNames := make(map[string]struct{})
 	for _, v := range s.Variants {
-		variant := t.translateField(v, existingFieldNames)
-		if variant.Type.IsNullable() {
+		variant := t.translateField(v, existingFieldNames, placementSumVariant)
+		if encoding == ast.SumEncodingInternal {
+			t.checkInternallyTaggedVariant(s.Id.Value, tag, variant, v.Id().Loc)
+		}
+		if variant.Type.IsOptional() {
 			t.addError(fmt.Sprintf("Sum variant %s cannot be optional, sum variants cannot be optional.", variant.Id), v.Id().Loc)
 		}
+		if variant.Type.IsNullable() {
+			t.addError(fmt.Sprintf("Sum variant %s cannot be nullable, sum variants cannot be nullable.", variant.Id), v.Id().Loc)
+		}
 		variants = append(variants, variant)
 	}
+	wireNames := fieldWireNames(s.Variants, "", t.path)
+	t.checkWireNames(wireNames, "name")
+	t.checkJsonNameCase(wireNames)
 	return ast.Sum{
-		Id:       s.Id.Value,
-		Variants: variants,
+		Doc:        translateDoc(s.Doc),
+		Id:         s.Id.Value,
+		TypeParams: typeParams,
+		Encoding:   encoding,
+		Tag:        tag,
+		Content:    content,
+		Variants:   variants,
+		Attributes: attributes,
 	}
 }
 
 func (t *translate) translateSumStr(ss st.SumStr) ast.SumStr {
 	var variants []ast.SumStrVariant
 	existingVariants := make(map[string]struct{})
+	var wireNames []wireName
 	for _, v := range ss.Variants {
 		variant := t.translateSumStrVariant(v, existingVariants)
 		variants = append(variants, variant)
+		wireNames = append(wireNames, wireName{name: sumStrVariantWireName(v), id: v.Id.Value, path: t.path, loc: sumStrVariantWireNameLoc(v)})
 	}
+	t.checkWireNames(wireNames, "value")
 	return ast.SumStr{
-		Id:       ss.Id.Value,
-		Variants: variants,
+		Doc:        translateDoc(ss.Doc),
+		Id:         ss.Id.Value,
+		Variants:   variants,
+		Attributes: t.translateAttributes(ss.Attributes, placementSumStr),
 	}
 }
 
@@ -278,14 +760,100 @@ func (t *translate) translateSumStrVariant(ssv st.SumStrVariant, existingVariant
 		t.duplicatedSumStrVariant(ssv.Id.Value, ssv.Id.Loc)
 	}
 	existingVariants[ssv.Id.Value] = struct{}{}
+	t.checkVariantNameCase(ssv.Id)
 	var jsonName *string
 	if ssv.JsonName != nil {
 		jsonName = &ssv.JsonName.Value
 	}
 	return ast.SumStrVariant{
-		Id:       ssv.Id.Value,
-		JsonName: jsonName,
+		Doc:        translateDoc(ssv.Doc),
+		Id:         ssv.Id.Value,
+		JsonName:   jsonName,
+		Attributes: t.translateAttributes(ssv.Attributes, placementSumStrVariant),
+	}
+}
+
+func (t *translate) translateAlias(a st.Alias) ast.Alias {
+	ty := t.translateType(a.Type)
+	if ty.IsOptional() {
+		t.addError(fmt.Sprintf("The type of alias %s cannot be optional", a.Id.Value), a.Type.Loc())
+	}
+	return ast.Alias{
+		Doc:        translateDoc(a.Doc),
+		Id:         a.Id.Value,
+		Type:       ty,
+		Attributes: t.translateAttributes(a.Attributes, placementAlias),
+	}
+}
+
+func (t *translate) translateNewType(n st.NewType) ast.NewType {
+	ty := t.translateType(n.Type)
+	if ty.IsOptional() {
+		t.addError(fmt.Sprintf("The type of newtype %s cannot be optional", n.Id.Value), n.Type.Loc())
+	}
+	return ast.NewType{
+		Doc:        translateDoc(n.Doc),
+		Id:         n.Id.Value,
+		Type:       ty,
+		Attributes: t.translateAttributes(n.Attributes, placementNewType),
+	}
+}
+
+func (t *translate) translateSumInt(si st.SumInt) ast.SumInt {
+	var variants []ast.SumIntVariant
+	existingVariants := make(map[string]struct{})
+	existingValues := make(map[int64]string)
+	// Variants without a value take the one after the previous variant
+	var nextValue int64
+	for _, v := range si.Variants {
+		if _, ok := existingVariants[v.Id.Value]; ok {
+			t.duplicatedSumIntVariant(v.Id.Value, v.Id.Loc)
+		}
+		existingVariants[v.Id.Value] = struct{}{}
+		t.checkVariantNameCase(v.Id)
+
+		value := nextValue
+		valueLoc := v.Id.Loc
+		if v.Value != nil {
+			valueLoc = v.Value.Loc
+			parsed, err := strconv.ParseInt(v.Value.Value, 10, 64)
+			if err != nil {
+				t.addError(fmt.Sprintf("Sumint values must be integers, got %s", v.Value.Value), valueLoc)
+			}
+			value = parsed
+		}
+		if other, ok := existingValues[value]; ok {
+			t.duplicatedSumIntValue(value, other, valueLoc)
+		} else {
+			existingValues[value] = v.Id.Value
+		}
+		nextValue = value + 1
+
+		variants = append(variants, ast.SumIntVariant{
+			Doc:        translateDoc(v.Doc),
+			Id:         v.Id.Value,
+			Value:      value,
+			Attributes: t.translateAttributes(v.Attributes, placementSumIntVariant),
+		})
+	}
+	return ast.SumInt{
+		Doc:        translateDoc(si.Doc),
+		Id:         si.Id.Value,
+		Variants:   variants,
+		Attributes: t.translateAttributes(si.Attributes, placementSumInt),
+	}
+}
+
+// translateDoc turns doc comment tokens into lines of documentation, dropping
+// the conventional single space after the "///".
+func translateDoc(doc []lex.Token) []string {
+	var lines []string
+	for _, d := range doc {
+		line := strings.TrimRight(d.Value, " \t\r")
+		line = strings.TrimPrefix(line, " ")
+		lines = append(lines, line)
 	}
+	return lines
 }
 
 func lowerCaseFirstLetter(s string) string {
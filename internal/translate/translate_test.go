@@ -0,0 +1,636 @@
+package translate
+
+import (
+	"testing"
+
+	"github.com/brahms116/between/internal/ast"
+	"github.com/brahms116/between/internal/parser"
+	"github.com/brahms116/between/internal/st"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseFiles(t *testing.T, sources map[string]string) map[string][]st.Definition {
+	files := make(map[string][]st.Definition)
+	for path, source := range sources {
+		definitions, errs := parser.LexAndParse(source)
+		assert.Equal(t, 0, len(errs))
+		files[path] = definitions
+	}
+	return files
+}
+
+func errorMessages(errs []error) []string {
+	var messages []string
+	for _, err := range errs {
+		if !IsWarning(err) {
+			messages = append(messages, err.(TypeError).Message)
+		}
+	}
+	return messages
+}
+
+func warningMessages(errs []error) []string {
+	var messages []string
+	for _, err := range errs {
+		if IsWarning(err) {
+			messages = append(messages, err.(TypeError).Message)
+		}
+	}
+	return messages
+}
+
+func TestTranslateFiles(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"api.bt":    "import \"common.bt\"\nprod Order { shipTo Address, }",
+		"common.bt": "prod Address { street Str, }",
+	})
+	result, _, errs := TranslateFiles("api.bt", files, Options{})
+	assert.Equal(t, 0, len(errs))
+	assert.Equal(t, "common.bt", result[0].Path)
+	assert.Equal(t, "api.bt", result[1].Path)
+	assert.Equal(t, []string{"Address"}, result[1].Imports[0].Ids)
+}
+
+func TestTranslateFilesErrors(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"a.bt": "import \"b.bt\"\nprod A { c C, }",
+		"b.bt": "import \"a.bt\"\nimport \"c.bt\"\nimport \"d.bt\"\nprod B { a A, }",
+		"c.bt": "prod C { a Int, }",
+		"d.bt": "prod A { a Int, }",
+	})
+	_, _, errs := TranslateFiles("a.bt", files, Options{})
+	assert.Equal(t, []string{
+		"Import cycle: a.bt -> b.bt -> a.bt",
+		"Duplicated identifier: A, already defined in d.bt",
+		"Type C is defined in c.bt, which is not imported",
+	}, errorMessages(errs))
+}
+
+func TestTranslateGenerics(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `prod Page<T> { items []T, next Page<T>?, }
+prod Pair<K, K> { key K, }
+sum Result<Str> { ok Str, }
+prod Users {
+  page Page<Int>,
+  missing Page,
+  extra Page<Int, Str>,
+  unknown U,
+}`,
+	})
+	_, _, errs := TranslateFiles("", files, Options{})
+	assert.Equal(t, []string{
+		"Duplicated type parameter: K",
+		"Type parameter Str shadows the type Str",
+		"Type Page expects 1 type arguments, got 0",
+		"Type Page expects 1 type arguments, got 2",
+		"Unknown type U",
+	}, errorMessages(errs))
+}
+
+func TestTranslateMapKeys(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `sumstr Status { Active, }
+prod Box<T> {
+  byName {Str: Int},
+  byStatus {Status: []Str}?,
+  byInt {Int: Str},
+  byOptional {Str?: Str},
+  byList {[]Str: Str},
+  byParam {T: Str},
+}`,
+	})
+	_, _, errs := TranslateFiles("", files, Options{})
+	assert.Equal(t, []string{
+		"Map keys must be a Str or a sumstr, and cannot be optional",
+		"Map keys must be a Str or a sumstr, and cannot be optional",
+		"Map keys must be a Str or a sumstr, and cannot be optional",
+		"Map keys must be a Str or a sumstr, and cannot be optional",
+	}, errorMessages(errs))
+}
+
+func TestTranslatePrimitives(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `prod Payment {
+  id Int64 @min(1),
+  amount Decimal = "0.00",
+  count Int32 = 1,
+  at DateTime,
+  wait Duration,
+}`,
+	})
+	_, primitives, errs := TranslateFiles("", files, Options{})
+	assert.Equal(t, []string{
+		"@min can only be used on numbers",
+	}, errorMessages(errs))
+	for _, p := range []string{"Int64", "Decimal", "Int32", "DateTime", "Duration"} {
+		assert.Contains(t, primitives, p)
+	}
+}
+
+func TestTranslateSumInt(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `sumint Code { Ok 200, Created, Accepted 201, Half 0.5, Ok, }`,
+	})
+	result, _, errs := TranslateFiles("", files, Options{})
+	assert.Equal(t, []string{
+		"Duplicated sumint value: 201, already used by Created",
+		"Sumint values must be integers, got 0.5",
+		"Duplicated sumint variant: Ok",
+	}, errorMessages(errs))
+
+	var values []int64
+	for _, v := range result[0].Definitions[0].SumInt.Variants {
+		values = append(values, v.Value)
+	}
+	assert.Equal(t, []int64{200, 201, 201, 0, 1}, values)
+}
+
+func TestTranslateAliases(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `alias UserId Str
+newtype OrderId UserId
+alias Count Int
+alias MaybeId Str?
+prod Order {
+  byId {OrderId: Int},
+  byCount {Count: Int},
+}`,
+	})
+	_, _, errs := TranslateFiles("", files, Options{})
+	assert.Equal(t, []string{
+		"The type of alias MaybeId cannot be optional",
+		"Map keys must be a Str or a sumstr, and cannot be optional",
+	}, errorMessages(errs))
+}
+
+func TestTranslateConstraints(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `newtype Age Int
+prod User {
+  age Age @min(0) @max(150),
+  score Float? @min(0.5),
+  email Str @pattern("^.+@.+$") @maxLength(100),
+  tags []Str @maxItems(10),
+  name Str @min(1),
+  count Int @max(1.5),
+  nick Str @minLength(-1),
+  code Str @pattern("("),
+  size Int @min(0) @min(1),
+  other Str @unknown,
+}`,
+	})
+	result, _, errs := TranslateFiles("", files, Options{})
+	assert.Equal(t, []string{
+		"@min can only be used on numbers",
+		"The argument of @max must be an integer, as the field is an integer",
+		"The argument of @minLength must be a non negative integer",
+		"Invalid pattern: error parsing regexp: missing closing ): `(`",
+		"Duplicated attribute @min",
+		"Unknown attribute @unknown",
+	}, errorMessages(errs))
+	assert.Equal(t, []ast.Constraint{
+		{Name: ast.ConstraintMin, Value: "0"},
+		{Name: ast.ConstraintMax, Value: "150"},
+	}, result[0].Definitions[1].Product.Fields[0].Constraints)
+}
+
+func TestTranslateExtends(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `prod Entity<Id> {
+  id Id,
+  note Str?,
+}
+prod Named<Id> extends Entity<Id?> {
+  name Str,
+}
+prod Admin extends Named<Str> {
+  level Int,
+}
+prod Dup extends Admin {
+  name Str,
+  Status,
+}
+sumstr Status { A, }
+prod A extends B { x Int, }
+prod B extends A { y Int, }
+prod C extends Status { }
+prod D<T> extends T { }
+prod E extends Admin? { }`,
+	})
+	result, _, errs := TranslateFiles("", files, Options{})
+	assert.Equal(t, []string{
+		"Duplicated field: name",
+		"Extends cycle: A -> B -> A",
+		"Extends cycle: B -> A -> B",
+		"Prod C can only extend a prod, Status is not a prod",
+		"Prod D can only extend a prod, T is a type parameter",
+		"Prod E cannot extend an optional type",
+	}, errorMessages(errs))
+
+	admin := result[0].Definitions[2].Product
+	assert.Equal(t, "Named", admin.Extends.Id)
+	var inherited []string
+	for _, f := range admin.InheritedFields {
+		inherited = append(inherited, f.Id)
+	}
+	assert.Equal(t, []string{"id", "note", "name"}, inherited)
+	assert.Equal(t, ast.TypeIdent{Id: "Str", Optional: true}, *admin.InheritedFields[0].Type.TypeIdent)
+}
+
+func TestTranslateSumEncodings(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `prod Admin { level Int, }
+prod Tagged { kind "type" Str, }
+alias AdminAlias Admin
+sum Internal @tag("type") { Admin, AdminAlias, n Int, Tagged, }
+sum Adjacent @tag("kind", content="data") { Admin, n Int, }
+sum Untagged @untagged { Admin, n Int, }
+sum Same @tag("a", content="a") { Admin, }
+sum Both @tag("a") @untagged { Admin, }
+sum Bad @tag(1) @other { Admin, }
+sum Named @tag("a", other="b") { Admin, }`,
+	})
+	result, _, errs := TranslateFiles("", files, Options{})
+	assert.Equal(t, []string{
+		"Variant n of Internal must be a prod, as Internal is internally tagged",
+		"The field kind of Tagged has the same key as the tag of Internal",
+		"The tag and content keys of @tag must be different",
+		"A sum can only have one of @tag and @untagged",
+		"The argument of @tag must be a string",
+		"Unknown attribute @other",
+		"@tag does not take an argument named other",
+	}, errorMessages(errs))
+
+	var sums []ast.Sum
+	for _, d := range result[0].Definitions {
+		if d.Sum != nil {
+			sums = append(sums, *d.Sum)
+		}
+	}
+	assert.Equal(t, ast.SumEncodingInternal, sums[0].Encoding)
+	assert.Equal(t, "type", sums[0].Tag)
+	assert.Equal(t, ast.SumEncodingAdjacent, sums[1].Encoding)
+	assert.Equal(t, "data", sums[1].Content)
+	assert.Equal(t, ast.SumEncodingUntagged, sums[2].Encoding)
+}
+
+func TestTranslateAttributes(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `prod User @deprecated("Use Account") {
+  name Str @deprecated,
+  Status @deprecated("x", "y"),
+  age Int @min,
+  count Int @min("1"),
+  other Str @deprecated(reason="x"),
+}
+sumstr Status @untagged { Active @deprecated, }
+sumint Level { Low @min(1), }
+sum Data { n Int @max(1), s Str @deprecated, }
+alias Id Str @deprecated("Use Str")`,
+	})
+	result, _, errs := TranslateFiles("", files, Options{})
+	assert.Equal(t, []string{
+		"@deprecated takes at most one argument",
+		"@min takes exactly one argument",
+		"The argument of @min must be a number",
+		"@deprecated does not take an argument named reason",
+		"@untagged cannot be used on sumstrs",
+		"@min cannot be used on sumint variants",
+		"@max cannot be used on sum variants",
+	}, errorMessages(errs))
+
+	user := result[0].Definitions[0].Product
+	reason, ok := user.Attributes[0].Arg("", 0)
+	assert.True(t, ok)
+	assert.Equal(t, "Use Account", reason)
+	_, ok = user.Fields[0].Attributes.Get("deprecated")
+	assert.True(t, ok)
+}
+
+func TestTranslateDefaults(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `sumstr Status { Active "active", Done, }
+sumint Level { Low, High, }
+alias St Status
+prod User {
+  retries Int = 3,
+  status Status = Active,
+  st St = Done,
+  Level = High,
+  tags []Str = [],
+  name Str? = "x",
+  ratio Int = 0.5,
+  kind Status = Missing,
+  label Str = 1,
+  other Level = "High",
+  user Data = {},
+  on Bool = true,
+}
+prod Data {}`,
+	})
+	result, _, errs := TranslateFiles("", files, Options{})
+	assert.Equal(t, []string{
+		"The optional field name cannot have a default",
+		"The default of ratio must be an integer",
+		"Missing is not a variant of Status",
+		"The default of label must be a string",
+		"The default of other must be a variant of Level",
+		"The field user cannot have a default, only numbers, strings, bools, sumstrs, sumints, lists and maps can",
+	}, errorMessages(errs))
+
+	fields := result[0].Definitions[3].Product.Fields
+	assert.Equal(t, ast.Default{Kind: ast.DefaultNumber, Value: "3"}, *fields[0].Default)
+	assert.Equal(t, ast.Default{Kind: ast.DefaultSumStr, Value: "active", Enum: "Status", Variant: "Active"}, *fields[1].Default)
+	assert.Equal(t, ast.Default{Kind: ast.DefaultSumStr, Value: "Done", Enum: "Status", Variant: "Done"}, *fields[2].Default)
+	assert.Equal(t, ast.Default{Kind: ast.DefaultSumInt, Value: "1", Enum: "Level", Variant: "High"}, *fields[3].Default)
+	assert.Equal(t, ast.Default{Kind: ast.DefaultEmptyList}, *fields[4].Default)
+	assert.Equal(t, ast.Default{Kind: ast.DefaultBool, Value: "true"}, *fields[11].Default)
+}
+
+func TestTranslateOptionalAndNullable(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `sumstr Status { A, B, }
+prod Patch {
+  name? Str,
+  nick Str | null,
+  bio? Str | null,
+  age Int?,
+  Status | null,
+  count Int | null = 1,
+}
+sum Data { name Str | null, }`,
+	})
+	result, _, errs := TranslateFiles("", files, Options{})
+	assert.Equal(t, []string{
+		"The nullable field count cannot have a default",
+		"Sum variant name cannot be nullable, sum variants cannot be nullable.",
+	}, errorMessages(errs))
+
+	fields := result[0].Definitions[1].Product.Fields
+	var flags [][2]bool
+	for _, f := range fields {
+		flags = append(flags, [2]bool{f.Type.IsOptional(), f.Type.IsNullable()})
+	}
+	assert.Equal(t, [][2]bool{
+		{true, false},
+		{false, true},
+		{true, true},
+		{true, false},
+		{false, true},
+		{false, true},
+	}, flags)
+}
+
+func TestTranslateExterns(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `extern Money { go "github.com/shopspring/decimal.Decimal", ts "string" }
+extern Big { go "*math/big.Int", go "int64", rust "i64", }
+extern Empty { ts "" }
+prod Order {
+  total Money,
+  count Big = 1,
+}
+prod Sub extends Money {}`,
+	})
+	result, _, errs := TranslateFiles("", files, Options{Targets: []string{"go", "ts"}})
+	assert.Equal(t, []string{
+		"Duplicated mapping for go",
+		"Unknown target rust, expected one of go, ts, rs, py, kt, java, swift, cs",
+		"Extern Big has no mapping for ts",
+		"The ts type of Empty cannot be empty",
+		"Extern Empty has no mapping for go",
+		"Extern Empty has no mapping for ts",
+		"The field count cannot have a default, only numbers, strings, bools, sumstrs, sumints, lists and maps can",
+		"Prod Sub can only extend a prod, Money is not a prod",
+	}, errorMessages(errs))
+
+	money := result[0].Definitions[0].Extern
+	assert.Equal(t, map[string]string{
+		"go": "github.com/shopspring/decimal.Decimal",
+		"ts": "string",
+	}, money.Targets)
+}
+
+func TestTranslateGoExternTypes(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `extern Node { go "gopkg.in/yaml.v3.Node", }
+extern Id { go "github.com/google/uuid/v5.UUID", }
+extern Ids { go "[]*github.com/google/uuid.UUID", }
+extern Raw { go "[]byte", }
+extern Yaml { go "gopkg.in/yaml.v3", }
+extern Lower { go "math/big.int", }
+extern Generic { go "github.com/example/set.Set[int]", }`,
+	})
+	_, _, errs := TranslateFiles("", files, Options{Targets: []string{"go"}})
+	assert.Equal(t, []string{
+		"The go type of Yaml must be a type, qualified by the import path of its package when it is not predeclared, such as github.com/shopspring/decimal.Decimal",
+		"The go type of Lower must be a type, qualified by the import path of its package when it is not predeclared, such as github.com/shopspring/decimal.Decimal",
+		"The go type of Generic must be a type, qualified by the import path of its package when it is not predeclared, such as github.com/shopspring/decimal.Decimal",
+	}, errorMessages(errs))
+}
+
+func TestTranslateRecursiveTypes(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `prod Node { next Node, }
+prod List { next List?, items []List, byName {Str: List}, parent List | null, }
+prod A { b B, }
+prod B { a A, }
+prod Box<T> { value T, }
+prod Pair<T> { first T?, }
+prod Boxed { inner Box<Boxed>, }
+prod Paired { inner Pair<Paired>, }
+prod Nested { inner Box<Box<Int>>, }
+prod Outer<T> { inner Outer<[]T>, }
+sum Tree { Leaf, Tree, }
+prod Leaf { tree Tree, }
+alias Self Self
+alias Items []Items
+newtype Wrapped Wrapped
+newtype Children []Children
+prod Base { child Child, }
+prod Child extends Base {}
+prod Loop extends Loop {}`,
+	})
+	_, _, errs := TranslateFiles("", files, Options{})
+	assert.Equal(t, []string{
+		"Extends cycle: Loop -> Loop",
+		"Alias cycle: Self -> Self",
+		"Alias cycle: Items -> Items",
+		"Recursive type Node contains itself: Node -> Node, make a field on the cycle optional, nullable, a list or a map",
+		"Recursive type A contains itself: A -> B -> A, make a field on the cycle optional, nullable, a list or a map",
+		"Recursive type Boxed contains itself: Boxed -> Box -> Boxed, make a field on the cycle optional, nullable, a list or a map",
+		"Recursive type Outer contains itself: Outer -> Outer, make a field on the cycle optional, nullable, a list or a map",
+		"Recursive type Wrapped contains itself: Wrapped -> Wrapped, make a field on the cycle optional, nullable, a list or a map",
+		"Recursive type Base contains itself: Base -> Child -> Base, make a field on the cycle optional, nullable, a list or a map",
+	}, errorMessages(errs))
+}
+
+func TestTranslateReservedNames(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `prod type { interface Str, delete Str, }
+sumstr string { A, }
+sum Result<error> { Ok Str, }
+alias Record {Str: Str}
+prod User { type Str, default Int, }`,
+	})
+	_, _, errs := TranslateFiles("", files, Options{Targets: []string{"go", "ts"}})
+	assert.Equal(t, []string{
+		"The name type is reserved in Go",
+		"The name string is reserved in Go",
+		"The name string is reserved in TypeScript",
+		"The name error is reserved in Go",
+		"The name Record is reserved in TypeScript",
+	}, errorMessages(errs))
+
+	_, _, errs = TranslateFiles("", files, Options{Targets: []string{"ts"}})
+	assert.Equal(t, []string{
+		"The name string is reserved in TypeScript",
+		"The name Record is reserved in TypeScript",
+	}, errorMessages(errs))
+}
+
+func TestTranslateOptionalNullableJava(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `prod Patch { bio? Str | null, name? Str, nick Str | null, }`,
+	})
+	_, _, errs := TranslateFiles("", files, Options{Targets: []string{"java"}})
+	assert.Equal(t, []string{
+		"Fields cannot be both optional and nullable in Java, which cannot tell an absent field from a null one",
+	}, errorMessages(errs))
+	assert.Equal(t, "Type error at (Row 1, Col 14): Fields cannot be both optional and nullable in Java, which cannot tell an absent field from a null one", errs[0].Error())
+
+	_, _, errs = TranslateFiles("", files, Options{Targets: []string{"kt", "go"}})
+	assert.Equal(t, 0, len(errorMessages(errs)))
+}
+
+func TestTranslateInt64TypeArgs(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `prod Page<T> { items []T, }
+alias Id Int64
+newtype Count Int64
+prod R {
+  a Page<Int64>,
+  b Page<[]Int64>,
+  c Page<{Str: Id}>,
+  d Page<Count>,
+  e Page<Int>,
+  f []Int64,
+}`,
+	})
+	_, _, errs := TranslateFiles("", files, Options{Targets: []string{"java"}})
+	assert.Equal(t, []string{
+		"Type arguments cannot be or hold an Int64 in Java, which would write it as a number",
+		"Type arguments cannot be or hold an Int64 in Java, which would write it as a number",
+		"Type arguments cannot be or hold an Int64 in Java, which would write it as a number",
+	}, errorMessages(errs))
+
+	_, _, errs = TranslateFiles("", files, Options{Targets: []string{"cs"}})
+	assert.Equal(t, []string{
+		"Type arguments cannot be or hold an Int64 in C#, which would write it as a number",
+		"Type arguments cannot be or hold an Int64 in C#, which would write it as a number",
+		"Type arguments cannot be or hold an Int64 in C#, which would write it as a number",
+	}, errorMessages(errs))
+
+	_, _, errs = TranslateFiles("", files, Options{Targets: []string{"go", "ts", "kt"}})
+	assert.Equal(t, 0, len(errorMessages(errs)))
+}
+
+func TestTranslateGeneratedNames(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `sum S { which Str, other Int, }
+sum T { which Str, Which Int, }
+prod TVariant {}
+prod User { id Str, Id Int, userId Str, userID Str, }
+prod Checked { name Str @minLength(1), validate Str, }
+sumstr Level { a, A, }`,
+	})
+	_, _, errs := TranslateFiles("", files, Options{Targets: []string{"go"}})
+	assert.Equal(t, []string{
+		"The name Which of variant which of S is reserved in Go",
+		"The name TVariant of sum T in Go collides with prod TVariant at (Row 3, Col 6)",
+		"The name Which of variant which of T is reserved in Go",
+		"The name Which of variant Which of T is reserved in Go",
+		"The name TVariant_Which of variant Which of T in Go collides with variant which of T at (Row 2, Col 9)",
+		"The name Id of field Id of User in Go collides with field id of User at (Row 4, Col 13)",
+		"The name Validate of field validate of Checked is reserved in Go",
+	}, errorMessages(errs))
+
+	_, _, errs = TranslateFiles("", files, Options{Targets: []string{"rs", "cs", "swift"}})
+	assert.Equal(t, []string{
+		"The name Which of variant Which of T in Rust collides with variant which of T at (Row 2, Col 9)",
+		"The name id of field Id of User in Rust collides with field id of User at (Row 4, Col 13)",
+		"The name user_id of field userID of User in Rust collides with field userId of User at (Row 4, Col 29)",
+		"The name TWhich of variant Which of T in C# collides with variant which of T at (Row 2, Col 9)",
+		"The name Id of field Id of User in C# collides with field id of User at (Row 4, Col 13)",
+		"The name A of variant A of Level in C# collides with variant a of Level at (Row 6, Col 16)",
+		"The name which of variant Which of T in Swift collides with variant which of T at (Row 2, Col 9)",
+		"The name a of variant A of Level in Swift collides with variant a of Level at (Row 6, Col 16)",
+	}, errorMessages(errs))
+
+	_, _, errs = TranslateFiles("", files, Options{Targets: []string{"ts"}})
+	assert.Equal(t, 0, len(errorMessages(errs)))
+}
+
+func TestTranslateGeneratedNamesAcrossFiles(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"":     `import "./base" prod User extends Base { Name Str, }`,
+		"base": `prod Base { name Str, }`,
+	})
+	_, _, errs := TranslateFiles("", files, Options{Targets: []string{"cs"}})
+	assert.Equal(t, []string{
+		"The name Name of field Name of User in C# collides with field name of Base at (Row 1, Col 13) in base",
+	}, errorMessages(errs))
+}
+
+func TestTranslateWarnings(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `prod user { UserId Str, Status, }
+sumstr Status { active, Done, }
+sumint level { Low, }
+prod Account { userId Str, userID "userid" Str, name "NAME" Str, nick "name" Str, }
+sum Result { ok Str, Ok "OK" Str, }`,
+	})
+	_, _, errs := TranslateFiles("", files, Options{})
+	assert.Equal(t, 0, len(errorMessages(errs)))
+	assert.Equal(t, []string{
+		"Type name user should be PascalCase, User",
+		"Field name UserId should be camelCase, userId",
+		"Variant name active should be PascalCase, Active",
+		"Type name level should be PascalCase, Level",
+		"The JSON name userid of userID differs only in case from userId of userId at (Row 4, Col 16), Go cannot tell them apart when decoding",
+		"The JSON name name of nick differs only in case from NAME of name at (Row 4, Col 54), Go cannot tell them apart when decoding",
+		"Field name Ok should be camelCase, ok",
+		"The JSON name OK of Ok differs only in case from ok of ok at (Row 5, Col 14), Go cannot tell them apart when decoding",
+	}, warningMessages(errs))
+	assert.True(t, IsWarning(errs[0]))
+	assert.Equal(t, "Warning at (Row 1, Col 6): Type name user should be PascalCase, User", errs[0].Error())
+}
+
+func TestTranslateWireNames(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"api.bt": `import "common.bt"
+prod Order extends Base { a "x" Str, x Int, createdAt "id" Str, }
+sum Event { a "b" Str, b Int, }
+sumstr Status { Pending "pending activation", Waiting "pending activation", Done, }`,
+		"common.bt": `prod Base { id Str, }`,
+	})
+	_, _, errs := TranslateFiles("api.bt", files, Options{})
+	assert.Equal(t, []string{
+		"Duplicated JSON name x of x, already used by a at (Row 2, Col 29)",
+		"Duplicated JSON name id of createdAt, already used by id of Base at (Row 1, Col 13) in common.bt",
+		"Duplicated JSON name b of b, already used by a at (Row 3, Col 15)",
+		"Duplicated JSON value pending activation of Waiting, already used by Pending at (Row 4, Col 25)",
+	}, errorMessages(errs))
+}
+
+func TestTranslateGoJsonNames(t *testing.T) {
+	files := parseFiles(t, map[string]string{
+		"": `prod User { name "a\"b" Str, email "" Str, id "$id" Str, }
+sum Event @tag("k,v") { a "x\\y" Data, }
+prod Data {}`,
+	})
+	// Names which cannot be struct tags are encoded by hand in Go
+	_, _, errs := TranslateFiles("", files, Options{Targets: []string{"go"}})
+	assert.Equal(t, 0, len(errorMessages(errs)))
+}
@@ -0,0 +1,107 @@
+package translate
+
+import (
+	"fmt"
+
+	"github.com/brahms116/between/internal/lex"
+	"github.com/brahms116/between/internal/st"
+)
+
+// wireName is the key of a field or the value of a sumstr variant in JSON.
+type wireName struct {
+	name string
+	// the field or variant
+	id string
+	// the product the field is inherited from, if any
+	owner string
+	path  string
+	loc   lex.Location
+}
+
+func (w wireName) describe(path string) string {
+	s := w.id
+	if w.owner != "" {
+		s += " of " + w.owner
+	}
+	s += " at " + w.loc.Start.String()
+	if w.path != path {
+		s += " in " + w.path
+	}
+	return s
+}
+
+// fieldWireNames are the keys of the fields in JSON.
+func fieldWireNames(fields []st.Field, owner string, path string) []wireName {
+	var names []wireName
+	for _, f := range fields {
+		id := fieldName(f)
+		if id == "" {
+			continue
+		}
+		names = append(names, wireName{name: fieldWireName(f), id: id, owner: owner, path: path, loc: jsonNameLoc(f)})
+	}
+	return names
+}
+
+// productWireNames are the keys of the fields of a product in JSON, the fields
+// it inherits first.
+func (t *translate) productWireNames(p st.Product) []wireName {
+	var ancestors []st.Product
+	seen := map[string]struct{}{p.Id.Value: {}}
+	for ancestor := t.extendedProduct(p); ancestor != nil; ancestor = t.extendedProduct(*ancestor) {
+		if _, ok := seen[ancestor.Id.Value]; ok {
+			// Extends cycles are reported
+			break
+		}
+		seen[ancestor.Id.Value] = struct{}{}
+		ancestors = append(ancestors, *ancestor)
+	}
+
+	var names []wireName
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		sym, _ := t.symbols.getSymbol(ancestors[i].Id.Value)
+		names = append(names, fieldWireNames(ancestors[i].Fields, ancestors[i].Id.Value, sym.path)...)
+	}
+	return append(names, fieldWireNames(p.Fields, "", t.path)...)
+}
+
+// checkWireNames reports fields or variants sharing a JSON name, as one would
+// be lost when encoding. Ones sharing their name as well are already reported
+// as duplicated, and inherited fields sharing a JSON name are reported on the
+// product they are declared in.
+func (t *translate) checkWireNames(names []wireName, kind string) {
+	existing := make(map[string]wireName)
+	for _, w := range names {
+		other, ok := existing[w.name]
+		if !ok {
+			existing[w.name] = w
+			continue
+		}
+		if other.id != w.id && w.owner == "" {
+			t.addError(fmt.Sprintf("Duplicated JSON %s %s of %s, already used by %s", kind, w.name, w.id, other.describe(t.path)), w.loc)
+		}
+	}
+}
+
+func sumStrVariantWireName(v st.SumStrVariant) string {
+	if v.JsonName != nil {
+		return v.JsonName.Value
+	}
+	return v.Id.Value
+}
+
+func sumStrVariantWireNameLoc(v st.SumStrVariant) lex.Location {
+	if v.JsonName != nil {
+		return v.JsonName.Loc
+	}
+	return v.Id.Loc
+}
+
+// jsonNameLoc is the location of the JSON name of a field, or of its name when
+// it is not renamed.
+func jsonNameLoc(f st.Field) lex.Location {
+	if f.FieldFull != nil && f.FieldFull.JsonName != nil {
+		return f.FieldFull.JsonName.Loc
+	}
+	return f.Id().Loc
+}